@@ -0,0 +1,135 @@
+/*
+Command zmanim-compare compares the zmanim calculated by two or more calculator.AstronomicalCalculator
+implementations over a date range and a set of locations, and prints the maximum and mean divergence, the dates of
+the worst divergence and the dates on which only one of the calculators could calculate the zman.
+
+Usage:
+
+	zmanim-compare -from 2024-01-01 -to 2024-12-31 -calculators noaa,suntimes -zmanim sunrise,sunset \
+		-location "Lakewood, NJ;40.0721087;-74.2400243;15;America/New_York" -format table
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/compare"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+locationsFlag a repeatable flag of locations in the format name;latitude;longitude;elevation;timezone
+*/
+type locationsFlag []calculator.GeoLocation
+
+func (t *locationsFlag) String() string {
+	var names []string
+	for _, geoLocation := range *t {
+		names = append(names, geoLocation.LocationName())
+	}
+	return strings.Join(names, ", ")
+}
+
+func (t *locationsFlag) Set(value string) error {
+	fields := strings.Split(value, ";")
+	if len(fields) != 5 {
+		return fmt.Errorf("location %q is not name;latitude;longitude;elevation;timezone", value)
+	}
+	latitude, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return fmt.Errorf("invalid latitude %q", fields[1])
+	}
+	longitude, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return fmt.Errorf("invalid longitude %q", fields[2])
+	}
+	elevation, err := strconv.ParseFloat(fields[3], 64)
+	if err != nil || elevation < 0 {
+		return fmt.Errorf("invalid elevation %q", fields[3])
+	}
+	timeZone, err := time.LoadLocation(fields[4])
+	if err != nil {
+		return err
+	}
+	*t = append(*t, calculator.NewGeoLocation2(fields[0], latitude, longitude, dimension.Meters(elevation), timeZone))
+	return nil
+}
+
+func parseDate(s string) (gdt.GDate, error) {
+	tm, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return gdt.GDate{}, err
+	}
+	return gdt.NewGDate1(tm), nil
+}
+
+func run() error {
+	var locations locationsFlag
+	from := flag.String("from", time.Now().Format("2006-01-02"), "first date, YYYY-MM-DD")
+	to := flag.String("to", time.Now().AddDate(0, 0, 364).Format("2006-01-02"), "last date, YYYY-MM-DD")
+	calculators := flag.String("calculators", "noaa,suntimes", "comma separated calculators: "+strings.Join(compare.CalculatorNames(), ", "))
+	zmanim := flag.String("zmanim", "sunrise,sunset", "comma separated zmanim")
+	format := flag.String("format", "table", "output format: table or json")
+	flag.Var(&locations, "location", "location name;latitude;longitude;elevation;timezone, can be repeated (default Lakewood, NJ and Jerusalem)")
+	flag.Parse()
+
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("unknown format %q, use table or json", *format)
+	}
+
+	var request compare.Request
+	var err error
+	if request.From, err = parseDate(*from); err != nil {
+		return err
+	}
+	if request.To, err = parseDate(*to); err != nil {
+		return err
+	}
+	if request.From.ToTime(time.UTC).After(request.To.ToTime(time.UTC)) {
+		return fmt.Errorf("from %s is after to %s", *from, *to)
+	}
+
+	for _, name := range strings.Split(*calculators, ",") {
+		newCalculator, ok := compare.Calculators[strings.TrimSpace(name)]
+		if !ok {
+			return fmt.Errorf("unknown calculator %q, use one of %s", name, strings.Join(compare.CalculatorNames(), ", "))
+		}
+		request.Calculators = append(request.Calculators, newCalculator())
+	}
+	if len(request.Calculators) < 2 {
+		return fmt.Errorf("at least 2 calculators are required")
+	}
+
+	for _, name := range strings.Split(*zmanim, ",") {
+		zman, ok := compare.ZmanByName(strings.TrimSpace(name))
+		if !ok {
+			return fmt.Errorf("unknown zman %q", name)
+		}
+		request.Zmanim = append(request.Zmanim, zman)
+	}
+
+	request.GeoLocations = locations
+	if len(request.GeoLocations) == 0 {
+		request.GeoLocations = []calculator.GeoLocation{calculator.LakewoodGeoLocation(), calculator.JerusalemGeoLocation()}
+	}
+
+	report := compare.Compare(request)
+
+	if *format == "json" {
+		return report.WriteJSON(os.Stdout)
+	}
+	return report.WriteTable(os.Stdout)
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
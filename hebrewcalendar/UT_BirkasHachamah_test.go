@@ -0,0 +1,54 @@
+package hebrewcalendar
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/jdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"testing"
+)
+
+func TestBirkasHachamahDates(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the date moves a day later in the Gregorian calendar after the years 1800, 1900 and 2100 that are not leap years
+	expected := []gdt.GDate{
+		gdt.NewGDate(1785, 4, 6),
+		gdt.NewGDate(1813, 4, 7),
+		gdt.NewGDate(1841, 4, 7),
+		gdt.NewGDate(1869, 4, 7),
+		gdt.NewGDate(1897, 4, 7),
+		gdt.NewGDate(1925, 4, 8),
+		gdt.NewGDate(1953, 4, 8),
+		gdt.NewGDate(1981, 4, 8),
+		gdt.NewGDate(2009, 4, 8),
+		gdt.NewGDate(2037, 4, 8),
+		gdt.NewGDate(2065, 4, 8),
+		gdt.NewGDate(2093, 4, 8),
+		gdt.NewGDate(2121, 4, 9),
+	}
+
+	dates := BirkasHachamahDates(gdt.NewGDate(1785, 4, 6), gdt.NewGDate(2121, 4, 9))
+	assert.Equal(t, tag, expected, dates)
+
+	for _, date := range dates {
+		assert.True(t, tag, NewJewishCalendar(NewJewishDate2(date)).IsBirkasHachamah())
+	}
+
+	// Erev Pesach 5769
+	assert.Equal(t, tag, jdt.NewJDate(5769, jdt.Nissan, 14), NewJewishDate2(gdt.NewGDate(2009, 4, 8)).JDate())
+
+	assert.Equal(t, tag, 0, len(BirkasHachamahDates(gdt.NewGDate(2009, 4, 9), gdt.NewGDate(2037, 4, 7))))
+}
+
+func TestNextAndPreviousBirkasHachamah(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	assert.Equal(t, tag, gdt.NewGDate(2037, 4, 8), NextBirkasHachamah(gdt.NewGDate(2009, 4, 8)))
+	assert.Equal(t, tag, gdt.NewGDate(2009, 4, 8), NextBirkasHachamah(gdt.NewGDate(2009, 4, 7)))
+	assert.Equal(t, tag, gdt.NewGDate(2037, 4, 8), NextBirkasHachamah(gdt.NewGDate(2024, 1, 1)))
+
+	assert.Equal(t, tag, gdt.NewGDate(1981, 4, 8), PreviousBirkasHachamah(gdt.NewGDate(2009, 4, 8)))
+	assert.Equal(t, tag, gdt.NewGDate(2009, 4, 8), PreviousBirkasHachamah(gdt.NewGDate(2009, 4, 9)))
+	assert.Equal(t, tag, gdt.NewGDate(2009, 4, 8), PreviousBirkasHachamah(gdt.NewGDate(2024, 1, 1)))
+}
@@ -1,7 +1,11 @@
 package hebrewcalendar
 
 import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/jdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
 	"testing"
+	"time"
 )
 
 /*
@@ -21,3 +25,31 @@ func Test1111(t *testing.T) {
 	// assert.Equal(t, tag, jDate, subject.JDate())
 
 }
+
+func TestMoladAsDate(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject := NewJewishCalendar(NewJewishDate1(jdt.NewJDate(5778, 5, 1)))
+
+	molad := subject.JewishDate().Molad()
+	assert.Equal(t, tag, jdt.NewMoladTime(6, 49, 8), molad.MoladTime())
+
+	// 06:49 and 8 chalakim in Jerusalem standard time less the local mean time offset of Har Habayis
+	expected := time.Date(2018, 7, 13, 4, 49, 26, 666666666, time.UTC).Add(-(20*time.Minute + 56496*time.Millisecond))
+	assert.Equal(t, tag, expected, subject.MoladAsDate().UTC())
+}
+
+func TestKidushLevanaTimes(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject := NewJewishCalendar(NewJewishDate1(jdt.NewJDate(5778, 5, 1)))
+	molad := subject.MoladAsDate()
+
+	assert.Equal(t, tag, molad.Add(3*24*time.Hour), subject.TchilasZmanKidushLevana3Days())
+	assert.Equal(t, tag, molad.Add(7*24*time.Hour), subject.TchilasZmanKidushLevana7Days())
+	assert.Equal(t, tag, molad.Add(15*24*time.Hour), subject.SofZmanKidushLevana15Days())
+
+	// half of the 29 days, 12 hours and 793 chalakim between molad and molad
+	next := NewJewishCalendar(NewJewishDate1(jdt.NewJDate(5778, 6, 1))).MoladAsDate()
+	assert.True(t, tag, (next.Sub(molad)/2-subject.SofZmanKidushLevanaBetweenMoldos().Sub(molad)).Abs() < time.Millisecond)
+}
@@ -0,0 +1,72 @@
+package hebrewcalendar
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/jdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"testing"
+	"time"
+)
+
+func TestTekufosShmuel(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// Birkas Hachamah 5769, tekufas Nissan at the start of Wednesday (6 PM Tuesday)
+	tekufos := Tekufos(5769, TekufasShmuel)
+	nissan := tekufos[TekufasNissan]
+	assert.Equal(t, tag, TekufasNissan, nissan.Season)
+	assert.Equal(t, tag, TekufasShmuel, nissan.Opinion)
+	assert.Equal(t, tag, gdt.NewGDateTime(gdt.NewGDate(2009, 4, 7), gdt.NewGTime(18, 0, 0, 0)), nissan.JerusalemMeanTime)
+	assert.Equal(t, tag, time.Date(2009, 4, 7, 15, 39, 3, 504000000, time.UTC), nissan.Time.UTC())
+
+	// the tekufos are 91D and 7.5H apart
+	for season := TekufasTishrei; season < TekufasTammuz; season++ {
+		assert.Equal(t, tag, 91*24*time.Hour+7*time.Hour+30*time.Minute, tekufos[season+1].Time.Sub(tekufos[season].Time))
+	}
+
+	tishrei := Tekufos(5785, TekufasShmuel)[TekufasTishrei]
+	assert.Equal(t, tag, gdt.NewGDateTime(gdt.NewGDate(2024, 10, 7), gdt.NewGTime(3, 0, 0, 0)), tishrei.JerusalemMeanTime)
+
+	// Vesein Tal Umatar outside of Israel starts 60 days after tekufas Tishrei counting both days, which is 47D after
+	// Rosh Hashana year 1 by TekufasTishreiElapsedDays
+	startDate := gdt.NewGDate1(tishrei.JerusalemMeanTime.D.ToTime(time.UTC).AddDate(0, 0, 59))
+	assert.Equal(t, tag, gdt.NewGDate(2024, 12, 5), startDate)
+	assert.Equal(t, tag, int32(47), NewJewishCalendar(NewJewishDate2(startDate)).TekufasTishreiElapsedDays())
+}
+
+func TestTekufosRavAdda(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the 19 years of Rav Adda are exactly 235 months, the tekufah is the same time after the molad in every cycle
+	moladOffset := func(year jdt.JYear) time.Duration {
+		molad := NewJewishCalendar(NewJewishDate1(jdt.NewJDate(year, jdt.Nissan, 1))).MoladAsDate()
+		return Tekufos(year, TekufasRavAdda)[TekufasNissan].Time.Sub(molad)
+	}
+	assert.True(t, tag, (moladOffset(5784)-moladOffset(5784+19)).Abs() < time.Millisecond)
+
+	nissan := Tekufos(5784, TekufasRavAdda)[TekufasNissan]
+	assert.Equal(t, tag, gdt.NewGDate(2024, 3, 27), nissan.JerusalemMeanTime.D)
+
+	// Rav Adda's year is shorter than Shmuel's, his tekufah is more than 11 days before Shmuel's in 5784
+	assert.True(t, tag, Tekufos(5784, TekufasShmuel)[TekufasNissan].Time.Sub(nissan.Time) > 11*24*time.Hour)
+}
+
+func TestTekufahWarningWindow(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	tekufah := Tekufos(5784, TekufasShmuel)[TekufasTammuz]
+	from, to := tekufah.WarningWindow(TekufahWarningMargin)
+	assert.Equal(t, tag, tekufah.Time.Add(-30*time.Minute), from)
+	assert.Equal(t, tag, tekufah.Time.Add(30*time.Minute), to)
+
+	defer assert.Raises(t, tag)()
+	tekufah.WarningWindow(-time.Minute)
+}
+
+func TestTekufosUnknownOpinion(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	defer assert.Raises(t, tag)()
+	Tekufos(5784, TekufahOpinion(2))
+}
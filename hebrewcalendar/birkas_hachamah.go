@@ -0,0 +1,61 @@
+package hebrewcalendar
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/jdt"
+)
+
+const (
+	// birkasHachamahCycle the 28 years of 365.25 days of Tekufas Shmuel in days
+	birkasHachamahCycle = 10227
+	// birkasHachamahOffset the elapsed days since molad tohu of the first Birkas Hachamah, see JewishCalendar.IsBirkasHachamah
+	birkasHachamahOffset = 172
+)
+
+/*
+birkasHachamahDaysIntoCycle returns the days of the gDate since the last Birkas Hachamah, 0 on Birkas Hachamah.
+*/
+func birkasHachamahDaysIntoCycle(gDate gdt.GDate) gdt.GDay {
+	days := (gDate.ToAbsDate() - jdt.JewishEpoch - birkasHachamahOffset) % birkasHachamahCycle
+	if days < 0 {
+		days += birkasHachamahCycle
+	}
+	return days
+}
+
+/*
+NextBirkasHachamah returns the date of the first Birkas Hachamah after the gDate, see JewishCalendar.IsBirkasHachamah.
+*/
+func NextBirkasHachamah(gDate gdt.GDate) gdt.GDate {
+	return gdt.NewGDate2(gDate.ToAbsDate() + birkasHachamahCycle - birkasHachamahDaysIntoCycle(gDate))
+}
+
+/*
+PreviousBirkasHachamah returns the date of the last Birkas Hachamah before the gDate, see JewishCalendar.IsBirkasHachamah.
+*/
+func PreviousBirkasHachamah(gDate gdt.GDate) gdt.GDate {
+	days := birkasHachamahDaysIntoCycle(gDate)
+	if days == 0 {
+		days = birkasHachamahCycle
+	}
+	return gdt.NewGDate2(gDate.ToAbsDate() - days)
+}
+
+/*
+BirkasHachamahDates returns the dates of Birkas Hachamah from the date from to the date to inclusive, such as the
+historical list of Birkas Hachamah to validate the calculation against.
+*/
+func BirkasHachamahDates(from gdt.GDate, to gdt.GDate) []gdt.GDate {
+	var dates []gdt.GDate
+
+	date := from
+	if birkasHachamahDaysIntoCycle(from) != 0 {
+		date = NextBirkasHachamah(from)
+	}
+	for date.ToAbsDate() <= to.ToAbsDate() {
+		dates = append(dates, date)
+		date = NextBirkasHachamah(date)
+	}
+
+	return dates
+}
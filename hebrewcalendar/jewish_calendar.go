@@ -753,40 +753,36 @@ method intentionally uses standard time and not daylight savings time. Java will
 default (or set) Timezone.
 */
 func (t *jewishCalendar) MoladAsDate() time.Time {
-	/*	molad := t.Molad()
-		locationName := "Jerusalem, Israel"
-
-		latitude := 31.778 // Har Habayis latitude
-		longitude := 35.2354 // Har Habayis longitude
-
-		// The raw molad Date (point in time) must be generated using standard time. Using "Asia/Jerusalem" timezone will result in the time
-		// being incorrectly off by an hour in the summer due to DST. Proper adjustment for the actual time in DST will be done by the date
-		// formatter class used to display the Date.
-		// TimeZone yerushalayimStandardTZ = TimeZone.getTimeZone("GMT+2");
-		yerushalayimStandardTZ, err := time.LoadLocation("GMT+2")
-		if err != nil {
-			panic(err)
-		}
+	molad := t.jewishDate.Molad()
 
-		geo := util.NewGeoLocation1(locationName, latitude, longitude, yerushalayimStandardTZ)
-	*/
+	moladTime := molad.MoladTime()
+	// a chelek is 10/3 seconds
+	moladNanoseconds := time.Duration(moladTime.Chalakim) * 10 * time.Second / 3
 
-	/*
-		Calendar cal = Calendar.getInstance(geo.getTimeZone());
+	moladDate := time.Date(int(molad.GYear()), molad.GMonth(), int(molad.GDay()), int(moladTime.Hours), int(moladTime.Minutes), 0, 0, yerushalayimStandardTZ).
+		Add(moladNanoseconds)
 
-		cal.clear()
+	// subtract local time difference of 20.94 minutes (20 minutes and 56.496 seconds) to get to Standard time
+	return moladDate.Add(-harHabayisLocalMeanTimeOffset)
+}
 
-		double moladSeconds = molad.getMoladChalakim() * 10 / (double) 3;
+/*
+yerushalayimStandardTZ the standard time of Yerushalayim. The raw molad Date (point in time) must be generated using
+standard time. Using "Asia/Jerusalem" timezone will result in the time being incorrectly off by an hour in the summer
+due to DST.
+*/
+var yerushalayimStandardTZ = time.FixedZone("GMT+2", 2*60*60)
 
-		cal.set(molad.getGregorianYear(), molad.getGregorianMonth(), molad.getGregorianDayOfMonth(), molad.getMoladHours(), molad.getMoladMinutes(), (int) moladSeconds);
-		cal.set(Calendar.MILLISECOND, (int) (1000 * (moladSeconds - (int) moladSeconds)));
-		// subtract local time difference of 20.94 minutes (20 minutes and 56.496 seconds) to get to Standard time
-		cal.add(Calendar.MILLISECOND, -1 * (int) geo.getLocalMeanTimeOffset());
+/*
+harHabayisLongitude the longitude of Har Habayis the traditional molad calculation is based on.
+*/
+const harHabayisLongitude = 35.2354
 
-		return cal.getTime();
-	*/
-	panic("implement me")
-}
+/*
+harHabayisLocalMeanTimeOffset the difference between the local mean time of Har Habayis (5.2354 deg east of the 30 deg
+longitude of the GMT+2 timezone) and the standard time, 20 minutes and 56.496 seconds.
+*/
+const harHabayisLocalMeanTimeOffset = time.Duration((harHabayisLongitude - 30) * 4 * float64(time.Minute))
 
 /*
 TchilasZmanKidushLevana3Days returns the earliest time of Kiddush Levana calculated as 3 days after the molad. This method returns the time
@@ -794,13 +790,7 @@ even if it is during the day when Kiddush Levana can't be said. Callers of this
 displaying the next tzais if the zman is between alos and tzais.
 */
 func (t *jewishCalendar) TchilasZmanKidushLevana3Days() time.Time {
-	/*Date molad = getMoladAsDate();
-	  Calendar cal = Calendar.getInstance();
-	  cal.setTime(molad);
-	  cal.add(Calendar.HOUR, 72); // 3 days after the molad
-	  return cal.getTime();
-	*/
-	panic("implement me")
+	return t.MoladAsDate().Add(72 * time.Hour) // 3 days after the molad
 }
 
 /*
@@ -812,13 +802,7 @@ This method returns the time
 - displaying the next tzais if the zman is between alos and tzais.
 */
 func (t *jewishCalendar) TchilasZmanKidushLevana7Days() time.Time {
-	/*Date molad = getMoladAsDate();
-	  Calendar cal = Calendar.getInstance();
-	  cal.setTime(molad);
-	  cal.add(Calendar.HOUR, 168); // 7 days after the molad
-	  return cal.getTime();
-	*/
-	panic("implement me")
+	return t.MoladAsDate().Add(168 * time.Hour) // 7 days after the molad
 }
 
 /*
@@ -831,19 +815,10 @@ recited. Callers of this method should consider displaying alos before this time
 between alos and tzais.
 */
 func (t *jewishCalendar) SofZmanKidushLevanaBetweenMoldos() time.Time {
-	/*Date molad = getMoladAsDate();
-	  Calendar cal = Calendar.getInstance();
-	  cal.setTime(molad);
-	  // add half the time between molad and molad (half of 29 days, 12 hours and 793 chalakim (44 minutes, 3.3
-	  // seconds), or 14 days, 18 hours, 22 minutes and 666 milliseconds). Add it as hours, not days, to avoid
-	  // DST/ST crossover issues.
-	  cal.add(Calendar.HOUR, (24 * 14) + 18);
-	  cal.add(Calendar.MINUTE, 22);
-	  cal.add(Calendar.SECOND, 1);
-	  cal.add(Calendar.MILLISECOND, 666);
-	  return cal.getTime();
-	*/
-	panic("implement me")
+	// add half the time between molad and molad (half of 29 days, 12 hours and 793 chalakim (44 minutes, 3.3
+	// seconds), or 14 days, 18 hours, 22 minutes and 666 milliseconds). Add it as hours, not days, to avoid
+	// DST/ST crossover issues.
+	return t.MoladAsDate().Add((24*14+18)*time.Hour + 22*time.Minute + 1*time.Second + 666*time.Millisecond)
 }
 
 /*
@@ -859,13 +834,7 @@ the day when Kiddush Levana can't be said. Callers of this method should conside
 before this time if the zman is between alos and tzais.
 */
 func (t *jewishCalendar) SofZmanKidushLevana15Days() time.Time {
-	/*Date molad = getMoladAsDate();
-	  Calendar cal = Calendar.getInstance();
-	  cal.setTime(molad);
-	  cal.add(Calendar.HOUR, 24 * 15); //15 days after the molad. Add it as hours, not days, to avoid DST/ST crossover issues.
-	  return cal.getTime();
-	*/
-	panic("implement me")
+	return t.MoladAsDate().Add(24 * 15 * time.Hour) // 15 days after the molad. Add it as hours, not days, to avoid DST/ST crossover issues.
 }
 
 /*
@@ -0,0 +1,131 @@
+package hebrewcalendar
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/jdt"
+	"math"
+	"time"
+)
+
+/*
+TekufahOpinion the opinion the length of the solar year of the tekufos is calculated with.
+*/
+type TekufahOpinion int
+
+const (
+	/*
+		TekufasShmuel the opinion of Shmuel that the solar year is 365.25 days (identical to the
+		[Julian Year]: https://en.wikipedia.org/wiki/Julian_year_(astronomy)). Tekufas Nissan of year 1 was 7D, 9H and
+		642C before molad Nissan. This is the opinion used for Vesein Tal Umatar and Birkas Hachamah.
+	*/
+	TekufasShmuel TekufahOpinion = iota
+	/*
+		TekufasRavAdda the opinion of Rav Adda bar Ahava that the solar year is 365D, 5H, 997C and 48 regaim. Tekufas Nissan
+		of year 1 was 9H and 642C before molad Nissan.
+	*/
+	TekufasRavAdda
+)
+
+/*
+TekufahSeason the season of a tekufah.
+*/
+type TekufahSeason int
+
+const (
+	// TekufasTishrei the autumn tekufah, corresponding to the September equinox
+	TekufasTishrei TekufahSeason = iota
+	// TekufasTeves the winter tekufah, corresponding to the December solstice
+	TekufasTeves
+	// TekufasNissan the spring tekufah, corresponding to the March equinox
+	TekufasNissan
+	// TekufasTammuz the summer tekufah, corresponding to the June solstice
+	TekufasTammuz
+)
+
+/*
+TekufahWarningMargin the customary margin before and after the tekufah hour when water is not drunk, see
+Tekufah.WarningWindow. Some are stringent for an hour.
+*/
+const TekufahWarningMargin = 30 * time.Minute
+
+/*
+Tekufah a tekufah (season) of a Jewish year.
+*/
+type Tekufah struct {
+	Season  TekufahSeason
+	Opinion TekufahOpinion
+	// JerusalemMeanTime the date and the time of the tekufah in the local mean time of Yerushalayim, as traditionally
+	// published
+	JerusalemMeanTime gdt.GDateTime
+	// Time the instant of the tekufah in the Yerushalayim standard time (GMT+2), see time.Time.In to convert it to
+	// another time zone
+	Time time.Time
+}
+
+/*
+WarningWindow returns the time from the margin before to the margin after the tekufah (see TekufahWarningMargin).
+*/
+func (t Tekufah) WarningWindow(margin time.Duration) (from time.Time, to time.Time) {
+	if margin < 0 {
+		panic("margin < 0")
+	}
+	return t.Time.Add(-margin), t.Time.Add(margin)
+}
+
+const (
+	chalakimPerDay  = 25920
+	chalakimPerHour = 1080
+	// the solar year of Shmuel, 365.25 days
+	shmuelYearChalakim = 365.25 * chalakimPerDay
+	// the solar year of Rav Adda, 365D, 5H, 997C and 48 regaim (76 regaim in a chelek)
+	ravAddaYearChalakim = 365*chalakimPerDay + 5*chalakimPerHour + 997 + 48.0/76
+)
+
+/*
+Tekufos returns the 4 tekufos of the Jewish year passed in (Tishrei, Teves, Nissan and Tammuz) by the opinion passed in.
+The tekufah is counted from the start of Rosh Hashana year 1 (6 PM of the evening before), see
+JewishCalendar.TekufasTishreiElapsedDays: Tekufas Nissan of year 1 was 170D after it by the opinion of Shmuel and 177D
+after it by the opinion of Rav Adda.
+*/
+func Tekufos(year jdt.JYear, opinion TekufahOpinion) [4]Tekufah {
+	var nissan, yearLength float64
+	switch opinion {
+	case TekufasShmuel:
+		nissan, yearLength = 170*chalakimPerDay, shmuelYearChalakim
+	case TekufasRavAdda:
+		nissan, yearLength = 177*chalakimPerDay, ravAddaYearChalakim
+	default:
+		panic("unknown TekufahOpinion")
+	}
+	nissan += float64(year-1) * yearLength
+
+	var tekufos [4]Tekufah
+	for season := TekufasTishrei; season <= TekufasTammuz; season++ {
+		chalakim := nissan + float64(season-TekufasNissan)*yearLength/4
+		tekufos[season] = newTekufah(season, opinion, chalakim)
+	}
+
+	return tekufos
+}
+
+/*
+newTekufah returns the Tekufah the chalakim passed in after the start of Rosh Hashana year 1.
+*/
+func newTekufah(season TekufahSeason, opinion TekufahOpinion, chalakim float64) Tekufah {
+	days := math.Floor(chalakim / chalakimPerDay)
+	// the Jewish day starts at 6 PM of the evening before
+	rest := time.Duration((chalakim-days*chalakimPerDay)*10/3*float64(time.Second)) - 6*time.Hour
+
+	rhYear1 := jdt.NewJDate(1, jdt.TISHREI, 1)
+	gDate := gdt.NewGDate2(rhYear1.ToAbsDate() + gdt.GDay(days))
+
+	meanTime := gDate.ToTime(time.UTC).Add(rest)
+	standardTime := time.Date(meanTime.Year(), meanTime.Month(), meanTime.Day(), meanTime.Hour(), meanTime.Minute(), meanTime.Second(), meanTime.Nanosecond(), yerushalayimStandardTZ)
+
+	return Tekufah{
+		Season:            season,
+		Opinion:           opinion,
+		JerusalemMeanTime: gdt.NewGDateTime1(meanTime),
+		Time:              standardTime.Add(-harHabayisLocalMeanTimeOffset),
+	}
+}
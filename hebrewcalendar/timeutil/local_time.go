@@ -0,0 +1,105 @@
+package timeutil
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+LocalTimeKind the kind of the wall clock time of a time zone on the days of the daylight saving time transitions.
+*/
+type LocalTimeKind int
+
+const (
+	// LocalTimeUnique the wall clock time that occurs once
+	LocalTimeUnique LocalTimeKind = iota
+	// LocalTimeRepeated the wall clock time that occurs twice, in the hour repeated when the clocks are set back
+	LocalTimeRepeated
+	// LocalTimeSkipped the wall clock time that doesn't occur, in the hour skipped when the clocks are set forward
+	LocalTimeSkipped
+)
+
+func (k LocalTimeKind) String() string {
+	switch k {
+	case LocalTimeUnique:
+		return "LocalTimeUnique"
+	case LocalTimeRepeated:
+		return "LocalTimeRepeated"
+	case LocalTimeSkipped:
+		return "LocalTimeSkipped"
+	default:
+		return fmt.Sprintf("LocalTimeKind(%d)", int(k))
+	}
+}
+
+/*
+NewLocalTime returns the time of the wall clock time in the loc, nil for GMT, and its LocalTimeKind. Unlike time.Date,
+the choice of the time is defined: the earlier one of the LocalTimeRepeated, and, for the LocalTimeSkipped, the one of
+the offset before the clocks are set forward, which is after the transition, such as 3:30 EDT for 2:30 on the day the
+clocks in New York are set forward from 2:00 to 3:00.
+It presumes a single transition of the loc within a day of the wall clock time.
+*/
+func NewLocalTime(year int, month time.Month, day int, hour int, min int, sec int, nsec int, loc *time.Location) (tm time.Time, kind LocalTimeKind) {
+	loc = TimeZoneOrGmt(loc)
+	// the wall clock time as if it is UTC, the instants of the wall clock time are it minus the offsets of the loc
+	wall := time.Date(year, month, day, hour, min, sec, nsec, time.UTC)
+
+	_, offsetBefore := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, offsetAfter := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	var instants []time.Time
+	for _, offset := range []int{offsetBefore, offsetAfter} {
+		instant := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if _, o := instant.Zone(); o == offset && (len(instants) == 0 || !instant.Equal(instants[0])) {
+			instants = append(instants, instant)
+		}
+	}
+
+	switch len(instants) {
+	case 0:
+		return wall.Add(-time.Duration(offsetBefore) * time.Second).In(loc), LocalTimeSkipped
+	case 1:
+		return instants[0], LocalTimeUnique
+	default:
+		if instants[1].Before(instants[0]) {
+			return instants[1], LocalTimeRepeated
+		}
+		return instants[0], LocalTimeRepeated
+	}
+}
+
+/*
+LocalTimeKindOf returns the LocalTimeKind of the wall clock time of the tm in its location, LocalTimeRepeated if
+another time reads the same, otherwise LocalTimeUnique. A time is never LocalTimeSkipped.
+*/
+func LocalTimeKindOf(tm time.Time) LocalTimeKind {
+	_, kind := NewLocalTime(tm.Year(), tm.Month(), tm.Day(), tm.Hour(), tm.Minute(), tm.Second(), tm.Nanosecond(), tm.Location())
+	return kind
+}
+
+/*
+ZoneTransition returns the transition of the offset of the location of the start at or after the start and before the
+end, such as a daylight saving time transition, and the change of the offset, positive when the clocks are set forward.
+It presumes a single transition, ok is false if the offset doesn't change.
+*/
+func ZoneTransition(start time.Time, end time.Time) (tm time.Time, change time.Duration, ok bool) {
+	loc := start.Location()
+	// the transitions are at the whole seconds, the offsets are of the second before the start and of the last second
+	before, last := start.Truncate(time.Second).Add(-time.Second), end.Add(-time.Nanosecond).Truncate(time.Second)
+	_, offsetBefore := before.In(loc).Zone()
+	_, offsetLast := last.In(loc).Zone()
+	if offsetBefore == offsetLast {
+		return time.Time{}, 0, false
+	}
+
+	// the last is the first second of the offset of the end
+	for last.Sub(before) > time.Second {
+		middle := before.Add(last.Sub(before) / 2).Truncate(time.Second)
+		if _, offset := middle.In(loc).Zone(); offset == offsetBefore {
+			before = middle
+		} else {
+			last = middle
+		}
+	}
+	return last.In(loc), time.Duration(offsetLast-offsetBefore) * time.Second, true
+}
@@ -39,12 +39,19 @@ func GmtTimezoneOrPanic() *time.Location {
 	return LoadLocationOrPanic(GeoLocationNameGMT)
 }
 
-func NewDate(year int, month time.Month, day int, loc *time.Location) time.Time {
-	if loc != nil {
-		return time.Date(year, month, day, 0, 0, 0, 0, loc)
-	} else {
-		return time.Date(year, month, day, 0, 0, 0, 0, GmtTimezoneOrPanic())
+/*
+TimeZoneOrGmt returns the loc, or the GMT time zone if it is nil, as NewDate, for the time zones of the locations that
+are not set.
+*/
+func TimeZoneOrGmt(loc *time.Location) *time.Location {
+	if loc == nil {
+		return GmtTimezoneOrPanic()
 	}
+	return loc
+}
+
+func NewDate(year int, month time.Month, day int, loc *time.Location) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, TimeZoneOrGmt(loc))
 }
 
 func NewDateOfToday(loc *time.Location) time.Time {
@@ -0,0 +1,44 @@
+package zmanim
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/jdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"testing"
+)
+
+func TestNextBirkasHachamah(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	geoLocation := calculator.JerusalemGeoLocation()
+	subject := NextBirkasHachamah(gdt.NewGDate(2024, 1, 1), geoLocation, calculator.NewNOAACalculator())
+
+	assert.Equal(t, tag, gdt.NewGDate(2037, 4, 8), subject.GDate)
+	assert.Equal(t, tag, jdt.NewJDate(5797, jdt.Nissan, 23), subject.JewishDate)
+	assert.Equal(t, tag, len(birkasHachamahOpinions), len(subject.SofZmanTfila))
+
+	zc := NewZmanimCalendar(gdt.NewGDateTime(subject.GDate, gdt.NewGTime0()), geoLocation, calculator.NewNOAACalculator())
+	sofZmanTfilaGRA, _ := zc.SofZmanTfilaGRA()
+	chatzos, _ := zc.Chatzos()
+	for i, zman := range subject.SofZmanTfila {
+		if zman.Opinion == "SofZmanTfilaGRA" {
+			assert.Equal(t, tag, sofZmanTfilaGRA, zman.Time)
+		}
+		if i > 0 {
+			assert.False(t, tag, zman.Time.Before(subject.SofZmanTfila[i-1].Time))
+		}
+		assert.True(t, tag, zman.Time.Before(subject.Chatzos))
+	}
+	assert.Equal(t, tag, chatzos, subject.Chatzos)
+}
+
+func TestPreviousBirkasHachamah(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject := PreviousBirkasHachamah(gdt.NewGDate(2024, 1, 1), calculator.JerusalemGeoLocation(), calculator.NewNOAACalculator())
+
+	assert.Equal(t, tag, gdt.NewGDate(2009, 4, 8), subject.GDate)
+	assert.Equal(t, tag, jdt.NewJDate(5769, jdt.Nissan, 14), subject.JewishDate)
+}
@@ -19,3 +19,54 @@ func TestShaahZmanis19Point8Degrees(t *testing.T) {
 	cal := testNewComplexZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
 	assert.Equal(t, tag, test2to1(cal.ShaahZmanis19Point8Degrees())("cal.ShaahZmanis19Point8Degrees()"), temporalHour(test2to1(cal.Alos19Point8Degrees())("cal.Alos19Point8Degrees"), test2to1(cal.Tzais19Point8Degrees())("cal.Tzais19Point8Degrees")))
 }
+
+func TestSolarMidnight(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	cal := testNewComplexZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
+	sunset := test2to1(cal.SeaLevelSunset())("cal.SeaLevelSunset()")
+	nextSunrise := test2to1(cal.NextDay().SeaLevelSunrise())("cal.NextDay().SeaLevelSunrise()")
+	midnight := test2to1(cal.SolarMidnight())("cal.SolarMidnight()")
+
+	// the middle of the night that follows the date, not of the sunrise and the sunset of the date
+	assert.True(t, tag, midnight.After(sunset) && midnight.Before(nextSunrise))
+	assert.True(t, tag, (midnight.Sub(sunset)-nextSunrise.Sub(midnight)).Abs() < 20*time.Millisecond)
+
+	// the getSolarMidnight of KosherJava, 6 shaos zmaniyos of the night after the sea level sunset 18:13:58.954 EDT (of
+	// TestSeaLevelSunset) to the next sea level sunrise 7:10:55.599 EDT, 12 hours after the solar noon, of the next civil
+	// date
+	assert.Equal(t, tag, gdt.GMillisecond(3884720), temporalHour(sunset, nextSunrise))
+	expected := time.Date(2017, 10, 18, 0, 42, 27, 274000000, calculator.LakewoodGeoLocation().TimeZone())
+	assert.True(t, tag, midnight.Sub(expected).Abs() < time.Millisecond)
+	transit := test2to1(cal.SunTransit())("cal.SunTransit()")
+	assert.True(t, tag, (midnight.Sub(transit)-12*time.Hour).Abs() < 2*time.Minute)
+}
+
+func TestComplexNextDay(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	cal := testNewComplexZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
+	cal.SetUseElevation(true)
+	cal.SetCandleLightingOffset(40)
+	cal.(*complexZmanimCalendar).ateretTorahSunsetOffset = 30
+
+	for _, test := range []struct {
+		zc  ZmanimCalendar
+		day gdt.GDay
+	}{
+		{cal.PreviousDay(), 16},
+		{cal.NextDay(), 18},
+		{cal.NextDay().PreviousDay(), 17},
+	} {
+		next, ok := test.zc.(ComplexZmanimCalendar)
+		assert.True(t, tag, ok)
+		assert.Equal(t, tag, test.day, next.(*complexZmanimCalendar).gDateTime.D.Day)
+		assert.True(t, tag, next.IsUseElevation())
+		assert.Equal(t, tag, cal.CandleLightingOffset(), next.CandleLightingOffset())
+		assert.Equal(t, tag, cal.AteretTorahSunsetOffset(), next.AteretTorahSunsetOffset())
+	}
+
+	// across the end of the year
+	cal = testNewComplexZmanimCalendar(2017, 12, 31, calculator.LakewoodGeoLocation())
+	assert.Equal(t, tag, gdt.GYear(2018), cal.NextDay().(*complexZmanimCalendar).gDateTime.D.Year)
+}
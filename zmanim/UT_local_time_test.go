@@ -0,0 +1,199 @@
+package zmanim
+
+import (
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"testing"
+	"time"
+)
+
+/*
+testTransitions the daylight saving time transitions of 2017 of the US, the EU, Israel and the southern hemisphere.
+Santiago sets the clocks at the midnight, so that its midnight of the day they are set forward doesn't occur.
+*/
+var testTransitions = []struct {
+	geoLocation   calculator.GeoLocation
+	forward, back gdt.GDate
+}{
+	{calculator.LakewoodGeoLocation(), gdt.NewGDate(2017, 3, 12), gdt.NewGDate(2017, 11, 5)},
+	{calculator.NewGeoLocation2("Paris", 48.8566, 2.3522, 0, timeutil.LoadLocationOrPanic("Europe/Paris")), gdt.NewGDate(2017, 3, 26), gdt.NewGDate(2017, 10, 29)},
+	{calculator.NewGeoLocation2("Jerusalem", 31.7781, 35.2338, 0, timeutil.LoadLocationOrPanic("Asia/Jerusalem")), gdt.NewGDate(2017, 3, 24), gdt.NewGDate(2017, 10, 29)},
+	{calculator.NewGeoLocation2("Sydney", -33.8688, 151.2093, 0, timeutil.LoadLocationOrPanic("Australia/Sydney")), gdt.NewGDate(2017, 10, 1), gdt.NewGDate(2017, 4, 2)},
+	{calculator.NewGeoLocation2("Santiago", -33.4489, -70.6693, 0, timeutil.LoadLocationOrPanic("America/Santiago")), gdt.NewGDate(2017, 8, 13), gdt.NewGDate(2017, 5, 13)},
+}
+
+func TestZmanimOnTransitionDays(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	for _, test := range testTransitions {
+		timeZone := test.geoLocation.TimeZone()
+		for _, transition := range []gdt.GDate{test.forward, test.back} {
+			for days := -1; days <= 1; days++ {
+				gDate := addDays(transition, days)
+				cal := NewComplexZmanimCalendar(gdt.NewGDateTime(gDate, gdt.NewGTime0()), test.geoLocation, calculator.NewNOAACalculator())
+				name := tag + " " + test.geoLocation.LocationName() + " " + cal.LocalNoon().Format("2006-01-02")
+
+				localNoon := cal.LocalNoon()
+				assert.Equal(t, name, gDate, gdt.NewGDate1(localNoon))
+				assert.Equal(t, name, 12, localNoon.Hour())
+
+				// the zmanim are of the civil date, in order, also after the local midnight
+				zmanim := []func() (time.Time, bool){cal.Alos72, cal.Hanetz, cal.Chatzos, cal.Shkia, cal.Tzais72}
+				previous := time.Time{}
+				for i, zman := range zmanim {
+					tm := test2to1(zman())(name)
+					assert.True(t, name, tm.After(previous))
+					previous = tm
+					if i < len(zmanim)-1 {
+						assert.Equal(t, name, gDate, gdt.NewGDate1(tm.In(timeZone)))
+					}
+				}
+
+				// the day is of the elapsed time, the transition doesn't add or subtract an hour
+				hanetz := test2to1(cal.Hanetz())(name)
+				shkia := test2to1(cal.Shkia())(name)
+				nextHanetz := test2to1(cal.NextDay().Hanetz())(name)
+				nextShkia := test2to1(cal.NextDay().Shkia())(name)
+				assert.True(t, name, (nextShkia.Sub(nextHanetz)-shkia.Sub(hanetz)).Abs() < 5*time.Minute)
+				assert.True(t, name, (nextHanetz.Sub(hanetz)-24*time.Hour).Abs() < 5*time.Minute)
+
+				midnight := test2to1(cal.SolarMidnight())(name)
+				assert.True(t, name, midnight.After(shkia) && midnight.Before(nextHanetz))
+			}
+		}
+	}
+}
+
+func TestZoneTransition(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	for _, test := range testTransitions {
+		for _, transition := range []struct {
+			gDate  gdt.GDate
+			change time.Duration
+		}{
+			{test.forward, time.Hour},
+			{test.back, -time.Hour},
+		} {
+			cal := NewZmanimCalendar(gdt.NewGDateTime(transition.gDate, gdt.NewGTime0()), test.geoLocation, calculator.NewNOAACalculator())
+			name := tag + " " + test.geoLocation.LocationName()
+
+			tm, change, ok := cal.ZoneTransition()
+			assert.True(t, name, ok)
+			assert.Equal(t, name, transition.change, change)
+			assert.Equal(t, name, transition.gDate, gdt.NewGDate1(tm))
+			_, offsetBefore := tm.Add(-time.Second).Zone()
+			_, offsetAfter := tm.Zone()
+			assert.Equal(t, name, transition.change, time.Duration(offsetAfter-offsetBefore)*time.Second)
+
+			_, _, ok = cal.PreviousDay().ZoneTransition()
+			assert.False(t, name, ok)
+		}
+	}
+}
+
+func TestLocalTimeKind(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	for _, test := range []struct {
+		timeZone    string
+		wall        string
+		kind        timeutil.LocalTimeKind
+		utc         string
+		repeatedUTC string
+	}{
+		{"America/New_York", "2017-03-12 02:30", timeutil.LocalTimeSkipped, "2017-03-12 07:30", ""},
+		{"America/New_York", "2017-11-05 01:30", timeutil.LocalTimeRepeated, "2017-11-05 05:30", "2017-11-05 06:30"},
+		{"Europe/Paris", "2017-03-26 02:30", timeutil.LocalTimeSkipped, "2017-03-26 01:30", ""},
+		{"Europe/Paris", "2017-10-29 02:30", timeutil.LocalTimeRepeated, "2017-10-29 00:30", "2017-10-29 01:30"},
+		{"Asia/Jerusalem", "2017-03-24 02:30", timeutil.LocalTimeSkipped, "2017-03-24 00:30", ""},
+		{"Asia/Jerusalem", "2017-10-29 01:30", timeutil.LocalTimeRepeated, "2017-10-28 22:30", "2017-10-28 23:30"},
+		{"Australia/Sydney", "2017-10-01 02:30", timeutil.LocalTimeSkipped, "2017-09-30 16:30", ""},
+		{"Australia/Sydney", "2017-04-02 02:30", timeutil.LocalTimeRepeated, "2017-04-01 15:30", "2017-04-01 16:30"},
+		{"America/Santiago", "2017-08-13 00:00", timeutil.LocalTimeSkipped, "2017-08-13 04:00", ""},
+		{"America/Santiago", "2017-05-13 23:30", timeutil.LocalTimeRepeated, "2017-05-14 02:30", "2017-05-14 03:30"},
+		{"America/New_York", "2017-11-05 02:30", timeutil.LocalTimeUnique, "2017-11-05 07:30", ""},
+	} {
+		name := tag + " " + test.timeZone + " " + test.wall
+		loc := timeutil.LoadLocationOrPanic(test.timeZone)
+		wall, err := time.Parse("2006-01-02 15:04", test.wall)
+		assert.Equal(t, name, nil, err)
+		utc, err := time.Parse("2006-01-02 15:04", test.utc)
+		assert.Equal(t, name, nil, err)
+
+		tm, kind := timeutil.NewLocalTime(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), 0, 0, loc)
+		assert.Equal(t, name, test.kind, kind)
+		assert.True(t, name, utc.Equal(tm))
+
+		cal := NewZmanimCalendar(gdt.NewGDateTime1(wall), calculator.NewGeoLocation2(test.timeZone, 0, 0, 0, loc), calculator.NewNOAACalculator())
+		switch test.kind {
+		case timeutil.LocalTimeSkipped:
+			// the time after the transition reads an hour later than the wall clock time
+			assert.Equal(t, name, timeutil.LocalTimeUnique, cal.LocalTimeKind(tm))
+			assert.Equal(t, name, wall.Hour()+1, tm.Hour())
+		case timeutil.LocalTimeRepeated:
+			repeatedUTC, err := time.Parse("2006-01-02 15:04", test.repeatedUTC)
+			assert.Equal(t, name, nil, err)
+			assert.Equal(t, name, timeutil.LocalTimeRepeated, cal.LocalTimeKind(tm))
+			assert.Equal(t, name, timeutil.LocalTimeRepeated, cal.LocalTimeKind(repeatedUTC))
+			assert.Equal(t, name, tm.In(loc).Format("15:04"), repeatedUTC.In(loc).Format("15:04"))
+		default:
+			assert.Equal(t, name, timeutil.LocalTimeUnique, cal.LocalTimeKind(tm))
+		}
+	}
+}
+
+func TestNightZmanimInRepeatedHour(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the night from Nov 4 to Nov 5 2017 in Lakewood, the clocks are set back from 2:00 EDT to 1:00 EST, so the night is
+	// an hour longer on the wall clock but not in the elapsed time
+	cal := testNewZmanimCalendar(2017, 11, 4, calculator.LakewoodGeoLocation())
+	shaahZmanis := test2to1(cal.ShaahZmanisNight(NightDefinitionShkiaToHanetz()))("cal.ShaahZmanisNight()")
+	previousShaahZmanis := test2to1(cal.PreviousDay().ShaahZmanisNight(NightDefinitionShkiaToHanetz()))("cal.PreviousDay().ShaahZmanisNight()")
+	assert.True(t, tag, (time.Duration(shaahZmanis-previousShaahZmanis)*time.Millisecond).Abs() < 30*time.Second)
+
+	transition, _, ok := cal.NextDay().ZoneTransition()
+	assert.True(t, tag, ok)
+	for _, hours := range []gdt.GHourH64{6, 7, 8, 9} {
+		tm := test2to1(cal.ShaahZmanisNightBasedZman(NightDefinitionShkiaToHanetz(), hours))("cal.ShaahZmanisNightBasedZman()")
+		repeated := tm.After(transition.Add(-time.Hour)) && tm.Before(transition.Add(time.Hour))
+		assert.Equal(t, tag, repeated, cal.LocalTimeKind(tm) == timeutil.LocalTimeRepeated)
+	}
+}
+
+func TestZmanWithKindOnFallBackNight(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the night from Nov 1 to Nov 2 2025 in New York, the clocks are set back from 2:00 EDT to 1:00 EST
+	newYork := calculator.NewGeoLocation2("New York", 40.7128, -74.0060, 0, timeutil.LoadLocationOrPanic("America/New_York"))
+	cal := testNewZmanimCalendar(2025, 11, 1, newYork)
+
+	for _, test := range []struct {
+		hours gdt.GHourH64
+		wall  string
+		kind  timeutil.LocalTimeKind
+	}{
+		{6, "00:39 EDT", timeutil.LocalTimeUnique},
+		{6.75, "01:30 EDT", timeutil.LocalTimeRepeated},
+		{7.5, "01:21 EST", timeutil.LocalTimeRepeated},
+		{8.5, "02:29 EST", timeutil.LocalTimeUnique},
+	} {
+		name := fmt.Sprintf("%s %v", tag, test.hours)
+		tm, kind, ok := cal.ZmanWithKind(func() (time.Time, bool) {
+			return cal.ShaahZmanisNightBasedZman(NightDefinitionShkiaToHanetz(), test.hours)
+		})
+		assert.True(t, name, ok)
+		assert.Equal(t, name, test.wall, tm.Format("15:04 MST"))
+		assert.Equal(t, name, test.kind, kind)
+	}
+
+	tm, kind, ok := cal.ZmanWithKind(cal.Tzais72)
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, test2to1(cal.Tzais72())(tag), tm)
+	assert.Equal(t, tag, timeutil.LocalTimeUnique, kind)
+}
@@ -0,0 +1,135 @@
+package zmanim
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"testing"
+	"time"
+)
+
+func TestNightBounds(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	cal := testNewZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
+	next := testNewZmanimCalendar(2017, 10, 18, calculator.LakewoodGeoLocation())
+
+	for _, test := range []struct {
+		night      NightDefinition
+		start, end func() (time.Time, bool)
+	}{
+		{NightDefinitionShkiaToHanetz(), cal.Shkia, next.Hanetz},
+		{NightDefinitionTzaisToAlos(), cal.Tzais, next.Alos},
+		{NightDefinitionTzais72ToAlos72(), cal.Tzais72, next.Alos72},
+	} {
+		start, end, ok := cal.NightBounds(test.night)
+		assert.True(t, tag, ok)
+		assert.Equal(t, tag, test2to1(test.start())(test.night.Name), start)
+		assert.Equal(t, tag, test2to1(test.end())(test.night.Name), end)
+
+		shaahZmanis := test2to1(cal.ShaahZmanisNight(test.night))("cal.ShaahZmanisNight()")
+		assert.Equal(t, tag, cal.ShaahZmanis(start, end), shaahZmanis)
+
+		chatzos := test2to1(cal.ChatzosHalayla(test.night))("cal.ChatzosHalayla()")
+		assert.True(t, tag, (chatzos.Sub(start)-end.Sub(chatzos)).Abs() < 20*time.Millisecond)
+		assert.Equal(t, tag, cal.ShaahZmanisBasedZman(start, end, 4), test2to1(cal.SofAshmuraRishona(test.night))("cal.SofAshmuraRishona()"))
+		assert.Equal(t, tag, cal.ShaahZmanisBasedZman(start, end, 8), test2to1(cal.SofAshmuraShniya(test.night))("cal.SofAshmuraShniya()"))
+	}
+
+	// the night in the autumn is longer than the day
+	assert.True(t, tag, test2to1(cal.ShaahZmanisNight(NightDefinitionShkiaToHanetz()))("") > test2to1(cal.ShaahZmanisGRA())(""))
+
+	// chatzos halayla from shkia to hanetz is about 12 hours after the sun transit, at 0:42 EDT
+	chatzos := test2to1(cal.ChatzosHalayla(NightDefinitionShkiaToHanetz()))("cal.ChatzosHalayla()")
+	transit := test2to1(cal.SunTransit())("cal.SunTransit()")
+	assert.True(t, tag, chatzos.Sub(transit.Add(12*time.Hour)).Abs() < 2*time.Minute)
+}
+
+func TestNightBoundsComplex(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the night from the ateret torah offset after the shkia to the offset before the hanetz, of the settings of the
+	// ComplexZmanimCalendar passed to the NightDefinition
+	ateretTorah := NewNightDefinition("ateret torah",
+		func(zc ZmanimCalendar) (time.Time, bool) {
+			czc, ok := zc.(ComplexZmanimCalendar)
+			if !ok {
+				return time.Time{}, false
+			}
+			shkia, ok := czc.Shkia()
+			return shkia.Add(time.Duration(czc.AteretTorahSunsetOffset()) * time.Minute), ok
+		},
+		func(zc ZmanimCalendar) (time.Time, bool) {
+			czc, ok := zc.(ComplexZmanimCalendar)
+			if !ok {
+				return time.Time{}, false
+			}
+			hanetz, ok := czc.Hanetz()
+			return hanetz.Add(-time.Duration(czc.AteretTorahSunsetOffset()) * time.Minute), ok
+		})
+
+	cal := testNewComplexZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
+	cal.(*complexZmanimCalendar).ateretTorahSunsetOffset = 30
+	start, end, ok := cal.NightBounds(ateretTorah)
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, test2to1(cal.Shkia())("cal.Shkia()").Add(30*time.Minute), start)
+	assert.Equal(t, tag, test2to1(cal.NextDay().Hanetz())("cal.NextDay().Hanetz()").Add(-30*time.Minute), end)
+	_, ok = cal.ShaahZmanisNight(ateretTorah)
+	assert.True(t, tag, ok)
+
+	_, _, ok = testNewZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation()).NightBounds(ateretTorah)
+	assert.False(t, tag, ok)
+}
+
+func TestTzaisAfterMidnight(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the tzais of 72 minutes of Rabbeinu Tam after the summer solstice sunset of the high latitudes is after the local
+	// midnight, of the next civil date, and before the chatzos halayla and the hanetz of the next date
+	for _, test := range []struct {
+		geoLocation calculator.GeoLocation
+		tzais72     string
+	}{
+		{calculator.NewGeoLocation2("Helsinki", 60.1699, 24.9384, 0, timeutil.LoadLocationOrPanic("Europe/Helsinki")), "2017-06-22 00:02:03"},
+		{calculator.NewGeoLocation2("Reykjavik", 64.1466, -21.9426, 0, timeutil.LoadLocationOrPanic("Atlantic/Reykjavik")), "2017-06-22 01:15:56"},
+	} {
+		name := tag + " " + test.geoLocation.LocationName()
+		cal := testNewComplexZmanimCalendar(2017, 6, 21, test.geoLocation)
+
+		shkia := test2to1(cal.Shkia())(name)
+		tzais72 := test2to1(cal.Tzais72())(name)
+		assert.Equal(t, name, test.tzais72, tzais72.In(test.geoLocation.TimeZone()).Format("2006-01-02 15:04:05"))
+		assert.Equal(t, name, 72*time.Minute, tzais72.Sub(shkia))
+
+		chatzos := test2to1(cal.ChatzosHalayla(NightDefinitionShkiaToHanetz()))(name)
+		hanetz := test2to1(cal.NextDay().Hanetz())(name)
+		assert.True(t, name, tzais72.Before(chatzos) && chatzos.Before(hanetz))
+	}
+}
+
+func TestNightPolar(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the sun doesn't reach 16.1 deg below the horizon at the summer solstice in London
+	london := calculator.NewGeoLocation1("London, England", 51.5074, -0.1278, timeutil.LoadLocationOrPanic("Europe/London"))
+	cal := testNewZmanimCalendar(2024, 6, 21, london)
+	_, ok := cal.ChatzosHalayla(NightDefinitionTzaisToAlos())
+	assert.False(t, tag, ok)
+	_, ok = cal.ChatzosHalayla(NightDefinitionShkiaToHanetz())
+	assert.True(t, tag, ok)
+}
+
+func TestNightPanics(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	cal := testNewZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
+	func() {
+		defer assert.Raises(t, tag)()
+		cal.ShaahZmanisNightBasedZman(NightDefinitionShkiaToHanetz(), 12.5)
+	}()
+	func() {
+		defer assert.Raises(t, tag)()
+		NewNightDefinition("", ZmanimCalendar.Shkia, nil)
+	}()
+}
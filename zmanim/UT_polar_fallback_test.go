@@ -0,0 +1,211 @@
+package zmanim
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"testing"
+	"time"
+)
+
+func TestPolarFallbackNone(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	dateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 12, 21), gdt.NewGTime0())
+	geoLocation := calculator.JerusalemGeoLocation()
+	subject := NewPolarFallbackCalendar(dateTime, geoLocation, calculator.NewNOAACalculator())
+	cal := NewComplexZmanimCalendar(dateTime, geoLocation, calculator.NewNOAACalculator())
+
+	for _, zman := range []func(zc ComplexZmanimCalendar) (tm time.Time, ok bool){
+		ComplexZmanimCalendar.Sunrise, ComplexZmanimCalendar.Sunset, ComplexZmanimCalendar.Alos19Point8Degrees,
+		ComplexZmanimCalendar.SofZmanShmaGRA, ComplexZmanimCalendar.Tzais72,
+	} {
+		expected, _ := zman(cal)
+		actual, ok := subject.Zman(zman)
+		assert.True(t, tag, ok)
+		assert.Equal(t, tag, expected, actual.Time)
+		assert.Equal(t, tag, PolarFallbackNone, actual.Fallback)
+	}
+}
+
+func TestPolarFallbackPolarNight(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	dateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 12, 21), gdt.NewGTime0())
+	for _, geoLocation := range []calculator.GeoLocation{calculator.ArcticNunavutGeoLocation(), calculator.DaneborgGeoLocation()} {
+		_, ok := NewAstronomicalCalendar(dateTime, geoLocation, calculator.NewNOAACalculator()).Sunrise()
+		assert.False(t, tag, ok)
+
+		subject := NewPolarFallbackCalendar(dateTime, geoLocation, calculator.NewNOAACalculator())
+		for _, strategy := range []PolarFallback{PolarFallbackNearestDay, PolarFallbackNearestLatitude, PolarFallbackFixedLatitude, PolarFallbackSolarNoonMidnight} {
+			subject.SetStrategies(strategy)
+
+			sunrise, ok := subject.Sunrise()
+			assert.True(t, tag, ok)
+			assert.Equal(t, tag, strategy, sunrise.Fallback)
+			sunset, ok := subject.Sunset()
+			assert.True(t, tag, ok)
+			assert.Equal(t, tag, strategy, sunset.Fallback)
+
+			// the times are on the date and the day is around noon
+			assert.Equal(t, tag, dateTime.D, gdt.NewGDate1(sunrise.Time))
+			assert.Equal(t, tag, dateTime.D, gdt.NewGDate1(sunset.Time))
+			assert.False(t, tag, sunset.Time.Before(sunrise.Time))
+
+			chatzos, ok := subject.Zman(ComplexZmanimCalendar.Chatzos)
+			assert.True(t, tag, ok)
+			assert.Equal(t, tag, strategy, chatzos.Fallback)
+		}
+
+		// the sun stays below the horizon, so that sunrise and sunset merge at solar noon
+		subject.SetStrategies(PolarFallbackSolarNoonMidnight)
+		sunrise, _ := subject.Sunrise()
+		noon := calculator.UTCSolarNoon(dateTime, geoLocation)
+		expected := dateTime.D.ToTime(time.UTC).Add(time.Duration(noon * float64(time.Hour)))
+		assert.True(t, tag, sunrise.Time.Sub(expected).Abs() < time.Second)
+	}
+}
+
+func TestPolarFallbackAdjustedZenith(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the sun culminates about 1 deg above the horizon, but an inferior mirage lowering it by 2.5 deg keeps it out of sight
+	dateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 12, 21), gdt.NewGTime0())
+	geoLocation := calculator.NewGeoLocation2("Polar Circle", 65.5, 0, 0, time.UTC)
+	astronomicalCalculator := calculator.NewNOAACalculator().(calculator.AdjustableCalculator)
+	astronomicalCalculator.SetRefractionModel(calculator.RefractionFunc(func(_ dimension.Degrees) dimension.ArcMinutes {
+		return -150
+	}))
+	astronomicalCalculator.SetSolarRadius(0)
+	_, ok := NewAstronomicalCalendar(dateTime, geoLocation, astronomicalCalculator).Sunrise()
+	assert.False(t, tag, ok)
+
+	// the sun stays below the adjusted zenith all day, so that sunrise merges at solar noon
+	subject := NewPolarFallbackCalendar(dateTime, geoLocation, astronomicalCalculator)
+	subject.SetStrategies(PolarFallbackSolarNoonMidnight)
+	sunrise, ok := subject.Sunrise()
+	assert.True(t, tag, ok)
+	noon := calculator.UTCSolarNoon(dateTime, geoLocation)
+	expected := dateTime.D.ToTime(time.UTC).Add(time.Duration(noon * float64(time.Hour)))
+	assert.True(t, tag, sunrise.Time.Sub(expected).Abs() < time.Second)
+}
+
+func TestPolarFallbackMidnightSun(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	dateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 6, 21), gdt.NewGTime0())
+	geoLocation := calculator.DaneborgGeoLocation()
+	_, ok := NewAstronomicalCalendar(dateTime, geoLocation, calculator.NewNOAACalculator()).Sunset()
+	assert.False(t, tag, ok)
+
+	subject := NewPolarFallbackCalendar(dateTime, geoLocation, calculator.NewNOAACalculator())
+
+	// the default strategies start with the nearest day
+	sunset, ok := subject.Sunset()
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, PolarFallbackNearestDay, sunset.Fallback)
+
+	// the sun stays above the horizon, so that sunrise and sunset merge at solar midnight
+	subject.SetStrategies(PolarFallbackSolarNoonMidnight)
+	sunrise, ok := subject.Sunrise()
+	assert.True(t, tag, ok)
+	sunset, ok = subject.Sunset()
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, PolarFallbackSolarNoonMidnight, sunset.Fallback)
+	assert.Equal(t, tag, 24*time.Hour, sunset.Time.Sub(sunrise.Time).Round(time.Minute))
+
+	chatzos, ok := subject.Zman(ComplexZmanimCalendar.Chatzos)
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, 12*time.Hour, chatzos.Time.Sub(sunrise.Time).Round(time.Minute))
+}
+
+func TestPolarFallbackMixed(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the sun rises and sets at Fort Conger on the equinox, but doesn't dip 19.8 deg below the horizon
+	dateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 3, 20), gdt.NewGTime0())
+	subject := NewPolarFallbackCalendar(dateTime, calculator.ArcticNunavutGeoLocation(), calculator.NewNOAACalculator())
+
+	sunrise, ok := subject.Sunrise()
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, PolarFallbackNone, sunrise.Fallback)
+
+	subject.SetStrategies(PolarFallbackFixedLatitude, PolarFallbackNearestDay)
+	alos, ok := subject.Zman(ComplexZmanimCalendar.Alos19Point8Degrees)
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, PolarFallbackFixedLatitude, alos.Fallback)
+	assert.True(t, tag, alos.Time.Before(sunrise.Time))
+
+	// the shaah zmanis of the MGA is based on the fallback of alos and tzais
+	sofZmanShma, ok := subject.Zman(ComplexZmanimCalendar.SofZmanShmaMGA16Point1Degrees)
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, PolarFallbackFixedLatitude, sofZmanShma.Fallback)
+
+	// the sun dips 19.8 deg below the horizon on the equinox at the latitude of 60 deg as well
+	subject.SetFixedLatitude(PolarFallbackLatitude60)
+	alos, ok = subject.Zman(ComplexZmanimCalendar.Alos19Point8Degrees)
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, PolarFallbackFixedLatitude, alos.Fallback)
+
+	subject.SetStrategies()
+	_, ok = subject.Zman(ComplexZmanimCalendar.Alos19Point8Degrees)
+	assert.False(t, tag, ok)
+}
+
+func TestPolarFallbackSetStrategiesPanics(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	defer assert.Raises(t, tag)()
+
+	subject := NewPolarFallbackCalendar(gdt.NewGDateTime(gdt.NewGDate(2017, 12, 21), gdt.NewGTime0()), calculator.DaneborgGeoLocation(), calculator.NewNOAACalculator())
+	subject.SetStrategies(PolarFallbackNearestDay, PolarFallbackNearestDay)
+}
+
+func TestPolarFallbackSetFixedLatitudePanics(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	defer assert.Raises(t, tag)()
+
+	subject := NewPolarFallbackCalendar(gdt.NewGDateTime(gdt.NewGDate(2017, 12, 21), gdt.NewGTime0()), calculator.DaneborgGeoLocation(), calculator.NewNOAACalculator())
+	subject.SetFixedLatitude(90)
+}
+
+func TestPolarFallbackAdjustableCalculator(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	dateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 12, 21), gdt.NewGTime0())
+	noaaCalculator := calculator.NewNOAACalculator().(calculator.AdjustableCalculator)
+	noaaCalculator.SetRefractionModel(calculator.RefractionFunc(func(altitude dimension.Degrees) dimension.ArcMinutes { return 40 }))
+	noaaCalculator.SetDateDependentSolarRadius(true)
+	subject := newPolarFallbackCalendar()
+	subject.initPolarFallbackCalendar(dateTime, calculator.JerusalemGeoLocation(), noaaCalculator)
+
+	// the settings of the wrapped calculator are forwarded
+	var astronomicalCalculator calculator.AstronomicalCalculator
+	_, _ = subject.Zman(func(zc ComplexZmanimCalendar) (tm time.Time, ok bool) {
+		astronomicalCalculator = zc.(*complexZmanimCalendar).astronomicalCalculator
+		return time.Time{}, false
+	})
+	adjustableCalculator, ok := astronomicalCalculator.(calculator.AdjustableCalculator)
+	assert.True(t, tag, ok)
+	tm := dateTime.ToTime(time.UTC)
+	assert.Equal(t, tag, dimension.ArcMinutes(40), calculator.RefractionModelOf(adjustableCalculator).Refraction(0))
+	assert.Equal(t, tag, calculator.SolarRadiusOf(noaaCalculator, tm), calculator.SolarRadiusOf(adjustableCalculator, tm))
+	adjustableCalculator.SetDateDependentSolarRadius(false)
+	adjustableCalculator.SetSolarRadius(15)
+	adjustableCalculator.SetEarthRadius(6371)
+	assert.False(t, tag, noaaCalculator.IsDateDependentSolarRadius())
+	assert.Equal(t, tag, dimension.ArcMinutes(15), noaaCalculator.SolarRadius())
+	assert.Equal(t, tag, dimension.KM(6371), noaaCalculator.EarthRadius())
+
+	// the visible sunrise over a flat horizon is of the refraction and the sun position of the wrapped calculator
+	geoLocation := calculator.JerusalemGeoLocation().(calculator.HorizonGeoLocation)
+	geoLocation.SetHorizonProfile(calculator.NewHorizonProfile([]calculator.HorizonPoint{{Azimuth: 0, Altitude: 0}}))
+	expected, _ := NewComplexZmanimCalendar(dateTime, geoLocation, noaaCalculator).Sunrise()
+	actual, ok := NewPolarFallbackCalendar(dateTime, geoLocation, noaaCalculator).Sunrise()
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, PolarFallbackNone, actual.Fallback)
+	assert.Equal(t, tag, expected, actual.Time)
+}
@@ -0,0 +1,167 @@
+package zmanim
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"math"
+	"testing"
+	"time"
+)
+
+func assertHours(t *testing.T, tag string, want float64, got float64) {
+	assert.True(t, tag, math.Abs(want-got) < 1e-6)
+}
+
+func assertInstant(t *testing.T, tag string, want time.Time, got time.Time) {
+	assert.True(t, tag, want.Sub(got).Abs() < time.Millisecond)
+}
+
+func TestClockPositionShaosZmaniyos(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	cal := testNewZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
+	gra := DayDefinitionGRA()
+
+	sofZmanShma := test2to1(cal.SofZmanShmaGRA())("cal.SofZmanShmaGRA()")
+	position := test2to1(cal.ClockPosition(sofZmanShma, gra, ClockShaosZmaniyos))("cal.ClockPosition()")
+	assert.Equal(t, tag, gdt.NewGDate(2017, 10, 17), position.GDate)
+	assert.False(t, tag, position.Night)
+	assertHours(t, tag, 3, position.Hours)
+	assert.Equal(t, tag, time.Duration(test2to1(cal.ShaahZmanisGRA())("cal.ShaahZmanisGRA()"))*time.Millisecond, position.HourLength.Truncate(time.Millisecond))
+	assertInstant(t, tag, sofZmanShma, test2to1(cal.ClockInstant(position, gra))("cal.ClockInstant()"))
+
+	sunrise, sunset := test2to1(cal.Hanetz())("cal.Hanetz()"), test2to1(cal.Shkia())("cal.Shkia()")
+	midday := sunrise.Add(sunset.Sub(sunrise) / 2)
+	assertHours(t, tag, 6, test2to1(cal.ClockPosition(midday, gra, ClockShaosZmaniyos))("cal.ClockPosition()").Hours)
+
+	// the MGA day starts at alos
+	alos72 := test2to1(cal.Alos72())("cal.Alos72()")
+	position = test2to1(cal.ClockPosition(alos72, DayDefinitionMGA(), ClockShaosZmaniyos))("cal.ClockPosition()")
+	assert.False(t, tag, position.Night)
+	assertHours(t, tag, 0, position.Hours)
+	assert.Equal(t, tag, test2to1(cal.Alos())("cal.Alos()"), test2to1(DayDefinitionByDegrees(16.1).Start(cal))("Start"))
+	assert.Equal(t, tag, alos72, test2to1(DayDefinitionByMinutes(72).Start(cal))("Start"))
+}
+
+func TestClockPositionNight(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	cal := testNewZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
+	next := testNewZmanimCalendar(2017, 10, 18, calculator.LakewoodGeoLocation())
+	gra := DayDefinitionGRA()
+	sunset := test2to1(cal.Shkia())("cal.Shkia()")
+	sunrise := test2to1(next.Hanetz())("next.Hanetz()")
+
+	// the night after the 17th is of the 18th, and the position of any of the days around is found
+	for _, zc := range []ZmanimCalendar{cal, next} {
+		position := test2to1(zc.ClockPosition(sunset, gra, ClockShaosZmaniyos))("zc.ClockPosition()")
+		assert.Equal(t, tag, gdt.NewGDate(2017, 10, 18), position.GDate)
+		assert.True(t, tag, position.Night)
+		assertHours(t, tag, 0, position.Hours)
+
+		middle := sunset.Add(sunrise.Sub(sunset) / 4)
+		position = test2to1(zc.ClockPosition(middle, gra, ClockShaosZmaniyos))("zc.ClockPosition()")
+		assert.True(t, tag, position.Night)
+		assertHours(t, tag, 3, position.Hours)
+		assert.Equal(t, tag, "3:00 of the night", position.String())
+		assertInstant(t, tag, middle, test2to1(zc.ClockInstant(position, gra))("zc.ClockInstant()"))
+	}
+
+	// after midnight, before sunrise
+	early := time.Date(2017, 10, 18, 3, 0, 0, 0, timeutil.LoadLocationOrPanic("America/New_York"))
+	position := test2to1(cal.ClockPosition(early, gra, ClockShaosZmaniyos))("cal.ClockPosition()")
+	assert.Equal(t, tag, gdt.NewGDate(2017, 10, 18), position.GDate)
+	assert.True(t, tag, position.Night)
+	assert.True(t, tag, position.Hours > 6 && position.Hours < 12)
+	assertInstant(t, tag, early, test2to1(cal.ClockInstant(position, gra))("cal.ClockInstant()"))
+}
+
+func TestClockPositionSunset(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	cal := testNewZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
+	gra := DayDefinitionGRA()
+	sunset := test2to1(cal.Shkia())("cal.Shkia()")
+
+	position := test2to1(cal.ClockPosition(sunset, gra, ClockSunset))("cal.ClockPosition()")
+	assert.Equal(t, tag, gdt.NewGDate(2017, 10, 18), position.GDate)
+	assertHours(t, tag, 0, position.Hours)
+	assert.True(t, tag, position.Night)
+	assert.Equal(t, tag, time.Hour, position.HourLength)
+
+	tm := sunset.Add(13*time.Hour + 30*time.Minute)
+	position = test2to1(cal.ClockPosition(tm, gra, ClockSunset))("cal.ClockPosition()")
+	assert.Equal(t, tag, gdt.NewGDate(2017, 10, 18), position.GDate)
+	assert.False(t, tag, position.Night)
+	assertHours(t, tag, 13.5, position.Hours)
+	assert.Equal(t, tag, "13:30 from sunset", position.String())
+	assertInstant(t, tag, tm, test2to1(cal.ClockInstant(position, gra))("cal.ClockInstant()"))
+}
+
+func TestClockPositionAfterMidnight(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the sunset of Reykjavik at the summer solstice is after the midnight
+	reykjavik := calculator.NewGeoLocation1("Reykjavik, Iceland", 64.1466, -21.9426, timeutil.LoadLocationOrPanic("Atlantic/Reykjavik"))
+	cal := testNewZmanimCalendar(2024, 6, 21, reykjavik)
+	sunset := test2to1(cal.Shkia())("cal.Shkia()")
+	assert.Equal(t, tag, 22, sunset.In(reykjavik.TimeZone()).Day())
+
+	position := test2to1(cal.ClockPosition(sunset.Add(-time.Minute), DayDefinitionGRA(), ClockShaosZmaniyos))("cal.ClockPosition()")
+	assert.Equal(t, tag, gdt.NewGDate(2024, 6, 21), position.GDate)
+	assert.False(t, tag, position.Night)
+	assert.True(t, tag, position.Hours > 11.9 && position.Hours < 12)
+
+	// no alos and tzais of 16.1 deg
+	_, ok := cal.ClockPosition(sunset, DayDefinitionByDegrees(16.1), ClockShaosZmaniyos)
+	assert.False(t, tag, ok)
+}
+
+func TestClockPositionComplex(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the day from the hanetz to the ateret torah offset after the shkia, of the settings of the ComplexZmanimCalendar
+	// passed to the DayDefinition
+	ateretTorah := NewDayDefinition("ateret torah", ZmanimCalendar.Hanetz, func(zc ZmanimCalendar) (time.Time, bool) {
+		czc, ok := zc.(ComplexZmanimCalendar)
+		if !ok {
+			return time.Time{}, false
+		}
+		shkia, ok := czc.Shkia()
+		return shkia.Add(time.Duration(czc.AteretTorahSunsetOffset()) * time.Minute), ok
+	})
+
+	cal := testNewComplexZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
+	cal.(*complexZmanimCalendar).ateretTorahSunsetOffset = 30
+	tm := test2to1(cal.Shkia())("cal.Shkia()").Add(29 * time.Minute)
+	position := test2to1(cal.ClockPosition(tm, ateretTorah, ClockShaosZmaniyos))("cal.ClockPosition()")
+	assert.Equal(t, tag, gdt.NewGDate(2017, 10, 17), position.GDate)
+	assert.False(t, tag, position.Night)
+	assert.True(t, tag, position.Hours > 11.9 && position.Hours < 12)
+	assertInstant(t, tag, tm, test2to1(cal.ClockInstant(position, ateretTorah))("cal.ClockInstant()"))
+}
+
+func TestClockPositionPanics(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	cal := testNewZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
+
+	assert.Equal(t, tag, "4:12", ClockPosition{Hours: 4.2}.Clock())
+	assert.Equal(t, tag, "ClockSunset", ClockSunset.String())
+
+	func() {
+		defer assert.Raises(t, tag)()
+		cal.ClockPosition(time.Now(), DayDefinitionGRA(), ClockMode(5))
+	}()
+	func() {
+		defer assert.Raises(t, tag)()
+		cal.ClockInstant(ClockPosition{GDate: gdt.NewGDate(2017, 10, 17), Hours: -1}, DayDefinitionGRA())
+	}()
+	func() {
+		defer assert.Raises(t, tag)()
+		NewDayDefinition("", nil, ZmanimCalendar.Shkia)
+	}()
+}
@@ -0,0 +1,85 @@
+package zmanim
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"testing"
+	"time"
+)
+
+func TestVisibleSunriseFlatHorizon(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the visible sunrise and sunset are of the position of the sun of the calculator of the sunrise and sunset
+	for _, astronomicalCalculator := range []calculator.AstronomicalCalculator{calculator.NewNOAACalculator(), calculator.NewMeeusCalculator(), calculator.NewSPACalculator()} {
+		name := tag + " " + astronomicalCalculator.CalculatorName()
+		geoLocation := calculator.JerusalemGeoLocation().(calculator.HorizonGeoLocation)
+		geoLocation.SetElevation(0)
+		cal := NewAstronomicalCalendar(gdt.NewGDateTime(gdt.NewGDate(2017, 3, 20), gdt.NewGTime0()), geoLocation, astronomicalCalculator)
+		sunrise, _ := cal.Sunrise()
+		sunset, _ := cal.Sunset()
+
+		// a flat horizon gives the sea level sunrise and sunset
+		geoLocation.SetHorizonProfile(calculator.NewHorizonProfile([]calculator.HorizonPoint{{Azimuth: 0, Altitude: 0}}))
+		visibleSunrise, ok := cal.Sunrise()
+		assert.True(t, name, ok)
+		assert.True(t, name, visibleSunrise.Sub(sunrise).Abs() < 2*time.Second)
+		visibleSunset, ok := cal.Sunset()
+		assert.True(t, name, ok)
+		assert.True(t, name, visibleSunset.Sub(sunset).Abs() < 2*time.Second)
+		assert.Equal(t, name, geoLocation.TimeZone(), visibleSunrise.Location())
+	}
+}
+
+func TestVisibleSunriseObstructedHorizon(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	geoLocation := calculator.JerusalemGeoLocation().(calculator.HorizonGeoLocation)
+	geoLocation.SetElevation(0)
+	cal := NewAstronomicalCalendar(gdt.NewGDateTime(gdt.NewGDate(2017, 3, 20), gdt.NewGTime0()), geoLocation, calculator.NewNOAACalculator())
+	sunrise, _ := cal.Sunrise()
+	sunset, _ := cal.Sunset()
+
+	// mountains 2 deg high in the east and a flat horizon in the west
+	geoLocation.SetHorizonProfile(calculator.NewHorizonProfile([]calculator.HorizonPoint{{Azimuth: 80, Altitude: 2}, {Azimuth: 100, Altitude: 2}, {Azimuth: 260, Altitude: 0}, {Azimuth: 280, Altitude: 0}}))
+	visibleSunrise, ok := cal.Sunrise()
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, 9*time.Minute, visibleSunrise.Sub(sunrise).Truncate(time.Minute))
+	visibleSunset, ok := cal.Sunset()
+	assert.True(t, tag, ok)
+	assert.True(t, tag, visibleSunset.Sub(sunset).Abs() < 2*time.Second)
+
+	// the sea seen from a mountain in the west
+	geoLocation.SetHorizonProfile(calculator.NewHorizonProfile([]calculator.HorizonPoint{{Azimuth: 90, Altitude: 0}, {Azimuth: 270, Altitude: -1}}))
+	visibleSunset, ok = cal.Sunset()
+	assert.True(t, tag, ok)
+	assert.True(t, tag, visibleSunset.After(sunset.Add(4*time.Minute)))
+
+	// the sun never clears the horizon
+	geoLocation.SetHorizonProfile(calculator.NewHorizonProfile([]calculator.HorizonPoint{{Azimuth: 0, Altitude: 80}}))
+	_, ok = cal.Sunrise()
+	assert.False(t, tag, ok)
+	_, ok = cal.Sunset()
+	assert.False(t, tag, ok)
+}
+
+func TestVisibleSunriseNilTimeZone(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the visible sunrise and sunset of a location without a time zone are in GMT, as the sunrise and sunset
+	geoLocation := calculator.NewGeoLocation2("Jerusalem, Israel", 31.7781161, 35.233804, 0, nil).(calculator.HorizonGeoLocation)
+	cal := NewAstronomicalCalendar(gdt.NewGDateTime(gdt.NewGDate(2017, 3, 20), gdt.NewGTime0()), geoLocation, calculator.NewNOAACalculator())
+	sunrise, _ := cal.Sunrise()
+
+	geoLocation.SetHorizonProfile(calculator.NewHorizonProfile([]calculator.HorizonPoint{{Azimuth: 0, Altitude: 0}}))
+	visibleSunrise, ok := cal.Sunrise()
+	assert.True(t, tag, ok)
+	assert.True(t, tag, visibleSunrise.Sub(sunrise).Abs() < 2*time.Second)
+	assert.Equal(t, tag, sunrise.Location(), visibleSunrise.Location())
+	visibleSunset, ok := cal.Sunset()
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, timeutil.GmtTimezoneOrPanic(), visibleSunset.Location())
+}
@@ -636,3 +636,46 @@ func TestAssurBemelachaOnFirstOfTwoIssurMelachaDaysInIsrael(t *testing.T) {
 	assert.True(t, tag, cal.IsAssurBemlacha(test2to1(cal.Tzais())("cal.Tzais()").Add(-2*time.Second), test2to1(cal.Tzais())("cal.Tzais()"), true))
 	assert.True(t, tag, cal.IsAssurBemlacha(test2to1(cal.Tzais())("cal.Tzais()").Add(2*time.Second), test2to1(cal.Tzais())("cal.Tzais()"), true))
 }
+
+func TestNewZmanimCalendar1(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	location, err := calculator.LocationOf(calculator.LakewoodGeoLocation())
+	assert.Equal(t, tag, nil, err)
+	gDateTime := gdt.NewGDateTime1(timeutil.NewDate(2017, 10, 17, nil))
+
+	// the calendars of a shared location have their own geoLocations
+	cal := NewZmanimCalendar1(gDateTime, location, calculator.NewNOAACalculator())
+	other := NewZmanimCalendar1(gDateTime, location, calculator.NewNOAACalculator())
+	assert.True(t, tag, cal.GeoLocation() != other.GeoLocation())
+	cal.GeoLocation().SetLatitude1(0)
+	assert.Equal(t, tag, calculator.LakewoodGeoLocation().Latitude(), other.GeoLocation().Latitude())
+
+	want := testNewZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
+	assert.Equal(t, tag, test2to1(want.Shkia())("want.Shkia()"), test2to1(other.Shkia())("other.Shkia()"))
+}
+
+func TestPreviousAndNextDay(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	cal := testNewZmanimCalendar(2017, 10, 17, calculator.LakewoodGeoLocation())
+	assert.Equal(t, tag, test2to1(testNewZmanimCalendar(2017, 10, 16, calculator.LakewoodGeoLocation()).Tzais72())("Tzais72()"), test2to1(cal.PreviousDay().Tzais72())("cal.PreviousDay().Tzais72()"))
+	assert.Equal(t, tag, test2to1(testNewZmanimCalendar(2017, 10, 18, calculator.LakewoodGeoLocation()).Alos72())("Alos72()"), test2to1(cal.NextDay().Alos72())("cal.NextDay().Alos72()"))
+
+	// the Rabbeinu Tam tzais of the summer in Helsinki, 72 minutes after the sunset at about 22:50, is after the civil
+	// midnight
+	helsinki := calculator.NewGeoLocation2("Helsinki", 60.1699, 24.9384, 0, timeutil.LoadLocationOrPanic("Europe/Helsinki"))
+	cal = testNewZmanimCalendar(2017, 6, 21, helsinki)
+	shkia := test2to1(cal.Shkia())("cal.Shkia()")
+	tzais72 := test2to1(cal.Tzais72())("cal.Tzais72()")
+	assert.Equal(t, tag, 21, shkia.In(helsinki.TimeZone()).Day())
+	assert.Equal(t, tag, 22, tzais72.In(helsinki.TimeZone()).Day())
+	assert.Equal(t, tag, 72*time.Minute, tzais72.Sub(shkia))
+
+	// and the night that follows it ends with the alos of the next date
+	start, end, ok := cal.NightBounds(NightDefinitionTzais72ToAlos72())
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, tzais72, start)
+	assert.Equal(t, tag, test2to1(cal.NextDay().Alos72())("cal.NextDay().Alos72()"), end)
+	assert.True(t, tag, end.After(start))
+}
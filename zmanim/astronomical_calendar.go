@@ -21,6 +21,10 @@ year.
 When the calculations encounter this condition, ok = false will be returned.
 The reason that panic is not invoked in these cases, is because the lack of a rise/set or twilight is
 not an exception, but an expected condition in many parts of the world.
+
+Note: The times are instants, correct on the days of the daylight saving time transitions. A time in the hour repeated
+when the clocks are set back, such as 1:30 on the night the clocks in New York are set back from 2:00 to 1:00, reads the
+same as the time an hour before or after it, ZmanWithKind returns it flagged timeutil.LocalTimeRepeated.
 */
 type AstronomicalCalendar interface {
 	Sunrise() (tm time.Time, ok bool)
@@ -35,6 +39,12 @@ type AstronomicalCalendar interface {
 	UTCSeaLevelSunset(zenith dimension.Degrees) float64
 	TemporalHour() (i gdt.GMillisecond, ok bool)
 	SunTransit() (tm time.Time, ok bool)
+	// LocalNoon and other of the civil date in the time zone
+	//
+	LocalNoon() time.Time
+	ZoneTransition() (tm time.Time, change time.Duration, ok bool)
+	LocalTimeKind(tm time.Time) timeutil.LocalTimeKind
+	ZmanWithKind(zman func() (time.Time, bool)) (tm time.Time, kind timeutil.LocalTimeKind, ok bool)
 	GeoLocation() calculator.GeoLocation
 }
 
@@ -73,6 +83,13 @@ func NewAstronomicalCalendar(dateTime gdt.GDateTime, geoLocation calculator.GeoL
 	return t
 }
 
+/*
+NewAstronomicalCalendar1 returns the AstronomicalCalendar of the calculator.Location.
+*/
+func NewAstronomicalCalendar1(dateTime gdt.GDateTime, location calculator.Location, astronomicalCalculator calculator.AstronomicalCalculator) AstronomicalCalendar {
+	return NewAstronomicalCalendar(dateTime, location.GeoLocation(), astronomicalCalculator)
+}
+
 /*
 The Sunrise method returns a time.Time representing the calculator.AstronomicalCalculator elevationAdjustment sunrise time.
 The zenith used
@@ -83,6 +100,8 @@ The zenith used
   - See documentation for the specific implementation of the calculator.AstronomicalCalculator that you are using.
 
 return the time.Time representing the exact sunrise time.
+If the calculator.GeoLocation is a calculator.HorizonGeoLocation of a calculator.HorizonProfile, it is the first moment
+the upper limb of the sun clears it (the visible sunrise over the terrain) instead.
 If the calculation can't be computed such as in the Arctic Circle where there is at least one day,
 a year, where the sun does not rise, and one where it does not set, an ok is false will be returned.
 see
@@ -91,6 +110,9 @@ see
 - UTCSunrise
 */
 func (t *astronomicalCalendar) Sunrise() (tm time.Time, ok bool) {
+	if calculator.HorizonProfileOf(t.GeoLocation()) != nil {
+		return t.visibleSunrise()
+	}
 	utcSunrise := t.UTCSunrise(calculator.GeometricZenith)
 	if math.IsNaN(utcSunrise) {
 		return time.Time{}, false
@@ -169,6 +191,8 @@ Note: In certain cases the calculates sunset will occur before sunrise. This wil
 other than the local timezone is used (calculating Los Angeles sunset using a GMT timezone for example). In this
 case the sunset date will be incremented to the following date.
 The method return the time.Time representing the exact sunset time.
+If the calculator.GeoLocation is a calculator.HorizonGeoLocation of a calculator.HorizonProfile, it is the last moment
+the upper limb of the sun clears it (the visible sunset over the terrain) instead.
 If the calculation can't be computed such as in the Arctic Circle where there is at least one day a year,
 where the sun does not rise, and one where it does not set, ok is false will be returned.
 See detailed explanation on top of the page.
@@ -178,6 +202,9 @@ see
 - UTCSunset
 */
 func (t *astronomicalCalendar) Sunset() (tm time.Time, ok bool) {
+	if calculator.HorizonProfileOf(t.GeoLocation()) != nil {
+		return t.visibleSunset()
+	}
 	sunset := t.UTCSunset(calculator.GeometricZenith)
 	if math.IsNaN(sunset) {
 		return time.Time{}, false
@@ -462,6 +489,9 @@ timeOfDay is the time to be set as the time for the time.Time.
 The time expected is in the format: 18.75 for 6:45:00 PM.time is sunrise and false if it is sunset
 isSunrise
 The method return the time.Time.
+The time is of the civil date of the calendar in the time zone of the GeoLocation, it is anchored to the LocalNoon of the
+date, so that neither the daylight saving time transitions nor the zmanim after the local midnight move it to another
+date.
 */
 func (t *astronomicalCalendar) dateTimeFromTimeOfDay(timeOfDay float64, isSunrise bool) time.Time {
 	if math.IsNaN(timeOfDay) {
@@ -479,26 +509,26 @@ func (t *astronomicalCalendar) dateTimeFromTimeOfDay(timeOfDay float64, isSunris
 	calculatedTime -= seconds             // remaining NOT milliseconds, but microseconds
 	nanoseconds := math.Floor(calculatedTime * float64(time.Second))
 
-	adjustedDateTime := t.adjustedDateTime()
-	year, month, day := adjustedDateTime.D.Year, adjustedDateTime.D.Month, adjustedDateTime.D.Day
-	utcDateTime := time.Date(int(year), month, int(day), int(hours), int(minutes), int(seconds), int(nanoseconds), timeutil.GmtTimezoneOrPanic())
-
-	// adjust date if utc time reflects a wraparound from the local offset
-	// Check if a date transition has occurred, or is about to occur - this indicates the date of the event is
-	// actually not the target date, but the day prior or after
-	localOffsetHours := math.Floor(t.GeoLocation().Longitude() / 15)
-	if isSunrise && localOffsetHours+hours > 18 {
-		utcDateTime = utcDateTime.AddDate(0, 0, -1) //add(Calendar.DAY_OF_MONTH, -1)
-	} else if !isSunrise && localOffsetHours+hours < 6 {
-		utcDateTime = utcDateTime.AddDate(0, 0, 1) // cal.add(Calendar.DAY_OF_MONTH, 1)
+	// the UTC date of the mean solar noon of the civil date, the sunrise is in the 24 hours up to 6 hours after the noon
+	// and the sunset in the 24 hours from 6 hours before it, also if it is after the local midnight
+	noon := t.meanSolarNoon()
+	utcDateTime := time.Date(noon.Year(), noon.Month(), noon.Day(), int(hours), int(minutes), int(seconds), int(nanoseconds), timeutil.GmtTimezoneOrPanic())
+	earliest := noon.Add(-6 * time.Hour)
+	if isSunrise {
+		earliest = noon.Add(-18 * time.Hour)
+	}
+	for utcDateTime.Before(earliest) {
+		utcDateTime = utcDateTime.AddDate(0, 0, 1)
+	}
+	for !utcDateTime.Before(earliest.Add(24 * time.Hour)) {
+		utcDateTime = utcDateTime.AddDate(0, 0, -1)
 	}
 
 	return t.convertDateTimeForZone(gdt.NewGDateTime1(utcDateTime))
-
 }
 
 func (t *astronomicalCalendar) convertDateTimeForZone(utcDateTime gdt.GDateTime) time.Time {
-	return utcDateTime.ToTime(nil).In(t.GeoLocation().TimeZone())
+	return utcDateTime.ToTime(nil).In(t.timeZone())
 }
 
 /*
@@ -0,0 +1,80 @@
+package zmanim
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/jdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"time"
+)
+
+/*
+BirkasHachamahZman the latest time of Birkas Hachamah by a sof zman tfila opinion.
+*/
+type BirkasHachamahZman struct {
+	// Opinion the name of the ComplexZmanimCalendar method of the sof zman tfila
+	Opinion string
+	Time    time.Time
+}
+
+/*
+BirkasHachamah the date of Birkas Hachamah with its latest times at a calculator.GeoLocation.
+Birkas Hachamah should be recited until sof zman tfila, and bedieved until chatzos.
+*/
+type BirkasHachamah struct {
+	GDate      gdt.GDate
+	JewishDate jdt.JDate
+	// SofZmanTfila the latest times by the sof zman tfila opinions, the opinions that can't be computed are omitted
+	SofZmanTfila []BirkasHachamahZman
+	// Chatzos the latest time bedieved, the zero time.Time if it can't be computed
+	Chatzos time.Time
+}
+
+/*
+birkasHachamahOpinions the sof zman tfila opinions of BirkasHachamah.SofZmanTfila, from the earliest to the latest.
+*/
+var birkasHachamahOpinions = []struct {
+	name string
+	zman func(t *complexZmanimCalendar) (tm time.Time, ok bool)
+}{
+	{"SofZmanTfilaMGA19Point8Degrees", (*complexZmanimCalendar).SofZmanTfilaMGA19Point8Degrees},
+	{"SofZmanTfilaMGA16Point1Degrees", (*complexZmanimCalendar).SofZmanTfilaMGA16Point1Degrees},
+	{"SofZmanTfilaMGA", (*complexZmanimCalendar).SofZmanTfilaMGA},
+	{"SofZmanTfilaBaalHatanya", (*complexZmanimCalendar).SofZmanTfilaBaalHatanya},
+	{"SofZmanTfilaGRA", (*complexZmanimCalendar).SofZmanTfilaGRA},
+	{"SofZmanTfila2HoursBeforeChatzos", (*complexZmanimCalendar).SofZmanTfila2HoursBeforeChatzos},
+}
+
+/*
+NextBirkasHachamah returns the first BirkasHachamah after the gDate at the geoLocation, see
+hebrewcalendar.NextBirkasHachamah.
+*/
+func NextBirkasHachamah(gDate gdt.GDate, geoLocation calculator.GeoLocation, astronomicalCalculator calculator.AstronomicalCalculator) BirkasHachamah {
+	return newBirkasHachamah(hebrewcalendar.NextBirkasHachamah(gDate), geoLocation, astronomicalCalculator)
+}
+
+/*
+PreviousBirkasHachamah returns the last BirkasHachamah before the gDate at the geoLocation, see
+hebrewcalendar.PreviousBirkasHachamah.
+*/
+func PreviousBirkasHachamah(gDate gdt.GDate, geoLocation calculator.GeoLocation, astronomicalCalculator calculator.AstronomicalCalculator) BirkasHachamah {
+	return newBirkasHachamah(hebrewcalendar.PreviousBirkasHachamah(gDate), geoLocation, astronomicalCalculator)
+}
+
+func newBirkasHachamah(gDate gdt.GDate, geoLocation calculator.GeoLocation, astronomicalCalculator calculator.AstronomicalCalculator) BirkasHachamah {
+	t := NewComplexZmanimCalendar(gdt.NewGDateTime(gDate, gdt.NewGTime0()), geoLocation, astronomicalCalculator).(*complexZmanimCalendar)
+
+	birkasHachamah := BirkasHachamah{
+		GDate:      gDate,
+		JewishDate: hebrewcalendar.NewJewishDate2(gDate).JDate(),
+	}
+
+	for _, opinion := range birkasHachamahOpinions {
+		if tm, ok := opinion.zman(t); ok {
+			birkasHachamah.SofZmanTfila = append(birkasHachamah.SofZmanTfila, BirkasHachamahZman{Opinion: opinion.name, Time: tm})
+		}
+	}
+	birkasHachamah.Chatzos, _ = t.Chatzos()
+
+	return birkasHachamah
+}
@@ -0,0 +1,104 @@
+package calculator
+
+import (
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestApparentSolarRadius(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	// perihelion and aphelion of 2017
+	assert.Equal(t, tag, 16.27, roundFloat(float64(ApparentSolarRadius(time.Date(2017, 1, 4, 14, 0, 0, 0, time.UTC))), 2))
+	assert.Equal(t, tag, 15.73, roundFloat(float64(ApparentSolarRadius(time.Date(2017, 7, 3, 20, 0, 0, 0, time.UTC))), 2))
+}
+
+func tromsoGeoLocation() GeoLocation {
+	return NewGeoLocation2("Tromsø, Norway", 69.672312, 19.049787, 0, time.UTC)
+}
+
+/*
+TestSolarRadiusDifferenceGreenwich the sunrise at the Royal Observatory in 2017 with the solar radius at the
+perihelion (16.293') and at the aphelion (15.755') differs by 3 to 5 seconds.
+*/
+func TestSolarRadiusDifferenceGreenwich(t *testing.T) {
+	geoLocation := NewGeoLocation2("Royal Observatory, Greenwich", 51.4769, -0.0005, 0, time.UTC)
+	for _, newCalc := range []func() AstronomicalCalculator{NewSunTimesCalculator, NewNOAACalculator} {
+		calc := newCalc().(AdjustableCalculator)
+		tag := fmt.Sprintf("%s[%s]", helper.CurrentFuncName(), calc.CalculatorName())
+		for _, date := range []gdt.GDate{gdt.NewGDate(2017, 1, 4), gdt.NewGDate(2017, 3, 20), gdt.NewGDate(2017, 6, 21), gdt.NewGDate(2017, 7, 3)} {
+			targetDateTime := gdt.NewGDateTime(date, gdt.NewGTime0())
+			calc.SetSolarRadius(16.293)
+			perihelionSunrise := calc.UTCSunrise(targetDateTime, geoLocation, GeometricZenith, false)
+			calc.SetSolarRadius(15.755)
+			aphelionSunrise := calc.UTCSunrise(targetDateTime, geoLocation, GeometricZenith, false)
+
+			difference := (aphelionSunrise - perihelionSunrise) * 3600
+			assert.True(t, tag, difference > 3 && difference < 5)
+		}
+	}
+}
+
+/*
+TestSolarRadiusDifferenceTromso the sunrise in Tromsø close to the rise of the midnight sun with the solar radius
+at the perihelion (16.293') and at the aphelion (15.755'), the figures of the doc of the solarRadius. The difference
+grows from less than a minute on May 17 to minutes on May 18, the last day with a sunrise of the USNO algorithm.
+*/
+func TestSolarRadiusDifferenceTromso(t *testing.T) {
+	for _, tc := range []struct {
+		newCalc func() AstronomicalCalculator
+		day     gdt.GDay
+		want    float64
+	}{
+		{NewSunTimesCalculator, 17, 42},
+		{NewNOAACalculator, 17, 46},
+		{NewSunTimesCalculator, 18, 196},
+		{NewNOAACalculator, 18, math.NaN()},
+	} {
+		calc := tc.newCalc().(AdjustableCalculator)
+		tag := fmt.Sprintf("%s[%s][%d]", helper.CurrentFuncName(), calc.CalculatorName(), tc.day)
+		targetDateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 5, tc.day), gdt.NewGTime0())
+
+		calc.SetSolarRadius(16.293)
+		perihelionSunrise := calc.UTCSunrise(targetDateTime, tromsoGeoLocation(), GeometricZenith, false)
+		calc.SetSolarRadius(15.755)
+		aphelionSunrise := calc.UTCSunrise(targetDateTime, tromsoGeoLocation(), GeometricZenith, false)
+
+		if math.IsNaN(tc.want) {
+			// no sunrise, the midnight sun
+			assert.True(t, tag, math.IsNaN(perihelionSunrise) && math.IsNaN(aphelionSunrise))
+			continue
+		}
+		assert.Equal(t, tag, tc.want, math.Round((aphelionSunrise-perihelionSunrise)*3600))
+	}
+}
+
+func TestDateDependentSolarRadius(t *testing.T) {
+	targetDateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 5, 17), gdt.NewGTime0())
+
+	for _, newCalc := range []func() AstronomicalCalculator{NewNOAACalculator, NewSunTimesCalculator} {
+		calc := newCalc().(AdjustableCalculator)
+		tag := fmt.Sprintf("%s[%s]", helper.CurrentFuncName(), calc.CalculatorName())
+		assert.False(t, tag, calc.IsDateDependentSolarRadius())
+
+		calc.SetSolarRadius(16.293)
+		perihelionSunrise := calc.UTCSunrise(targetDateTime, tromsoGeoLocation(), GeometricZenith, false)
+		calc.SetSolarRadius(15.755)
+		aphelionSunrise := calc.UTCSunrise(targetDateTime, tromsoGeoLocation(), GeometricZenith, false)
+
+		// the apparent radius in May (15.81') is between the radii at the perihelion and the aphelion, closer to the aphelion
+		calc.SetDateDependentSolarRadius(true)
+		assert.True(t, tag, calc.IsDateDependentSolarRadius())
+		sunrise := calc.UTCSunrise(targetDateTime, tromsoGeoLocation(), GeometricZenith, false)
+		assert.True(t, tag, perihelionSunrise < sunrise && sunrise < aphelionSunrise)
+		assert.True(t, tag, aphelionSunrise-sunrise < sunrise-perihelionSunrise)
+
+		// the radius only affects sunrise and sunset
+		assert.Equal(t, tag, newCalc().UTCSunrise(targetDateTime, LakewoodGeoLocation(), NauticalZenith, false),
+			calc.UTCSunrise(targetDateTime, LakewoodGeoLocation(), NauticalZenith, false))
+	}
+}
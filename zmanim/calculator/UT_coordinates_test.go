@@ -0,0 +1,129 @@
+package calculator
+
+import (
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"math"
+	"testing"
+)
+
+func TestParseCoordinates(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	for _, test := range []struct {
+		coordinates         string
+		latitude, longitude float64
+	}{
+		{`40°05'44"N 74°13'19"W`, 40.095556, -74.221944},
+		{`40°05'44.2"N, 74°13'19.2"W`, 40.095611, -74.222},
+		{`40° 05′ 44″ N 74° 13′ 19″ W`, 40.095556, -74.221944},
+		{`74°13'19"W 40°05'44"N`, 40.095556, -74.221944},
+		{`N40 05 44 W74 13 19`, 40.095556, -74.221944},
+		{`40 05 44 N, 74 13 19 W`, 40.095556, -74.221944},
+		{`40°05.736'N 74°13.32'W`, 40.0956, -74.222},
+		{`40 05 44, -74 13 19`, 40.095556, -74.221944},
+		{`40 05 44 -74 13 19`, 40.095556, -74.221944},
+		{`40.0956, -74.222`, 40.0956, -74.222},
+		{`40.0956,-74.222`, 40.0956, -74.222},
+		{` 40.0956 -74.222 `, 40.0956, -74.222},
+		{`40.0956°N 74.222°W`, 40.0956, -74.222},
+		{`-33.8688, 151.2093`, -33.8688, 151.2093},
+		{`33.8688 S 151.2093 E`, -33.8688, 151.2093},
+		{`+40.0956-074.2220/`, 40.0956, -74.222},
+		{`+40.0956-074.2220`, 40.0956, -74.222},
+		{`+4005.736-07413.32/`, 40.0956, -74.222},
+		{`+400544-0741319+15CRSWGS_84/`, 40.095556, -74.221944},
+		{`-3352.128+15112.558/`, -33.8688, 151.209300},
+		{`https://www.google.com/maps/@40.0956,-74.222,15z`, 40.0956, -74.222},
+		{`https://www.google.com/maps/place/Lakewood,+NJ/@40.08,-74.2,13z/data=!3m1!4b1!4m6!3m5!1s0x0:0x0!8m2!3d40.0956!4d-74.222`, 40.0956, -74.222},
+		{`https://maps.google.com/?q=40.0956,-74.222`, 40.0956, -74.222},
+		{`https://www.google.com/maps/search/?api=1&query=40.0956%2C-74.222`, 40.0956, -74.222},
+	} {
+		latitude, longitude, err := ParseCoordinates(test.coordinates)
+		assert.True(t, tag+" "+test.coordinates, err == nil)
+		assert.True(t, tag+" "+test.coordinates, math.Abs(latitude-test.latitude) < 1e-6)
+		assert.True(t, tag+" "+test.coordinates, math.Abs(longitude-test.longitude) < 1e-6)
+	}
+}
+
+func TestParseCoordinatesErrors(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	for _, coordinates := range []string{
+		``,
+		`40.0956`,
+		`40.0956, -74.222, 15`,
+		`91, 0`,
+		`0, 181`,
+		`40°05'44"N 74°13'19"N`,
+		`40°05'44"N 74°13'19"`,
+		`-40°05'44"N 74°13'19"W`,
+		`40°65'44"N 74°13'19"W`,
+		`40.5°05'N 74°13'W`,
+		`40 05 44 74 13`,
+		`Lakewood`,
+		`40..1, 74`,
+		`+4005736-0741319/`,
+		`https://www.google.com/maps/place/Lakewood`,
+	} {
+		_, _, err := ParseCoordinates(coordinates)
+		assert.True(t, tag+" "+coordinates, err != nil)
+	}
+}
+
+func TestParseGeoLocation(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject, err := ParseGeoLocation("Lakewood, NJ", `40°05'44"N 74°13'19"W`, nil)
+	assert.True(t, tag, err == nil)
+	assert.Equal(t, tag, "Lakewood, NJ", subject.LocationName())
+	assert.True(t, tag, math.Abs(subject.Latitude()-40.095556) < 1e-6)
+
+	_, err = ParseGeoLocation("Lakewood, NJ", `40°05'44"N`, nil)
+	assert.True(t, tag, err != nil)
+}
+
+func TestFormatCoordinates(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	assert.Equal(t, tag, "40.095600, -74.222000", FormatCoordinates(40.0956, -74.222, NotationDecimal))
+	assert.Equal(t, tag, `40°05'44.2"N 74°13'19.2"W`, FormatCoordinates(40.0956, -74.222, NotationDMS))
+	assert.Equal(t, tag, `33°52'07.7"S 151°12'33.5"E`, FormatCoordinates(-33.8688, 151.2093, NotationDMS))
+	assert.Equal(t, tag, `40°05.736'N 74°13.320'W`, FormatCoordinates(40.0956, -74.222, NotationDecimalMinutes))
+	assert.Equal(t, tag, "+40.09560-074.22200/", FormatCoordinates(40.0956, -74.222, NotationISO6709))
+	assert.Equal(t, tag, "-05.00000+005.00000/", FormatCoordinates(-5, 5, NotationISO6709))
+
+	// the seconds rounded to 60 carry to the minute
+	assert.Equal(t, tag, `1°00'00.0"N 0°00'00.0"E`, FormatCoordinates(0.99999999, 0, NotationDMS))
+
+	// every notation parses back
+	for _, notation := range []CoordinateNotation{NotationDecimal, NotationDMS, NotationDecimalMinutes, NotationISO6709} {
+		latitude, longitude, err := ParseCoordinates(FormatCoordinates(-33.8688, 151.2093, notation))
+		assert.True(t, tag+" "+notation.String(), err == nil)
+		assert.True(t, tag+" "+notation.String(), math.Abs(latitude+33.8688) < 1e-4 && math.Abs(longitude-151.2093) < 1e-4)
+	}
+}
+
+func TestFormatCoordinatesUnknownNotation(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	defer assert.Raises(t, tag)()
+
+	FormatCoordinates(0, 0, CoordinateNotation(100))
+}
+
+func TestGeoLocationString(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject := NewGeoLocation1("Lakewood, NJ", 40.0956, -74.222, nil)
+	assert.Equal(t, tag, "Lakewood, NJ (40.095600, -74.222000)", fmt.Sprint(subject))
+
+	formatter := subject.(interface {
+		Format(notation CoordinateNotation) string
+	})
+	assert.Equal(t, tag, `40°05'44.2"N 74°13'19.2"W`, formatter.Format(NotationDMS))
+	assert.Equal(t, tag, "+40.09560-074.22200/", formatter.Format(NotationISO6709))
+
+	subject.SetLocationName("")
+	assert.Equal(t, tag, "40.095600, -74.222000", fmt.Sprint(subject))
+}
@@ -0,0 +1,39 @@
+package calculator
+
+import (
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSeasons(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// US Naval Observatory, Earth's Seasons 2024
+	expected := [4]time.Time{
+		time.Date(2024, 3, 20, 3, 6, 0, 0, time.UTC),
+		time.Date(2024, 6, 20, 20, 51, 0, 0, time.UTC),
+		time.Date(2024, 9, 22, 12, 44, 0, 0, time.UTC),
+		time.Date(2024, 12, 21, 9, 20, 0, 0, time.UTC),
+	}
+	for i, season := range Seasons(2024) {
+		assert.Equal(t, tag, expected[i], season.Round(time.Minute))
+	}
+}
+
+func TestEquinoxOrSolstice(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// Astronomical Algorithms by Jean Meeus, Example 27.a, 1962 June 21 21:25:08 TD
+	expected := time.Date(1962, 6, 21, 21, 25, 8, 0, time.UTC).Add(-DeltaT(time.Date(1962, 6, 21, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, tag, math.Abs(EquinoxOrSolstice(1962, JuneSolstice).Sub(expected).Seconds()) < 60)
+
+	// the apparent longitude of the sun at the equinox
+	equinox := EquinoxOrSolstice(2100, SeptemberEquinox)
+	assert.Equal(t, tag, 180.0, roundFloat(float64(ApparentSunPosition(JulianEphemerisDay(equinox)).Longitude), 4))
+
+	defer assert.Raises(t, tag)()
+	EquinoxOrSolstice(2024, Season(4))
+}
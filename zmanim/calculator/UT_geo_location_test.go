@@ -1,12 +1,15 @@
 package calculator
 
 import (
+	"fmt"
 	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
 	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
 	"github.com/vlipovetskii/go-zmanim/helper"
 	"github.com/vlipovetskii/go-zmanim/helper/assert"
 	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
 	"testing"
+	"time"
 )
 
 func TestGMT(t *testing.T) {
@@ -76,6 +79,21 @@ func TestLongitudeCartographyWest(t *testing.T) {
 	assert.Equal(t, tag, -41.1181036, geoLocation.Longitude())
 }
 
+func TestLongitudeCartographyAfterWest(t *testing.T) {
+	// the previous longitude west of the prime meridian doesn't fail the new one
+	geoLocation := NewGeoLocation()
+	geoLocation.SetLongitude2(41, 7, 5.17296, "W")
+	geoLocation.SetLongitude2(41, 7, 5.17296, "E")
+
+	tag := helper.CurrentFuncName()
+
+	assert.Equal(t, tag, 41.1181036, geoLocation.Longitude())
+
+	geoLocation.SetLongitude1(-74.222)
+	geoLocation.SetLongitude2(74, 13, 19.2, "W")
+	assert.True(t, tag, math.Abs(geoLocation.Longitude()+74.222) < 1e-9)
+}
+
 func TestTimeZoneWithString(t *testing.T) {
 	/*
 	   geo = GeoLocation.GMT()
@@ -195,3 +213,71 @@ func TestStandardTimeOffsetAt(t *testing.T) {
 
 	*/
 }
+
+func TestTimeZoneOffsetAt(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	for _, test := range []struct {
+		tm       string
+		timeZone string
+		hours    gdt.GHour
+	}{
+		{"2017-03-12T06:30:00Z", "America/New_York", -5},
+		{"2017-03-12T07:00:00Z", "America/New_York", -4},
+		{"2017-03-12T09:30:00Z", "America/Los_Angeles", -8},
+		{"2017-03-12T10:00:00Z", "America/Los_Angeles", -7},
+		{"2017-03-23T23:30:00Z", "Asia/Jerusalem", 2},
+		{"2017-03-24T00:00:00Z", "Asia/Jerusalem", 3},
+	} {
+		tm, err := time.Parse(time.RFC3339, test.tm)
+		assert.Equal(t, tag, nil, err)
+		geoLocation := NewGeoLocation1("Sample", 0, 0, timeutil.LoadLocationOrPanic(test.timeZone))
+		assert.Equal(t, tag+" "+test.tm, test.hours.ToMilliseconds(), geoLocation.TimeZoneOffsetAt(tm))
+	}
+}
+
+func TestStandardTimeOffsetWithDaylightSavingTime(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the standard time offset doesn't depend on the daylight saving time, in effect at the time or now
+	for _, test := range []struct {
+		tm            string
+		timeZone      string
+		standardHours gdt.GHour
+	}{
+		{"2017-03-12T06:30:00Z", "America/New_York", -5},
+		{"2017-03-12T07:00:00Z", "America/New_York", -5},
+		{"2017-03-12T09:30:00Z", "America/Los_Angeles", -8},
+		{"2017-03-12T10:00:00Z", "America/Los_Angeles", -8},
+		{"2017-03-23T23:30:00Z", "Asia/Jerusalem", 2},
+		{"2017-03-24T00:00:00Z", "Asia/Jerusalem", 2},
+	} {
+		tm, err := time.Parse(time.RFC3339, test.tm)
+		assert.Equal(t, tag, nil, err)
+		geoLocation := NewGeoLocation1("Sample", 0, 0, timeutil.LoadLocationOrPanic(test.timeZone))
+		assert.Equal(t, tag+" "+test.tm, test.standardHours.ToMilliseconds(), StandardTimeOffsetAt(geoLocation, tm))
+		assert.Equal(t, tag+" "+test.tm, test.standardHours.ToMilliseconds(), geoLocation.StandardTimeOffset())
+	}
+
+	// the southern hemisphere, the daylight saving time is in January
+	sydney := NewGeoLocation1("Sydney", -33.8688, 151.2093, timeutil.LoadLocationOrPanic("Australia/Sydney"))
+	assert.Equal(t, tag, gdt.GHour(10).ToMilliseconds(), sydney.StandardTimeOffset())
+}
+
+func TestStandardTimeOffsetOfYear(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// Moscow kept the standard time of +4 from 2011 to 2014, it is +3 before and after
+	moscow := NewGeoLocation1("Moscow", 55.7558, 37.6173, timeutil.LoadLocationOrPanic("Europe/Moscow"))
+	for _, test := range []struct {
+		year          int
+		standardHours gdt.GHour
+	}{
+		{2010, 3},
+		{2012, 4},
+		{2015, 3},
+	} {
+		tm := time.Date(test.year, time.December, 1, 12, 0, 0, 0, time.UTC)
+		assert.Equal(t, fmt.Sprintf("%s %d", tag, test.year), test.standardHours.ToMilliseconds(), StandardTimeOffsetAt(moscow, tm))
+	}
+}
@@ -0,0 +1,124 @@
+package calculator
+
+import (
+	"errors"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"math"
+	"testing"
+)
+
+func dms(degrees float64, minutes float64, seconds float64) float64 {
+	return math.Copysign(math.Abs(degrees)+minutes/60+seconds/3600, degrees)
+}
+
+func TestVincentyDirect(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the example of Vincenty's paper from Flinders Peak to Buninyong
+	latitude, longitude, finalBearing, err := VincentyDirect(dms(-37, 57, 3.72030), dms(144, 25, 29.52440), dms(306, 52, 5.37), 54972.271)
+	assert.True(t, tag, err == nil)
+	assert.True(t, tag, math.Abs(latitude-dms(-37, 39, 10.15610)) < 1e-8)
+	assert.True(t, tag, math.Abs(longitude-dms(143, 55, 35.38390)) < 1e-8)
+	assert.True(t, tag, math.Abs(finalBearing+360-dms(307, 10, 25.07)) < 1e-5)
+
+	// across the 180 deg meridian
+	_, longitude, _, err = VincentyDirect(0, 179.5, 90, 111319.49)
+	assert.True(t, tag, err == nil)
+	assert.True(t, tag, math.Abs(longitude+179.5) < 1e-6)
+}
+
+func TestVincentyDirectNegativeDistance(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	defer assert.Raises(t, tag)()
+
+	_, _, _, _ = VincentyDirect(0, 0, 90, -1)
+}
+
+func TestVincentyInverse(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	distance, initialBearing, finalBearing, err := VincentyInverse(dms(-37, 57, 3.72030), dms(144, 25, 29.52440), dms(-37, 39, 10.15610), dms(143, 55, 35.38390))
+	assert.True(t, tag, err == nil)
+	assert.True(t, tag, math.Abs(distance-54972.271) < 1e-3)
+	assert.True(t, tag, math.Abs(initialBearing+360-dms(306, 52, 5.37)) < 1e-5)
+	assert.True(t, tag, math.Abs(finalBearing+360-dms(307, 10, 25.07)) < 1e-5)
+
+	distance, _, _, err = VincentyInverse(31.778, 35.2354, 31.778, 35.2354)
+	assert.True(t, tag, err == nil)
+	assert.Equal(t, tag, 0.0, distance)
+
+	// the nearly antipodal points
+	distance, _, _, err = VincentyInverse(0, 0, 0.5, 179.7)
+	assert.True(t, tag, errors.Is(err, ErrVincentyNotConverged))
+	assert.True(t, tag, math.IsNaN(distance))
+	assert.True(t, tag, math.IsNaN(NewGeoLocation1("", 0, 0, nil).GeodesicDistance(NewGeoLocation1("", 0.5, 179.7, nil))))
+
+	// the nearly antipodal points that converge in more iterations than the 19 of the GeodesicDistance of KosherJava
+	distance, _, _, err = VincentyInverse(0, 0, 0.5, 179)
+	assert.True(t, tag, err == nil)
+	assert.True(t, tag, math.Abs(distance-19902751.03) < 1)
+	origin, antipode := NewGeoLocation1("", 0, 0, nil), NewGeoLocation1("", 0.5, 179, nil)
+	assert.True(t, tag, math.IsNaN(origin.GeodesicDistance(antipode)))
+	assert.True(t, tag, math.IsNaN(origin.GeodesicInitialBearing(antipode)))
+	assert.True(t, tag, math.IsNaN(origin.GeodesicFinalBearing(antipode)))
+}
+
+func TestGeodesicDestination(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject := LakewoodGeoLocation()
+	destination, err := subject.GeodesicDestination(45, 2000)
+	assert.True(t, tag, err == nil)
+	assert.Equal(t, tag, subject.TimeZone(), destination.TimeZone())
+	assert.True(t, tag, math.Abs(subject.GeodesicDistance(destination)-2000) < 1e-6)
+	assert.True(t, tag, math.Abs(subject.GeodesicInitialBearing(destination)-45) < 1e-6)
+}
+
+func TestGeodesicMidpoint(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	midpoint, err := NewGeoLocation1("", 0, 0, nil).GeodesicMidpoint(NewGeoLocation1("", 0, 10, nil))
+	assert.True(t, tag, err == nil)
+	assert.True(t, tag, math.Abs(midpoint.Latitude()) < 1e-9)
+	assert.True(t, tag, math.Abs(midpoint.Longitude()-5) < 1e-9)
+
+	lakewood, jerusalem := LakewoodGeoLocation(), JerusalemGeoLocation()
+	midpoint, err = lakewood.GeodesicMidpoint(jerusalem)
+	assert.True(t, tag, err == nil)
+	assert.True(t, tag, math.Abs(lakewood.GeodesicDistance(midpoint)-jerusalem.GeodesicDistance(midpoint)) < 1e-3)
+
+	_, err = NewGeoLocation1("", 0, 0, nil).GeodesicMidpoint(NewGeoLocation1("", 0.5, 179.7, nil))
+	assert.True(t, tag, errors.Is(err, ErrVincentyNotConverged))
+}
+
+func TestGeodesicPoints(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	lakewood, jerusalem := LakewoodGeoLocation(), JerusalemGeoLocation()
+	points, err := lakewood.GeodesicPoints(jerusalem, 5)
+	assert.True(t, tag, err == nil)
+	assert.Equal(t, tag, 5, len(points))
+	assert.Equal(t, tag, lakewood, points[0])
+	assert.Equal(t, tag, jerusalem, points[4])
+
+	step := lakewood.GeodesicDistance(jerusalem) / 4
+	for i := 1; i < len(points); i++ {
+		assert.True(t, tag, math.Abs(points[i-1].GeodesicDistance(points[i])-step) < 1e-3)
+	}
+
+	// the first and the last points are the copies of the locations
+	assert.True(t, tag, points[0] != lakewood)
+	assert.True(t, tag, points[4] != jerusalem)
+	points[0].SetLatitude1(0)
+	points[4].SetLocationName("Kosel")
+	assert.Equal(t, tag, LakewoodGeoLocation().Latitude(), lakewood.Latitude())
+	assert.Equal(t, tag, JerusalemGeoLocation().LocationName(), jerusalem.LocationName())
+}
+
+func TestGeodesicPointsTooFew(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	defer assert.Raises(t, tag)()
+
+	_, _ = LakewoodGeoLocation().GeodesicPoints(JerusalemGeoLocation(), 1)
+}
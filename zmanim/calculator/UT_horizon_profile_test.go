@@ -0,0 +1,63 @@
+package calculator
+
+import (
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"strings"
+	"testing"
+)
+
+func TestHorizonProfileAltitude(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject := NewHorizonProfile([]HorizonPoint{{Azimuth: 270, Altitude: 0}, {Azimuth: 90, Altitude: 2}})
+
+	assert.Equal(t, tag, []HorizonPoint{{Azimuth: 90, Altitude: 2}, {Azimuth: 270, Altitude: 0}}, subject.Points())
+	assert.Equal(t, tag, dimension.Degrees(2), subject.Altitude(90))
+	assert.Equal(t, tag, dimension.Degrees(1), subject.Altitude(180))
+	// wrapping around the north
+	assert.Equal(t, tag, dimension.Degrees(1), subject.Altitude(0))
+	assert.Equal(t, tag, dimension.Degrees(1.5), subject.Altitude(45))
+	assert.Equal(t, tag, dimension.Degrees(1.5), subject.Altitude(405))
+	assert.Equal(t, tag, dimension.Degrees(0.5), subject.Altitude(-45))
+
+	// a single point is a constant horizon
+	subject = NewHorizonProfile([]HorizonPoint{{Azimuth: 0, Altitude: 0.5}})
+	assert.Equal(t, tag, dimension.Degrees(0.5), subject.Altitude(123))
+}
+
+func TestLoadHorizonProfile(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	expected := []HorizonPoint{{Azimuth: 0, Altitude: 0.5}, {Azimuth: 90, Altitude: 2}, {Azimuth: 180, Altitude: 1}, {Azimuth: 270, Altitude: 0}}
+	for _, path := range []string{"testdata/horizon_profile.csv", "testdata/horizon_profile.json"} {
+		subject, err := LoadHorizonProfile(path)
+		assert.True(t, tag, err == nil)
+		assert.Equal(t, tag, expected, subject.Points())
+	}
+
+	_, err := LoadHorizonProfile("horizon_profile.go")
+	assert.False(t, tag, err == nil)
+}
+
+func TestReadHorizonProfileErrors(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	for _, csv := range []string{"", "0,1\nx,y\n", "0,1,2\n", "0,1\n360,1\n", "0,1\n0,2\n", "0,90\n"} {
+		_, err := ReadHorizonProfileCSV(strings.NewReader(csv))
+		assert.False(t, tag, err == nil)
+	}
+	for _, json := range []string{"", "[]", "{}", `[{"azimuth": -1, "altitude": 0}]`} {
+		_, err := ReadHorizonProfileJSON(strings.NewReader(json))
+		assert.False(t, tag, err == nil)
+	}
+}
+
+func TestNewHorizonProfilePanics(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	defer assert.Raises(t, tag)()
+
+	NewHorizonProfile(nil)
+}
@@ -0,0 +1,175 @@
+package calculator
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"testing"
+	"time"
+)
+
+func testJerusalemLocation() Location {
+	location, err := NewLocation1("Jerusalem, Israel", 31.7781161, 35.233804, 740, "Asia/Jerusalem")
+	if err != nil {
+		panic(err.Error())
+	}
+	return location
+}
+
+func TestNewLocation(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	location := testJerusalemLocation()
+	assert.Equal(t, tag, "Jerusalem, Israel", location.Name())
+	assert.Equal(t, tag, 31.7781161, location.Latitude())
+	assert.Equal(t, tag, 35.233804, location.Longitude())
+	assert.Equal(t, tag, dimension.Meters(740), location.Elevation())
+	assert.Equal(t, tag, "Asia/Jerusalem", location.TimeZoneName())
+
+	assert.Equal(t, tag, "UTC", Location{}.TimeZoneName())
+	utc, err := NewLocation("", 0, 0, 0, nil)
+	assert.Equal(t, tag, nil, err)
+	assert.Equal(t, tag, time.UTC, utc.TimeZone())
+
+	for _, test := range []struct {
+		latitude, longitude, elevation float64
+		timeZoneName                   string
+	}{
+		{90.1, 0, 0, ""},
+		{-90.1, 0, 0, ""},
+		{math.NaN(), 0, 0, ""},
+		{0, 180.1, 0, ""},
+		{0, -180.1, 0, ""},
+		{0, math.NaN(), 0, ""},
+		{0, 0, -1, ""},
+		{0, 0, math.NaN(), ""},
+		{0, 0, math.Inf(1), ""},
+		{0, 0, 0, "Nowhere/Atlantis"},
+	} {
+		_, err := NewLocation1("", test.latitude, test.longitude, dimension.Meters(test.elevation), test.timeZoneName)
+		assert.True(t, tag, errors.Is(err, ErrInvalidLocation))
+	}
+
+	// a time zone that can't be loaded by its name, so that the Location couldn't be unmarshalled
+	_, err = NewLocation("Jerusalem", 31.7781161, 35.233804, 740, time.FixedZone("IST", 2*60*60))
+	assert.True(t, tag, errors.Is(err, ErrInvalidLocation))
+}
+
+func TestLocationOf(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	location, err := LocationOf(JerusalemGeoLocation())
+	assert.Equal(t, tag, nil, err)
+	assert.True(t, tag, location.Equal(testJerusalemLocation()))
+
+	geoLocation := location.GeoLocation()
+	assert.Equal(t, tag, location.Name(), geoLocation.LocationName())
+	assert.Equal(t, tag, location.Latitude(), geoLocation.Latitude())
+	assert.Equal(t, tag, location.Longitude(), geoLocation.Longitude())
+	assert.Equal(t, tag, location.Elevation(), geoLocation.Elevation())
+	assert.Equal(t, tag, location.TimeZone(), geoLocation.TimeZone())
+
+	// the GeoLocation is a copy
+	geoLocation.SetLatitude1(0)
+	assert.Equal(t, tag, 31.7781161, location.Latitude())
+	assert.True(t, tag, location.GeoLocation() != location.GeoLocation())
+}
+
+func TestLocationWith(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	location := testJerusalemLocation()
+
+	kosel, err := location.WithName("Kosel").WithCoordinates(31.7767, 35.2345)
+	assert.Equal(t, tag, nil, err)
+	kosel, err = kosel.WithElevation(720)
+	assert.Equal(t, tag, nil, err)
+	assert.Equal(t, tag, "Kosel", kosel.Name())
+	assert.Equal(t, tag, 31.7767, kosel.Latitude())
+	assert.Equal(t, tag, 35.2345, kosel.Longitude())
+	assert.Equal(t, tag, dimension.Meters(720), kosel.Elevation())
+	assert.Equal(t, tag, "Asia/Jerusalem", kosel.TimeZoneName())
+
+	// the original is not modified
+	assert.True(t, tag, location.Equal(testJerusalemLocation()))
+	assert.False(t, tag, location.Equal(kosel))
+	utc, err := location.WithTimeZone(nil)
+	assert.Equal(t, tag, nil, err)
+	assert.False(t, tag, location.Equal(utc))
+	jerusalem, err := utc.WithTimeZone(timeutil.LoadLocationOrPanic("Asia/Jerusalem"))
+	assert.Equal(t, tag, nil, err)
+	assert.True(t, tag, location.Equal(jerusalem))
+
+	_, err = location.WithLatitude(91)
+	assert.True(t, tag, errors.Is(err, ErrInvalidLocation))
+	_, err = location.WithLongitude(-181)
+	assert.True(t, tag, errors.Is(err, ErrInvalidLocation))
+	_, err = location.WithCoordinates(0, 200)
+	assert.True(t, tag, errors.Is(err, ErrInvalidLocation))
+	_, err = location.WithElevation(-5)
+	assert.True(t, tag, errors.Is(err, ErrInvalidLocation))
+	_, err = location.WithTimeZone(time.FixedZone("IST", 2*60*60))
+	assert.True(t, tag, errors.Is(err, ErrInvalidLocation))
+
+	assert.Equal(t, tag, "Jerusalem, Israel (31.778116, 35.233804)", location.String())
+}
+
+func TestLocationJSON(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	location := testJerusalemLocation()
+	data, err := json.Marshal(location)
+	assert.Equal(t, tag, nil, err)
+	assert.Equal(t, tag, `{"name":"Jerusalem, Israel","latitude":31.7781161,"longitude":35.233804,"elevation":740,"timeZone":"Asia/Jerusalem"}`, string(data))
+
+	var got Location
+	assert.Equal(t, tag, nil, json.Unmarshal(data, &got))
+	assert.True(t, tag, location.Equal(got))
+
+	// in a struct
+	type place struct {
+		Home Location `json:"home"`
+	}
+	data, err = json.Marshal(place{Home: location})
+	assert.Equal(t, tag, nil, err)
+	var p place
+	assert.Equal(t, tag, nil, json.Unmarshal(data, &p))
+	assert.True(t, tag, location.Equal(p.Home))
+
+	for _, data := range []string{
+		`{"latitude":91,"longitude":0,"elevation":0,"timeZone":"UTC"}`,
+		`{"latitude":0,"longitude":0,"elevation":0,"timeZone":"Nowhere/Atlantis"}`,
+		`{"latitude":"north"}`,
+	} {
+		assert.True(t, tag+" "+data, json.Unmarshal([]byte(data), &got) != nil)
+	}
+	assert.True(t, tag, location.Equal(p.Home))
+}
+
+func TestLocationText(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	location, _ := testJerusalemLocation().WithCoordinates(31.7767, 35.2345)
+	location = location.WithName("Kosel, Jerusalem")
+	text, err := location.MarshalText()
+	assert.Equal(t, tag, nil, err)
+	assert.Equal(t, tag, "31.7767,35.2345,740,Asia/Jerusalem,Kosel, Jerusalem", string(text))
+
+	var got Location
+	assert.Equal(t, tag, nil, got.UnmarshalText(text))
+	assert.True(t, tag, location.Equal(got))
+
+	for _, text := range []string{
+		"31.7767,35.2345,740,Asia/Jerusalem",
+		"north,35.2345,740,Asia/Jerusalem,",
+		"31.7767,35.2345,-1,Asia/Jerusalem,",
+		"31.7767,35.2345,740,Nowhere/Atlantis,",
+	} {
+		err := got.UnmarshalText([]byte(text))
+		assert.True(t, tag+" "+text, errors.Is(err, ErrInvalidLocation))
+	}
+}
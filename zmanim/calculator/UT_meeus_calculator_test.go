@@ -0,0 +1,164 @@
+package calculator
+
+import (
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewMeeusCalculator(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	calc := NewMeeusCalculator()
+	assert.Equal(t, tag, "Jean Meeus Astronomical Algorithms", calc.CalculatorName())
+}
+
+func TestDeltaT(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	// values at the start of the polynomial ranges of Espenak and Meeus
+	assert.Equal(t, tag, 10583.6, deltaTSeconds(0))
+	assert.Equal(t, tag, 1574.2, deltaTSeconds(1000))
+	assert.Equal(t, tag, 120.0, deltaTSeconds(1600))
+	assert.Equal(t, tag, 8.83, deltaTSeconds(1700))
+	assert.Equal(t, tag, 13.72, deltaTSeconds(1800))
+	assert.Equal(t, tag, -2.79, deltaTSeconds(1900))
+	assert.Equal(t, tag, 63.86, deltaTSeconds(2000))
+	// Astronomical Algorithms Example 10.a, 1977 February 18: ΔT = 48s
+	assert.Equal(t, tag, 48*time.Second, DeltaT(time.Date(1977, 2, 18, 3, 37, 40, 0, time.UTC)).Round(time.Second))
+}
+
+func TestJulianDayFromTime(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	assert.Equal(t, tag, JulianDayJan12000, julianDayFromTime(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)))
+	// outside of the range of time.Time.UnixNano
+	assert.Equal(t, tag, 0.0, julianDayFromTime(time.Date(-4713, 11, 24, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, tag, 2561117.5, julianDayFromTime(time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestMeeusApparentSunPosition(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	// Astronomical Algorithms Example 25.b, 1992 October 13.0 TD
+	sun := meeusApparentSunPosition(2448908.5)
+	assert.Equal(t, tag, 199.90606, roundFloat(sun.lambda, 5))
+	assert.Equal(t, tag, 0.99761, roundFloat(sun.r, 5))
+	assert.Equal(t, tag, 198.378, roundFloat(sun.alpha, 3))
+	assert.Equal(t, tag, -7.784, roundFloat(sun.delta, 3))
+}
+
+func roundFloat(val float64, precision uint) float64 {
+	ratio := math.Pow(10, float64(precision))
+	return math.Round(val*ratio) / ratio
+}
+
+func TestMeeusWorkedExamples(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// Astronomical Algorithms Example 22.a, 1987 April 10.0 TD: Δψ = -3.788", Δε = +9.443", ε = 23°26'36.850"
+	deltaPsi, deltaEpsilon := Nutation(2446895.5)
+	assert.Equal(t, tag, -3.788, roundFloat(float64(deltaPsi)*3600, 3))
+	assert.Equal(t, tag, 9.443, roundFloat(float64(deltaEpsilon)*3600, 3))
+	epsilon := TrueObliquityOfEcliptic(2446895.5)
+	assert.Equal(t, tag, 36.85, roundFloat((float64(epsilon)-23-26.0/60)*3600, 2))
+
+	// Example 12.a, 1987 April 10.0 UT: the apparent sidereal time at Greenwich is 13h10m46.1351s
+	assert.Equal(t, tag, 46.135, roundFloat((float64(ApparentSiderealTime(2446895.5, deltaPsi, epsilon))/15-13-10.0/60)*3600, 3))
+
+	// Example 27.a, the June solstice of 1962 is JDE 2437837.39245, the apparent longitude of the sun is 90 deg
+	assert.True(t, tag, math.Abs(meeusApparentSunPosition(2437837.39245).lambda-90) < 0.0005)
+}
+
+/*
+julianCalendarDate returns the GDate of the proleptic Gregorian calendar of the date of the Julian calendar, from its
+Julian Day (Astronomical Algorithms 7.1 with B = 0).
+*/
+func julianCalendarDate(year int, month time.Month, day int) gdt.GDate {
+	y, m := year, int(month)
+	if m <= 2 {
+		y--
+		m += 12
+	}
+	jd := math.Floor(365.25*float64(y+4716)) + math.Floor(30.6001*float64(m+1)) + float64(day) - 1524.5
+	return gdt.NewGDate1(timeFromJulianDay(jd + 0.5))
+}
+
+func TestMeeusHistoricalAndFutureDates(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// Example 7.b, 333 January 27.5 of the Julian calendar is JD 1842713.0, and the day before the Gregorian reform
+	assert.Equal(t, tag, gdt.NewGDate1(timeFromJulianDay(1842713.0)), julianCalendarDate(333, time.January, 27))
+	assert.Equal(t, tag, gdt.NewGDate(1582, time.October, 14), julianCalendarDate(1582, time.October, 4))
+
+	calc := NewMeeusCalculator()
+	geoLocation := JerusalemGeoLocation()
+	for _, year := range []int{1000, 1600, 1750, 1850, 2017, 2300, 3000} {
+		tag := fmt.Sprintf("%s[%d]", helper.CurrentFuncName(), year)
+		// October 11 of the Julian calendar, such as October 17, 1000 of the proleptic Gregorian calendar of the GDate
+		gDate := julianCalendarDate(year, time.October, 11)
+		targetDateTime := gdt.NewGDateTime(gDate, gdt.NewGTime0())
+
+		// at the sunrise and the sunset the center of the sun is the refraction and the solar radius below the
+		// horizon, with the ΔT of the year (see TestMeeusHistoricalAndFutureSunPosition for the position of the sun)
+		for _, utc := range []float64{
+			calc.UTCSunrise(targetDateTime, geoLocation, GeometricZenith, false),
+			calc.UTCSunset(targetDateTime, geoLocation, GeometricZenith, false),
+		} {
+			tm := gDate.ToTime(time.UTC).Add(time.Duration(utc * float64(time.Hour)))
+			altitude, _ := SunHorizontalPosition(tm, geoLocation)
+			assert.True(t, tag, math.Abs(float64(altitude)+50.0/60) < 0.01)
+		}
+	}
+}
+
+/*
+meeusTable27C the periodic terms A, B and C of the table 27.C of Astronomical Algorithms by Jean Meeus
+*/
+var meeusTable27C = [24][3]float64{
+	{485, 324.96, 1934.136}, {203, 337.23, 32964.467}, {199, 342.08, 20.186}, {182, 27.85, 445267.112},
+	{156, 73.14, 45036.886}, {136, 171.52, 22518.443}, {77, 222.54, 65928.934}, {74, 296.72, 3034.906},
+	{70, 243.58, 9037.513}, {58, 119.81, 33718.147}, {52, 297.17, 150.678}, {50, 21.02, 2281.226},
+	{45, 247.54, 29929.562}, {44, 325.15, 31555.956}, {29, 60.93, 4443.417}, {18, 155.12, 67555.328},
+	{17, 288.79, 4562.452}, {16, 198.04, 62894.029}, {14, 199.76, 31436.921}, {12, 95.39, 14577.848},
+	{12, 287.11, 31931.756}, {12, 320.81, 34777.259}, {9, 227.73, 1222.114}, {8, 15.45, 16859.074},
+}
+
+/*
+meeusMarchEquinox returns the JDE of the March equinox of the year, 1000 - 3000, of the chapter 27 of Astronomical
+Algorithms by Jean Meeus: the mean instant of the table 27.B corrected by the periodic terms, independent of the
+VSOP87 theory of the sun.
+*/
+func meeusMarchEquinox(year float64) float64 {
+	y := (year - 2000) / 1000
+	jde0 := 2451623.80984 + y*(365242.37404+y*(0.05169+y*(-0.00411-y*0.00057)))
+
+	t := (jde0 - JulianDayJan12000) / JulianDaysPerCentury
+	w := (35999.373*t - 2.47) * math.Pi / 180
+	deltaLambda := 1 + 0.0334*math.Cos(w) + 0.0007*math.Cos(2*w)
+	s := 0.0
+	for _, term := range meeusTable27C {
+		s += term[0] * math.Cos((term[1]+term[2]*t)*math.Pi/180)
+	}
+	return jde0 + 0.00001*s/deltaLambda
+}
+
+func TestMeeusHistoricalAndFutureSunPosition(t *testing.T) {
+	for _, year := range []float64{1000, 1600, 2300, 3000} {
+		tag := fmt.Sprintf("%s[%.0f]", helper.CurrentFuncName(), year)
+		// the apparent longitude of the sun is 0 deg at the March equinox of the chapter 27, within 0.0005 deg (about
+		// 40 seconds of the motion of the sun)
+		lambda := meeusApparentSunPosition(meeusMarchEquinox(year)).lambda
+		assert.True(t, tag, math.Abs(math.Remainder(lambda, 360)) < 0.0005)
+	}
+}
+
+func TestMeeusNoSunrise(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	calc := NewMeeusCalculator()
+	// the sun does not rise in Daneborg, Greenland in December
+	targetDateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 12, 21), gdt.NewGTime0())
+
+	assert.True(t, tag, math.IsNaN(calc.UTCSunrise(targetDateTime, DaneborgGeoLocation(), GeometricZenith, true)))
+	assert.True(t, tag, math.IsNaN(calc.UTCSunset(targetDateTime, DaneborgGeoLocation(), GeometricZenith, true)))
+}
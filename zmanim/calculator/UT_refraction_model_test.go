@@ -0,0 +1,138 @@
+package calculator
+
+import (
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestConstantRefraction(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	model := NewConstantRefraction(ReingoldRefraction)
+	assert.Equal(t, tag, ReingoldRefraction, model.Refraction(0))
+	assert.Equal(t, tag, ReingoldRefraction, model.Refraction(45))
+}
+
+func TestBennettRefraction(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	assert.Equal(t, tag, 34.478, roundFloat(float64(NewBennettRefraction(1010, 10).Refraction(0)), 3))
+	assert.Equal(t, tag, 0.995, roundFloat(float64(NewBennettRefraction(1010, 10).Refraction(45)), 3))
+	// cold and high pressure increase the refraction
+	assert.Equal(t, tag, 40.948, roundFloat(float64(NewBennettRefraction(1030, -30).Refraction(0)), 3))
+}
+
+func TestSaemundssonRefraction(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	assert.Equal(t, tag, 34.433, roundFloat(float64(NewSaemundssonRefraction(1010, 10).Refraction(0)), 3))
+	assert.Equal(t, tag, 1.013, roundFloat(float64(NewSaemundssonRefraction(1010, 10).Refraction(45)), 3))
+}
+
+func TestRefractionFunc(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	model := RefractionFunc(func(apparentAltitude dimension.Degrees) dimension.ArcMinutes {
+		return dimension.ArcMinutes(30 - apparentAltitude)
+	})
+	assert.Equal(t, tag, dimension.ArcMinutes(30), model.Refraction(0))
+	assert.Equal(t, tag, dimension.ArcMinutes(20), model.Refraction(10))
+}
+
+func TestAstronomicalCalculatorDefaults(t *testing.T) {
+	for _, astronomicalCalculator := range []AstronomicalCalculator{NewNOAACalculator(), NewSunTimesCalculator(), NewSPACalculator(), NewMeeusCalculator()} {
+		calc := astronomicalCalculator.(AdjustableCalculator)
+		tag := fmt.Sprintf("%s[%s]", helper.CurrentFuncName(), calc.CalculatorName())
+		assert.Equal(t, tag, DefaultRefraction, calc.RefractionModel().Refraction(0))
+		assert.Equal(t, tag, dimension.ArcMinutes(16), calc.SolarRadius())
+		assert.Equal(t, tag, dimension.KM(6356.9), calc.EarthRadius())
+		assert.Equal(t, tag, GeometricZenith+50.0/60, calc.(interface {
+			adjustZenith(targetDateTime gdt.GDateTime, zenith dimension.Degrees, elevation dimension.Meters) dimension.Degrees
+		}).adjustZenith(gdt.NewGDateTime(gdt.NewGDate(2017, 10, 17), gdt.NewGTime0()), GeometricZenith, 0))
+	}
+}
+
+func TestRefractionModelAndSolarRadiusOf(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	tm := time.Date(2017, 1, 4, 14, 0, 0, 0, time.UTC)
+
+	// a calculator that isn't an AdjustableCalculator has the defaults
+	calc := struct{ AstronomicalCalculator }{NewNOAACalculator()}
+	assert.Equal(t, tag, DefaultRefraction, RefractionModelOf(calc).Refraction(0))
+	assert.Equal(t, tag, dimension.ArcMinutes(16), SolarRadiusOf(calc, tm))
+
+	adjustable := NewNOAACalculator().(AdjustableCalculator)
+	adjustable.SetRefractionModel(NewConstantRefraction(ReingoldRefraction))
+	adjustable.SetSolarRadius(15)
+	assert.Equal(t, tag, ReingoldRefraction, RefractionModelOf(adjustable).Refraction(0))
+	assert.Equal(t, tag, dimension.ArcMinutes(15), SolarRadiusOf(adjustable, tm))
+	adjustable.SetDateDependentSolarRadius(true)
+	assert.Equal(t, tag, ApparentSolarRadius(tm), SolarRadiusOf(adjustable, tm))
+}
+
+func TestAdjustedZenithOf(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	targetDateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 10, 17), gdt.NewGTime0())
+
+	// a calculator that isn't an AdjustableCalculator has the defaults
+	calc := struct{ AstronomicalCalculator }{NewNOAACalculator()}
+	assert.Equal(t, tag, GeometricZenith+50.0/60, AdjustedZenithOf(calc, targetDateTime, GeometricZenith, 0))
+	assert.Equal(t, tag, NauticalZenith, AdjustedZenithOf(calc, targetDateTime, NauticalZenith, 1000))
+
+	adjustable := NewNOAACalculator().(AdjustableCalculator)
+	adjustable.SetRefractionModel(NewConstantRefraction(0))
+	adjustable.SetSolarRadius(0)
+	assert.Equal(t, tag, GeometricZenith, AdjustedZenithOf(adjustable, targetDateTime, GeometricZenith, 0))
+	adjustable.SetEarthRadius(6371)
+	assert.Equal(t, tag, GeometricZenith+dimension.Radians(math.Acos(6371/6372.0)).ToDegrees(), AdjustedZenithOf(adjustable, targetDateTime, GeometricZenith, 1000))
+}
+
+func TestAstronomicalCalculatorsHonorRefractionAndRadius(t *testing.T) {
+	targetDateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 10, 17), gdt.NewGTime0())
+	geoLocation := LakewoodGeoLocation()
+
+	for _, newCalc := range []func() AstronomicalCalculator{NewNOAACalculator, NewSunTimesCalculator, func() AstronomicalCalculator { return NewSPACalculator() }, NewMeeusCalculator} {
+		defaultCalc := newCalc()
+		tag := fmt.Sprintf("%s[%s]", helper.CurrentFuncName(), defaultCalc.CalculatorName())
+
+		// no refraction and no solar radius is the same as the geometric sunrise and sunset, slightly above 90 deg
+		calc := newCalc().(AdjustableCalculator)
+		calc.SetRefractionModel(NewConstantRefraction(0))
+		calc.SetSolarRadius(0)
+		assert.Equal(t, tag, roundFloat(calc.UTCSunrise(targetDateTime, geoLocation, GeometricZenith, false), 9),
+			roundFloat(defaultCalc.UTCSunrise(targetDateTime, geoLocation, GeometricZenith+1e-12, false), 9))
+		assert.Equal(t, tag, roundFloat(calc.UTCSunset(targetDateTime, geoLocation, GeometricZenith, false), 9),
+			roundFloat(defaultCalc.UTCSunset(targetDateTime, geoLocation, GeometricZenith+1e-12, false), 9))
+
+		// more refraction makes the sunrise earlier and the sunset later
+		calc = newCalc().(AdjustableCalculator)
+		calc.SetRefractionModel(NewBennettRefraction(1030, -30))
+		assert.True(t, tag, calc.UTCSunrise(targetDateTime, geoLocation, GeometricZenith, false) <
+			defaultCalc.UTCSunrise(targetDateTime, geoLocation, GeometricZenith, false))
+		assert.True(t, tag, calc.UTCSunset(targetDateTime, geoLocation, GeometricZenith, false) >
+			defaultCalc.UTCSunset(targetDateTime, geoLocation, GeometricZenith, false))
+
+		// a larger earth radius reduces the elevation adjustment
+		calc = newCalc().(AdjustableCalculator)
+		calc.SetEarthRadius(12713.8)
+		assert.True(t, tag, calc.UTCSunrise(targetDateTime, geoLocation, GeometricZenith, true) >
+			defaultCalc.UTCSunrise(targetDateTime, geoLocation, GeometricZenith, true))
+	}
+}
+
+func TestSetSolarRadiusNegative(t *testing.T) {
+	defer assert.Raises(t, helper.CurrentFuncName())()
+	NewNOAACalculator().(AdjustableCalculator).SetSolarRadius(-1)
+}
+
+func TestSetEarthRadiusZero(t *testing.T) {
+	defer assert.Raises(t, helper.CurrentFuncName())()
+	NewNOAACalculator().(AdjustableCalculator).SetEarthRadius(0)
+}
+
+func TestSetRefractionModelNil(t *testing.T) {
+	defer assert.Raises(t, helper.CurrentFuncName())()
+	NewNOAACalculator().(AdjustableCalculator).SetRefractionModel(nil)
+}
@@ -0,0 +1,67 @@
+package calculator
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestUTCSolarNoon(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	targetDateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 3, 20), gdt.NewGTime0())
+	for _, geoLocation := range append(BasicTestGeoLocations(), ArcticNunavutGeoLocation(), DaneborgGeoLocation()) {
+		noon := UTCSolarNoon(targetDateTime, geoLocation)
+		assert.True(t, tag, noon >= 0 && noon < 24)
+
+		// the sun is on the meridian at solar noon
+		noonTime := targetDateTime.D.ToTime(time.UTC).Add(time.Duration(noon * float64(time.Hour)))
+		assert.True(t, tag, math.Abs(float64(solarHourAngle(noonTime, geoLocation.Longitude()))) < 0.001)
+
+		midnight := UTCSolarMidnight(targetDateTime, geoLocation)
+		assert.True(t, tag, math.Abs(math.Mod(midnight-noon+24, 24)-12) < 1e-9)
+	}
+
+	// NOAA Solar Calculator, Jerusalem 2017-03-20 solar noon 11:46:30 IST
+	noon := UTCSolarNoon(targetDateTime, JerusalemGeoLocation())
+	assert.True(t, tag, math.Abs(noon-(9+46.5/60)) < 1.0/60)
+}
+
+func TestSunDeclination(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the declination is 0 at the equinox and the obliquity of the ecliptic at the solstice
+	assert.True(t, tag, math.Abs(float64(SunDeclination(EquinoxOrSolstice(2024, MarchEquinox)))) < 0.001)
+	assert.True(t, tag, math.Abs(float64(SunDeclination(EquinoxOrSolstice(2024, JuneSolstice)))-23.44) < 0.01)
+}
+
+func TestSunHorizontalPositionOf(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	targetDateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 3, 20), gdt.NewGTime0())
+	geoLocation := JerusalemGeoLocation()
+	for _, astronomicalCalculator := range []AstronomicalCalculator{NewNOAACalculator(), NewMeeusCalculator(), NewSPACalculator(), NewSunTimesCalculator()} {
+		name := tag + " " + astronomicalCalculator.CalculatorName()
+
+		sunrise := astronomicalCalculator.UTCSunrise(targetDateTime, geoLocation, GeometricZenith, false)
+		sunriseTime := targetDateTime.D.ToTime(time.UTC).Add(time.Duration(sunrise * float64(time.Hour)))
+		altitude, azimuth := SunHorizontalPositionOf(astronomicalCalculator, sunriseTime, geoLocation)
+		assert.True(t, name, math.Abs(float64(azimuth)-90) < 1)
+
+		if _, ok := astronomicalCalculator.(SunPositionCalculator); !ok {
+			// the almanac algorithm of the US Naval Observatory falls back to the position of Jean Meeus
+			assert.Equal(t, name, NewSunTimesCalculator().CalculatorName(), astronomicalCalculator.CalculatorName())
+			meeusAltitude, meeusAzimuth := SunHorizontalPosition(sunriseTime, geoLocation)
+			assert.Equal(t, name, meeusAltitude, altitude)
+			assert.Equal(t, name, meeusAzimuth, azimuth)
+			continue
+		}
+
+		// at the sunrise of the calculator the center of the sun is the refraction and the solar radius below the horizon
+		expected := -(SolarRadiusOf(astronomicalCalculator, sunriseTime).InDegrees() + RefractionModelOf(astronomicalCalculator).Refraction(0).InDegrees())
+		assert.True(t, name, math.Abs(float64(altitude-expected)) < 0.01)
+	}
+}
@@ -0,0 +1,180 @@
+package calculator
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+spaTestVectors reads the published SPA test vectors from testdata/spa_test_vectors.csv.
+Every vector is returned as a map of the column name to the value.
+*/
+func spaTestVectors() []map[string]string {
+	file, err := os.Open("testdata/spa_test_vectors.csv")
+	if err != nil {
+		helper.PanicOnError(err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		helper.PanicOnError(err)
+	}
+
+	var vectors []map[string]string
+	for _, record := range records[1:] {
+		vector := map[string]string{}
+		for i, name := range records[0] {
+			vector[name] = record[i]
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors
+}
+
+func parseFloatOrPanic(s string) float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		helper.PanicOnError(err)
+	}
+	return f
+}
+
+/*
+assertPublishedFloat compares got with the value published as want, rounded to the number of decimals of want.
+*/
+func assertPublishedFloat(t *testing.T, tag string, want string, got float64) {
+	decimals := 0
+	if i := strings.Index(want, "."); i >= 0 {
+		decimals = len(want) - i - 1
+	}
+	ratio := math.Pow(10, float64(decimals))
+	assert.Equal(t, tag, parseFloatOrPanic(want), math.Round(got*ratio)/ratio)
+}
+
+/*
+localTimeOfDay formats the UTC time of day in hours passed in as the local time of day (rounded to a second) in the
+time zone of tm.
+*/
+func localTimeOfDay(utcHours float64, tm time.Time) string {
+	_, offset := tm.Zone()
+	seconds := int(math.Round((utcHours+float64(offset)/3600)*3600)) % 86400
+	if seconds < 0 {
+		seconds += 86400
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+func spaTestCalculatorAndLocation(vector map[string]string) (SPACalculator, GeoLocation, time.Time) {
+	tm, err := time.Parse(time.RFC3339, vector["time"])
+	if err != nil {
+		helper.PanicOnError(err)
+	}
+
+	calc := NewSPACalculator()
+	calc.SetDeltaT(time.Duration(parseFloatOrPanic(vector["delta_t"]) * float64(time.Second)))
+	calc.SetPressure(dimension.Millibars(parseFloatOrPanic(vector["pressure"])))
+	calc.SetTemperature(dimension.Celsius(parseFloatOrPanic(vector["temperature"])))
+
+	geoLocation := NewGeoLocation2("SPA test vector", parseFloatOrPanic(vector["latitude"]), parseFloatOrPanic(vector["longitude"]),
+		dimension.Meters(parseFloatOrPanic(vector["elevation"])), tm.Location())
+
+	return calc, geoLocation, tm
+}
+
+func TestNewSPACalculator(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	calc := NewSPACalculator()
+	assert.Equal(t, tag, "US National Renewable Energy Laboratory Solar Position Algorithm", calc.CalculatorName())
+	assert.Equal(t, tag, 69*time.Second, calc.DeltaT())
+	assert.Equal(t, tag, dimension.Millibars(1010), calc.Pressure())
+	assert.Equal(t, tag, dimension.Celsius(10), calc.Temperature())
+}
+
+func TestSPAGeocentricPosition(t *testing.T) {
+	for i, vector := range spaTestVectors() {
+		tag := fmt.Sprintf("%s[%d]", helper.CurrentFuncName(), i)
+		calc, _, tm := spaTestCalculatorAndLocation(vector)
+
+		jd := julianDayFromTime(tm)
+		g := spaGeocentricPosition(jd, calc.DeltaT().Seconds())
+
+		assertPublishedFloat(t, tag+".jd", vector["jd"], jd)
+		assertPublishedFloat(t, tag+".l", vector["l"], g.l)
+		assertPublishedFloat(t, tag+".b", vector["b"], g.b)
+		assertPublishedFloat(t, tag+".r", vector["r"], g.r)
+		assertPublishedFloat(t, tag+".deltaPsi", vector["delta_psi"], g.deltaPsi)
+		assertPublishedFloat(t, tag+".deltaEpsilon", vector["delta_epsilon"], g.deltaEpsilon)
+		assertPublishedFloat(t, tag+".epsilon", vector["epsilon"], g.epsilon)
+		assertPublishedFloat(t, tag+".lambda", vector["lambda"], g.lambda)
+		assertPublishedFloat(t, tag+".alpha", vector["alpha"], g.alpha)
+		assertPublishedFloat(t, tag+".delta", vector["delta"], g.delta)
+	}
+}
+
+func TestSPASolarPosition(t *testing.T) {
+	for i, vector := range spaTestVectors() {
+		tag := fmt.Sprintf("%s[%d]", helper.CurrentFuncName(), i)
+		calc, geoLocation, tm := spaTestCalculatorAndLocation(vector)
+
+		solarPosition := calc.SolarPosition(tm, geoLocation)
+
+		assertPublishedFloat(t, tag+".zenith", vector["zenith"], float64(solarPosition.Zenith))
+		assertPublishedFloat(t, tag+".azimuth", vector["azimuth"], float64(solarPosition.Azimuth))
+	}
+}
+
+func TestSPASunriseTransitSunset(t *testing.T) {
+	for i, vector := range spaTestVectors() {
+		tag := fmt.Sprintf("%s[%d]", helper.CurrentFuncName(), i)
+		calc, geoLocation, tm := spaTestCalculatorAndLocation(vector)
+		targetDateTime := gdt.NewGDateTime1(tm)
+
+		// the published sunrise and sunset are not adjusted for elevation
+		assert.Equal(t, tag+".sunrise", vector["sunrise"], localTimeOfDay(calc.UTCSunrise(targetDateTime, geoLocation, GeometricZenith, false), tm))
+		assert.Equal(t, tag+".transit", vector["transit"], localTimeOfDay(calc.(*spaCalculator).riseTransitSet(targetDateTime, geoLocation, GeometricZenith, spaSunTransit), tm))
+		assert.Equal(t, tag+".sunset", vector["sunset"], localTimeOfDay(calc.UTCSunset(targetDateTime, geoLocation, GeometricZenith, false), tm))
+	}
+}
+
+func TestSPANoSunrise(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	calc := NewSPACalculator()
+	// the sun does not rise in Daneborg, Greenland in December
+	targetDateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 12, 21), gdt.NewGTime0())
+
+	assert.True(t, tag, math.IsNaN(calc.UTCSunrise(targetDateTime, DaneborgGeoLocation(), GeometricZenith, true)))
+	assert.True(t, tag, math.IsNaN(calc.UTCSunset(targetDateTime, DaneborgGeoLocation(), GeometricZenith, true)))
+}
+
+func TestSPASolarPositionDateDependentSolarRadius(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	calc := NewSPACalculator()
+	calc.SetRefractionModel(NewConstantRefraction(0))
+	calc.SetSolarRadius(0)
+	targetDateTime := gdt.NewGDateTime(gdt.NewGDate(2017, 10, 17), gdt.NewGTime0())
+	geoLocation := JerusalemGeoLocation()
+
+	// 20 seconds before the center of the sun rises, it is refracted only within the apparent solar radius
+	sunrise := calc.UTCSunrise(targetDateTime, geoLocation, GeometricZenith, false)
+	tm := targetDateTime.D.ToTime(time.UTC).Add(time.Duration(sunrise*float64(time.Hour)) - 20*time.Second)
+	unrefracted := calc.SolarPosition(tm, geoLocation)
+	assert.True(t, tag, unrefracted.Elevation < 0)
+
+	calc.SetDateDependentSolarRadius(true)
+	refracted := calc.SolarPosition(tm, geoLocation)
+	assert.True(t, tag, refracted.Elevation > unrefracted.Elevation)
+}
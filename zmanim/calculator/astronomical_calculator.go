@@ -1,9 +1,11 @@
 package calculator
 
 import (
+	"fmt"
 	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
 	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
 	"math"
+	"time"
 )
 
 type AstronomicalCalculator interface {
@@ -42,13 +44,107 @@ type AstronomicalCalculator interface {
 	UTCSunset(targetDateTime gdt.GDateTime, geoLocation GeoLocation, zenith dimension.Degrees, adjustForElevation bool) float64
 }
 
+/*
+AdjustableCalculator an AstronomicalCalculator whose refraction, solar radius and earth radius of the adjustment of the
+zenith of sunrise and sunset can be set. The AstronomicalCalculator of NewNOAACalculator, NewSunTimesCalculator,
+NewSPACalculator and NewMeeusCalculator implements it. The methods are not in the AstronomicalCalculator, so that its
+implementations outside this package don't have to implement them. See RefractionModelOf and SolarRadiusOf.
+*/
+type AdjustableCalculator interface {
+	AstronomicalCalculator
+	/*
+	 RefractionModel returns the RefractionModel used to adjust the zenith of sunrise and sunset.
+	 The default is NewConstantRefraction(DefaultRefraction).
+	*/
+	RefractionModel() RefractionModel
+	/*
+	 SetRefractionModel sets the RefractionModel used to adjust the zenith of sunrise and sunset, for example
+	 NewConstantRefraction(ReingoldRefraction), NewBennettRefraction(pressure, temperature) or a RefractionFunc.
+	 A panic will be if the refractionModel is nil.
+	*/
+	SetRefractionModel(refractionModel RefractionModel)
+	/*
+	 SolarRadius returns the sun's radius used to adjust the zenith of sunrise and sunset.
+	*/
+	SolarRadius() dimension.ArcMinutes
+	/*
+	 SetSolarRadius sets the sun's radius used to adjust the zenith of sunrise and sunset.
+	 A panic will be if the solarRadius is negative.
+	*/
+	SetSolarRadius(solarRadius dimension.ArcMinutes)
+	/*
+	 IsDateDependentSolarRadius returns if the apparent solar radius is computed from the Earth–Sun distance of the
+	 target date (see ApparentSolarRadius) instead of using the fixed SolarRadius.
+	*/
+	IsDateDependentSolarRadius() bool
+	/*
+	 SetDateDependentSolarRadius sets if the apparent solar radius is computed from the Earth–Sun distance of the
+	 target date (see ApparentSolarRadius) instead of using the fixed SolarRadius. The default is false.
+	*/
+	SetDateDependentSolarRadius(dateDependentSolarRadius bool)
+	/*
+	 EarthRadius returns the earth's radius used for the elevation adjustment.
+	*/
+	EarthRadius() dimension.KM
+	/*
+	 SetEarthRadius sets the earth's radius used for the elevation adjustment.
+	 A panic will be if the earthRadius is not positive.
+	*/
+	SetEarthRadius(earthRadius dimension.KM)
+}
+
+/*
+RefractionModelOf returns the RefractionModel of the astronomicalCalculator if it is an AdjustableCalculator, otherwise
+NewConstantRefraction(DefaultRefraction).
+*/
+func RefractionModelOf(astronomicalCalculator AstronomicalCalculator) RefractionModel {
+	if a, ok := astronomicalCalculator.(AdjustableCalculator); ok {
+		return a.RefractionModel()
+	}
+	return NewConstantRefraction(DefaultRefraction)
+}
+
+/*
+SolarRadiusOf returns the sun's radius of the astronomicalCalculator at the time tm if it is an AdjustableCalculator, the
+ApparentSolarRadius if it IsDateDependentSolarRadius, otherwise its SolarRadius, and 16 dimension.ArcMinutes if it is not
+an AdjustableCalculator.
+*/
+func SolarRadiusOf(astronomicalCalculator AstronomicalCalculator, tm time.Time) dimension.ArcMinutes {
+	a, ok := astronomicalCalculator.(AdjustableCalculator)
+	if !ok {
+		return 16
+	}
+	if a.IsDateDependentSolarRadius() {
+		return ApparentSolarRadius(tm)
+	}
+	return a.SolarRadius()
+}
+
+/*
+AdjustedZenithOf returns the zenith as the UTCSunrise and the UTCSunset of the calc adjust it: the GeometricZenith plus
+the SolarRadiusOf, the horizon refraction of the RefractionModelOf and the dip of the horizon at the elevation (0 if
+not adjusted for the elevation), with the EarthRadius of the calc if it is an AdjustableCalculator. The other zeniths
+are returned unchanged.
+*/
+func AdjustedZenithOf(calc AstronomicalCalculator, targetDateTime gdt.GDateTime, zenith dimension.Degrees, elevation dimension.Meters) dimension.Degrees {
+	adjusted := &astronomicalCalculator{}
+	adjusted.initAstronomicalCalculator()
+	if a, ok := calc.(AdjustableCalculator); ok {
+		adjusted.refractionModel = a.RefractionModel()
+		adjusted.solarRadius = a.SolarRadius()
+		adjusted.dateDependentSolarRadius = a.IsDateDependentSolarRadius()
+		adjusted.earthRadius = a.EarthRadius()
+	}
+	return adjusted.adjustZenith(targetDateTime, zenith, elevation)
+}
+
 /*
 astronomicalCalculator An abstract class that all sun time calculating classes extend. This allows the algorithm used to be changed at
 runtime, easily allowing comparison the results of using different algorithms.
 */
 type astronomicalCalculator struct {
 	/*
-		The commonly used average solar refraction.
+		The refraction model, the commonly used average solar refraction by default.
 			Calendar calculations lists a more accurate global average of 34.478885263888294
 			 refraction value to be used when calculating sunrise and sunset.
 			The default value is 34 dimension.ArcMinutes. The [Errata and Notes for Calendrical Calculations: The Millennium Edition]: https://web.archive.org/web/20150915094635/http://emr.cs.iit.edu/home/reingold/calendar-book/second-edition/errata.pdf
@@ -56,7 +152,7 @@ type astronomicalCalculator struct {
 			 lists the actual average refraction value as 34.478885263888294 or approximately 34' 29". The refraction value as well
 			 as the solarRadius and elevation adjustment are added to the zenith used to calculate sunrise and sunset.
 	*/
-	refraction dimension.ArcMinutes
+	refractionModel RefractionModel
 
 	/*
 	 The commonly used average solarRadius in minutes of a degree.
@@ -64,18 +160,22 @@ type astronomicalCalculator struct {
 	 almost universally given as 16 arc minutes but in fact it differs by the time of the Year. At the
 	 [perihelion]: https://en.wikipedia.org/wiki/Perihelion it has an apparent radius of 16.293, while at the
 	 [aphelion]: https://en.wikipedia.org/wiki/Aphelion it has an apparent radius of 15.755. There is little
-	 affect for most location, but at high and low latitudes the difference becomes more apparent. My Calculations for
-	 the difference at the location of the [Royal Observatory, Greenwich]: https://www.rmg.co.uk/royal-observatory
-	 shows only a 4.494 seconds difference between the perihelion and aphelion radii, but moving into the arctic circle the
-	 difference becomes more noticeable. Tests for Tromso, Norway (latitude 69.672312, longitude 19.049787) show that
-	 on May 17, the rise of the midnight sun, a 2 minute 23 seconds difference is observed between the perihelion and
-	 aphelion radii using the USNO algorithm, but only 1 minute and 6 seconds difference using the NOAA algorithm.
-	 Areas farther north show an even greater difference. Note that these test are not real valid test cases because
+	 affect for most location, but at high and low latitudes the difference becomes more apparent. The sunrise at the
+	 location of the [Royal Observatory, Greenwich]: https://www.rmg.co.uk/royal-observatory in 2017 differs by only 3 to 5
+	 seconds between the perihelion and aphelion radii, but moving into the arctic circle the difference becomes more
+	 noticeable. At the sea level of Tromso, Norway (latitude 69.672312, longitude 19.049787) the difference is 42 seconds
+	 using the USNO algorithm and 46 seconds using the NOAA algorithm on May 17 2017, and 3 minutes 16 seconds using the
+	 USNO algorithm on May 18, when the NOAA algorithm has no sunrise at all (see TestSolarRadiusDifferenceTromso). Areas farther north show an even greater difference. Note that these test are not real valid test cases because
 	 they show the extreme difference on days that are not the perihelion or aphelion, but are shown for illustrative
-	 purposes only.
+	 purposes only. SetDateDependentSolarRadius allows to use the ApparentSolarRadius of the target date instead.
 	*/
 	solarRadius dimension.ArcMinutes
 
+	/*
+	 Is the apparent solar radius computed from the Earth–Sun distance of the target date instead of the solarRadius.
+	*/
+	dateDependentSolarRadius bool
+
 	/*
 	 The commonly used average earthRadius in KM. At this time, this only affects elevation adjustment and not the
 	 sunrise and sunset calculations. The value currently defaults to 6356.9 dimension.KM.
@@ -84,11 +184,86 @@ type astronomicalCalculator struct {
 }
 
 func (t *astronomicalCalculator) initAstronomicalCalculator() {
-	t.refraction = dimension.ArcMinutes(float64(34) / 60)
-	t.solarRadius = dimension.ArcMinutes(float64(16) / 60)
+	t.refractionModel = NewConstantRefraction(DefaultRefraction)
+	t.solarRadius = 16
 	t.earthRadius = 6356.9
 }
 
+func (t *astronomicalCalculator) RefractionModel() RefractionModel {
+	return t.refractionModel
+}
+
+func (t *astronomicalCalculator) SetRefractionModel(refractionModel RefractionModel) {
+	if refractionModel == nil {
+		panic("refractionModel is nil.")
+	}
+	t.refractionModel = refractionModel
+}
+
+func (t *astronomicalCalculator) SolarRadius() dimension.ArcMinutes {
+	return t.solarRadius
+}
+
+func (t *astronomicalCalculator) SetSolarRadius(solarRadius dimension.ArcMinutes) {
+	if solarRadius < 0 {
+		panic(fmt.Sprintf("solarRadius %v is negative.", solarRadius))
+	}
+	t.solarRadius = solarRadius
+}
+
+func (t *astronomicalCalculator) IsDateDependentSolarRadius() bool {
+	return t.dateDependentSolarRadius
+}
+
+func (t *astronomicalCalculator) SetDateDependentSolarRadius(dateDependentSolarRadius bool) {
+	t.dateDependentSolarRadius = dateDependentSolarRadius
+}
+
+/*
+solarRadiusFor returns the apparent solar radius used for the targetDateTime, the ApparentSolarRadius at noon UTC
+of the date if IsDateDependentSolarRadius, otherwise the SolarRadius.
+*/
+func (t *astronomicalCalculator) solarRadiusFor(targetDateTime gdt.GDateTime) dimension.ArcMinutes {
+	if !t.dateDependentSolarRadius {
+		return t.solarRadius
+	}
+	return ApparentSolarRadius(gdt.NewGDateTime(targetDateTime.D, gdt.NewGTime(12, 0, 0, 0)).ToTime(time.UTC))
+}
+
+/*
+SolarRadiusAt1AU the apparent radius (semi-diameter) of the sun seen from the distance of one Astronomical Unit,
+959.63 arc seconds (Astronomical Algorithms by Jean Meeus, chapter 55).
+*/
+const SolarRadiusAt1AU = dimension.ArcMinutes(959.63 / 60)
+
+/*
+ApparentSolarRadius returns the apparent radius of the sun seen from the earth at the time tm, computed from the
+Earth–Sun distance of the VSOP87 theory. It ranges from about 16.27 dimension.ArcMinutes at the perihelion in the
+beginning of January to about 15.73 dimension.ArcMinutes at the aphelion in the beginning of July.
+*/
+func ApparentSolarRadius(tm time.Time) dimension.ArcMinutes {
+	jme := julianCenturiesFromJulianDay(julianDayFromTime(tm)) / 10
+	return SolarRadiusAt1AU / dimension.ArcMinutes(earthPeriodicTermSum(spaRTerms, jme))
+}
+
+func (t *astronomicalCalculator) EarthRadius() dimension.KM {
+	return t.earthRadius
+}
+
+func (t *astronomicalCalculator) SetEarthRadius(earthRadius dimension.KM) {
+	if earthRadius <= 0 {
+		panic(fmt.Sprintf("earthRadius %v is not positive.", earthRadius))
+	}
+	t.earthRadius = earthRadius
+}
+
+/*
+horizonRefraction returns the refraction of the RefractionModel at the apparent horizon (apparent altitude 0 deg).
+*/
+func (t *astronomicalCalculator) horizonRefraction() dimension.ArcMinutes {
+	return t.refractionModel.Refraction(0)
+}
+
 /*
 elevationAdjustment method to return the adjustment to the zenith required to account for the elevation. Since a person at a higher
 elevation can see farther below the horizon, the calculation for sunrise / sunset is calculated below the horizon
@@ -121,8 +296,8 @@ Earth were without an atmosphere, true sunset and sunrise would correspond to a
 is not a point, and because the atmosphere refracts light, this 90&deg; zenith does not, in fact, correspond to
 true sunset or sunrise, instead the center of the Sun's disk must lie just below the horizon for the upper edge
 to be obscured. This means that a zenith of just above 90 deg; must be used. The Sun subtends an angle of 16
-minutes of arc this can be changed via the {@link #setSolarRadius(double)} method , and atmospheric refraction
-accounts for 34 minutes or so (this can be changed via the {@link #setRefraction(double)} method), giving a total
+minutes of arc this can be changed via the SetSolarRadius method , and atmospheric refraction
+accounts for 34 minutes or so (this can be changed via the SetRefractionModel method), giving a total
 of 50 arcminutes. The total value for ZENITH is 90+(5/6) or 90.8333333&deg; for true sunrise/sunset. Since a
 person at an elevation can see below the horizon of a person at sea level, this will also adjust the zenith to
 account for elevation if available. Note that this will only adjust the value if the zenith is exactly 90 Degrees.
@@ -137,12 +312,13 @@ used for the calculation uses geometric zenith of 90 deg; and adjustZenith adjus
 this slightly to account for solar refraction and the sun's radius. Another example would be
 AstronomicalCalendar.getEndNauticalTwilight that passes
 NauticalZenith to this method.
+targetDateTime the date used for the date dependent solar radius (see SetDateDependentSolarRadius).
 elevation in dimension.Meters.
 */
-func (t *astronomicalCalculator) adjustZenith(zenith dimension.Degrees, elevation dimension.Meters) dimension.Degrees {
+func (t *astronomicalCalculator) adjustZenith(targetDateTime gdt.GDateTime, zenith dimension.Degrees, elevation dimension.Meters) dimension.Degrees {
 	if zenith != GeometricZenith {
 		return zenith
 	} else {
-		return zenith + (t.solarRadius.ToDegrees() + t.refraction.ToDegrees() + t.elevationAdjustment(elevation))
+		return zenith + (t.solarRadiusFor(targetDateTime).InDegrees() + t.horizonRefraction().InDegrees() + t.elevationAdjustment(elevation))
 	}
 }
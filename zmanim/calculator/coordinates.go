@@ -0,0 +1,369 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/*
+CoordinateNotation the notation of the latitude and the longitude of FormatCoordinates and ParseCoordinates.
+*/
+type CoordinateNotation int
+
+const (
+	// NotationDecimal the signed decimal degrees, such as 40.095600, -74.222000
+	NotationDecimal CoordinateNotation = iota
+	// NotationDMS the degrees, minutes and seconds with the hemisphere, such as 40°05'44.2"N 74°13'19.2"W
+	NotationDMS
+	// NotationDecimalMinutes the degrees and decimal minutes with the hemisphere, such as 40°05.736'N 74°13.320'W
+	NotationDecimalMinutes
+	// NotationISO6709 the ISO 6709 string of the decimal degrees, such as +40.09560-074.22200/
+	NotationISO6709
+)
+
+func (t CoordinateNotation) String() string {
+	switch t {
+	case NotationDecimal:
+		return "Decimal"
+	case NotationDMS:
+		return "DMS"
+	case NotationDecimalMinutes:
+		return "DecimalMinutes"
+	case NotationISO6709:
+		return "ISO6709"
+	default:
+		return "Unknown"
+	}
+}
+
+/*
+FormatCoordinates returns the latitude and the longitude in the notation.
+A panic will be if the notation is unknown.
+*/
+func FormatCoordinates(latitude float64, longitude float64, notation CoordinateNotation) string {
+	switch notation {
+	case NotationDecimal:
+		return fmt.Sprintf("%.6f, %.6f", latitude, longitude)
+	case NotationDMS:
+		return formatDMS(latitude, "N", "S") + " " + formatDMS(longitude, "E", "W")
+	case NotationDecimalMinutes:
+		return formatDecimalMinutes(latitude, "N", "S") + " " + formatDecimalMinutes(longitude, "E", "W")
+	case NotationISO6709:
+		return fmt.Sprintf("%+09.5f%+010.5f/", latitude, longitude)
+	default:
+		panic(fmt.Sprintf("unknown CoordinateNotation %d", notation))
+	}
+}
+
+func formatDMS(value float64, positive string, negative string) string {
+	// rounded to the tenth of the second first, so that 59.96" carries to the minute
+	tenths := int64(math.Round(math.Abs(value) * 36000))
+	degrees, minutes, seconds := tenths/36000, tenths%36000/600, float64(tenths%600)/10
+	return fmt.Sprintf("%d°%02d'%04.1f\"%s", degrees, minutes, seconds, hemisphere(value, positive, negative))
+}
+
+func formatDecimalMinutes(value float64, positive string, negative string) string {
+	thousandths := int64(math.Round(math.Abs(value) * 60000))
+	degrees, minutes := thousandths/60000, float64(thousandths%60000)/1000
+	return fmt.Sprintf("%d°%06.3f'%s", degrees, minutes, hemisphere(value, positive, negative))
+}
+
+func hemisphere(value float64, positive string, negative string) string {
+	if value < 0 {
+		return negative
+	}
+	return positive
+}
+
+var (
+	// iso6709Pattern the ISO 6709 string: the latitude, the longitude, the optional altitude and CRS, and the solidus
+	iso6709Pattern = regexp.MustCompile(`^([+-]\d+(?:\.\d+)?)([+-]\d+(?:\.\d+)?)(?:[+-]\d+(?:\.\d+)?)?(?:CRS[^/]*)?/?$`)
+	// googleMapsPatterns the coordinates of the Google Maps URLs, the place (!3d!4d) is preferred to the view (@)
+	googleMapsPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`!3d(-?\d+(?:\.\d+)?)!4d(-?\d+(?:\.\d+)?)`),
+		regexp.MustCompile(`[?&](?:q|query|ll|center|destination)=(-?\d+(?:\.\d+)?),\s*(-?\d+(?:\.\d+)?)`),
+		regexp.MustCompile(`@(-?\d+(?:\.\d+)?),(-?\d+(?:\.\d+)?)`),
+	}
+)
+
+/*
+ParseCoordinates parses the latitude and the longitude in the common notations:
+  - the decimal degrees, such as 40.0956, -74.222 or 40.0956°N 74.222°W
+  - the degrees, minutes and seconds, such as 40°05'44"N 74°13'19"W, N40 05 44 W74 13 19 or 40°05.736'N 74°13.32'W
+  - the ISO 6709 strings, such as +40.0956-074.2220/, +4005.736-07413.32/ or +400544-0741319+15CRSWGS_84/ (the
+    altitude is ignored)
+  - the Google Maps URLs, such as https://www.google.com/maps/@40.0956,-74.222,15z or https://maps.google.com/?q=40.0956,-74.222
+
+With the hemisphere letters N, S, E and W the longitude may be first.
+*/
+func ParseCoordinates(coordinates string) (latitude float64, longitude float64, err error) {
+	text := strings.TrimSpace(coordinates)
+	if text == "" {
+		return 0, 0, fmt.Errorf("the coordinates are empty")
+	}
+
+	switch {
+	case iso6709Pattern.MatchString(text):
+		latitude, longitude, err = parseISO6709(text)
+	case strings.Contains(text, "://") || strings.Contains(strings.ToLower(text), "maps"):
+		latitude, longitude, err = parseGoogleMapsURL(text)
+	default:
+		latitude, longitude, err = parseNotation(text)
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("%q: %v", coordinates, err)
+	}
+
+	if latitude < -90 || latitude > 90 {
+		return 0, 0, fmt.Errorf("%q: the latitude %v is not between -90 and 90", coordinates, latitude)
+	}
+	if longitude < -180 || longitude > 180 {
+		return 0, 0, fmt.Errorf("%q: the longitude %v is not between -180 and 180", coordinates, longitude)
+	}
+	return latitude, longitude, nil
+}
+
+/*
+ParseGeoLocation returns the GeoLocation of the coordinates, see ParseCoordinates.
+*/
+func ParseGeoLocation(name string, coordinates string, timeZone *time.Location) (GeoLocation, error) {
+	latitude, longitude, err := ParseCoordinates(coordinates)
+	if err != nil {
+		return nil, err
+	}
+	return NewGeoLocation1(name, latitude, longitude, timeZone), nil
+}
+
+/*
+parseISO6709 parses the ISO 6709 string of the ±DD.D, ±DDMM.M or ±DDMMSS.S latitude and the ±DDD.D, ±DDDMM.M or
+±DDDMMSS.S longitude.
+*/
+func parseISO6709(text string) (latitude float64, longitude float64, err error) {
+	match := iso6709Pattern.FindStringSubmatch(text)
+	if latitude, err = parseISO6709Component(match[1], 2); err != nil {
+		return 0, 0, err
+	}
+	if longitude, err = parseISO6709Component(match[2], 3); err != nil {
+		return 0, 0, err
+	}
+	return latitude, longitude, nil
+}
+
+func parseISO6709Component(component string, degreeDigits int) (float64, error) {
+	sign, digits := 1.0, component[1:]
+	if component[0] == '-' {
+		sign = -1
+	}
+	integer, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		integer, fraction = digits[:i], digits[i:]
+	}
+
+	var parts []string
+	switch len(integer) {
+	case degreeDigits:
+		parts = []string{integer + fraction}
+	case degreeDigits + 2:
+		parts = []string{integer[:degreeDigits], integer[degreeDigits:] + fraction}
+	case degreeDigits + 4:
+		parts = []string{integer[:degreeDigits], integer[degreeDigits : degreeDigits+2], integer[degreeDigits+2:] + fraction}
+	default:
+		return 0, fmt.Errorf("%s is not an ISO 6709 coordinate", component)
+	}
+
+	numbers := make([]float64, len(parts))
+	for i, part := range parts {
+		numbers[i], _ = strconv.ParseFloat(part, 64)
+	}
+	value, err := sexagesimal(numbers)
+	return sign * value, err
+}
+
+func parseGoogleMapsURL(text string) (latitude float64, longitude float64, err error) {
+	if unescaped, err := url.QueryUnescape(text); err == nil {
+		text = unescaped
+	}
+	for _, pattern := range googleMapsPatterns {
+		if match := pattern.FindStringSubmatch(text); match != nil {
+			latitude, _ = strconv.ParseFloat(match[1], 64)
+			longitude, _ = strconv.ParseFloat(match[2], 64)
+			return latitude, longitude, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("no coordinates in the URL")
+}
+
+/*
+coordinateToken a number or a hemisphere letter of the coordinates, or the comma between the latitude and the
+longitude.
+*/
+type coordinateToken struct {
+	number     float64
+	hemisphere rune
+	separator  bool
+}
+
+/*
+parseNotation parses the decimal degrees and the degrees, minutes and seconds, see ParseCoordinates.
+*/
+func parseNotation(text string) (latitude float64, longitude float64, err error) {
+	tokens, err := tokenizeCoordinates(text)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	first, second, err := splitCoordinates(tokens)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	value1, hemisphere1, err := parseCoordinate(first)
+	if err != nil {
+		return 0, 0, err
+	}
+	value2, hemisphere2, err := parseCoordinate(second)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch {
+	case hemisphere1 == 0 && hemisphere2 == 0:
+		return value1, value2, nil
+	case strings.ContainsRune("NS", hemisphere1) && strings.ContainsRune("EW", hemisphere2):
+		return value1, value2, nil
+	case strings.ContainsRune("EW", hemisphere1) && strings.ContainsRune("NS", hemisphere2):
+		return value2, value1, nil
+	default:
+		return 0, 0, fmt.Errorf("the hemispheres %c and %c are not a latitude and a longitude", hemisphere1, hemisphere2)
+	}
+}
+
+func tokenizeCoordinates(text string) (tokens []coordinateToken, err error) {
+	runes := []rune(strings.ToUpper(text))
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsDigit(r) || r == '.' || r == '+' || r == '-':
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			number, err := strconv.ParseFloat(string(runes[i:j]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s is not a number", string(runes[i:j]))
+			}
+			tokens = append(tokens, coordinateToken{number: number})
+			i = j
+			continue
+		case strings.ContainsRune("NSEW", r) && (i+1 == len(runes) || !unicode.IsLetter(runes[i+1])):
+			tokens = append(tokens, coordinateToken{hemisphere: r})
+		case r == ',' || r == ';':
+			tokens = append(tokens, coordinateToken{separator: true})
+		case unicode.IsSpace(r) || strings.ContainsRune("°º˚'′’\"″”", r):
+		default:
+			return nil, fmt.Errorf("unexpected %q", string(r))
+		}
+		i++
+	}
+	return tokens, nil
+}
+
+/*
+splitCoordinates splits the tokens into the two coordinates: at the hemisphere letters, which may be before or after
+the numbers, else at the comma, else into the halves.
+*/
+func splitCoordinates(tokens []coordinateToken) (first []coordinateToken, second []coordinateToken, err error) {
+	var hemispheres, separators []int
+	for i, token := range tokens {
+		switch {
+		case token.hemisphere != 0:
+			hemispheres = append(hemispheres, i)
+		case token.separator:
+			separators = append(separators, i)
+		}
+	}
+	withoutSeparators := func(tokens []coordinateToken) (result []coordinateToken) {
+		for _, token := range tokens {
+			if !token.separator {
+				result = append(result, token)
+			}
+		}
+		return result
+	}
+
+	switch {
+	case len(hemispheres) == 2:
+		split := hemispheres[0] + 1
+		if hemispheres[0] == 0 {
+			// the hemisphere letters are before the numbers
+			split = hemispheres[1]
+		}
+		first, second = withoutSeparators(tokens[:split]), withoutSeparators(tokens[split:])
+	case len(hemispheres) != 0:
+		return nil, nil, fmt.Errorf("the coordinates need two hemisphere letters or none")
+	case len(separators) == 1:
+		first, second = tokens[:separators[0]], tokens[separators[0]+1:]
+	case len(separators) == 0 && len(tokens)%2 == 0:
+		first, second = tokens[:len(tokens)/2], tokens[len(tokens)/2:]
+	default:
+		return nil, nil, fmt.Errorf("the latitude and the longitude can't be told apart")
+	}
+	return first, second, nil
+}
+
+/*
+parseCoordinate returns the value of the tokens of the degrees, the minutes and the seconds and the hemisphere letter,
+negative for S and W.
+*/
+func parseCoordinate(tokens []coordinateToken) (value float64, hemisphere rune, err error) {
+	var numbers []float64
+	for _, token := range tokens {
+		if token.hemisphere != 0 {
+			hemisphere = token.hemisphere
+		} else {
+			numbers = append(numbers, token.number)
+		}
+	}
+	if len(numbers) == 0 || len(numbers) > 3 {
+		return 0, 0, fmt.Errorf("a coordinate needs the degrees and optionally the minutes and the seconds")
+	}
+
+	sign := 1.0
+	if numbers[0] < 0 || math.Signbit(numbers[0]) {
+		if hemisphere != 0 {
+			return 0, 0, fmt.Errorf("a coordinate can't be both negative and have a hemisphere letter")
+		}
+		sign, numbers[0] = -1, -numbers[0]
+	}
+	if hemisphere == 'S' || hemisphere == 'W' {
+		sign = -1
+	}
+
+	value, err = sexagesimal(numbers)
+	return sign * value, hemisphere, err
+}
+
+/*
+sexagesimal returns the degrees of the non-negative degrees, minutes and seconds, only the last of them may have a
+fraction.
+*/
+func sexagesimal(numbers []float64) (float64, error) {
+	value, unit := 0.0, 1.0
+	for i, number := range numbers {
+		if number < 0 || (i > 0 && number >= 60) {
+			return 0, fmt.Errorf("%v is out of range", number)
+		}
+		if i < len(numbers)-1 && number != math.Trunc(number) {
+			return 0, fmt.Errorf("only the last of the degrees, minutes and seconds may have a fraction")
+		}
+		value += number / unit
+		unit *= 60
+	}
+	return value, nil
+}
@@ -0,0 +1,100 @@
+package calculator
+
+import (
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"time"
+)
+
+/*
+EclipticPosition the apparent geocentric ecliptic position of the sun.
+*/
+type EclipticPosition struct {
+	// Longitude the apparent ecliptic longitude, corrected for the nutation (and the aberration for the sun)
+	Longitude dimension.Degrees
+	// Latitude the ecliptic latitude
+	Latitude dimension.Degrees
+	// Distance the distance from the center of the earth in Astronomical Units (AU)
+	Distance float64
+}
+
+/*
+AstronomicalUnit the length of the Astronomical Unit (AU).
+*/
+const AstronomicalUnit = dimension.KM(149597870.7)
+
+/*
+JulianDay returns the [Julian Day]: http://en.wikipedia.org/wiki/Julian_day (UT) including the fraction of day of the
+time passed in.
+*/
+func JulianDay(tm time.Time) float64 {
+	return julianDayFromTime(tm)
+}
+
+/*
+JulianEphemerisDay returns the Julian Ephemeris Day (TT) of the time passed in, the JulianDay corrected by DeltaT.
+*/
+func JulianEphemerisDay(tm time.Time) float64 {
+	return julianDayFromTime(tm) + DeltaT(tm).Seconds()/86400
+}
+
+/*
+TimeFromJulianEphemerisDay returns the UTC time of the Julian Ephemeris Day jde (TT) corrected by DeltaT, rounded to a
+millisecond. It is the inverse of JulianEphemerisDay.
+*/
+func TimeFromJulianEphemerisDay(jde float64) time.Time {
+	tm := timeFromJulianDay(jde)
+	return timeFromJulianDay(jde - DeltaT(tm).Seconds()/86400)
+}
+
+/*
+timeFromJulianDay returns the UTC time of the Julian Day jd, rounded to a millisecond.
+*/
+func timeFromJulianDay(jd float64) time.Time {
+	millis := math.Round((jd - 2440587.5) * 86400000)
+	return time.UnixMilli(int64(millis)).UTC()
+}
+
+/*
+Nutation returns the nutation in longitude deltaPsi and in obliquity deltaEpsilon for the Julian Ephemeris Day jde
+(Astronomical Algorithms by Jean Meeus, chapter 22).
+*/
+func Nutation(jde float64) (deltaPsi dimension.Degrees, deltaEpsilon dimension.Degrees) {
+	psi, epsilon := nutation(julianCenturiesFromJulianDay(jde))
+	return dimension.Degrees(psi), dimension.Degrees(epsilon)
+}
+
+/*
+TrueObliquityOfEcliptic returns the true obliquity of the ecliptic (including the nutation) for the Julian Ephemeris
+Day jde.
+*/
+func TrueObliquityOfEcliptic(jde float64) dimension.Degrees {
+	jce := julianCenturiesFromJulianDay(jde)
+	_, deltaEpsilon := nutation(jce)
+	return dimension.Degrees(trueObliquityOfEcliptic(jce/10, deltaEpsilon))
+}
+
+/*
+ApparentSunPosition returns the apparent geocentric EclipticPosition of the sun for the Julian Ephemeris Day jde,
+calculated as by the meeusCalculator.
+*/
+func ApparentSunPosition(jde float64) EclipticPosition {
+	sun := meeusApparentSunPosition(jde)
+	return EclipticPosition{Longitude: dimension.Degrees(sun.lambda), Latitude: dimension.Degrees(sun.beta), Distance: sun.r}
+}
+
+/*
+EclipticToEquatorial converts the ecliptic longitude and latitude to the right ascension and declination for the
+obliquity of the ecliptic epsilon.
+*/
+func EclipticToEquatorial(longitude dimension.Degrees, latitude dimension.Degrees, epsilon dimension.Degrees) (rightAscension dimension.Degrees, declination dimension.Degrees) {
+	alpha, delta := eclipticToEquatorial(float64(longitude), float64(latitude), float64(epsilon))
+	return dimension.Degrees(alpha), dimension.Degrees(delta)
+}
+
+/*
+ApparentSiderealTime returns the apparent sidereal time at Greenwich for the Julian Day (UT) jd.
+*/
+func ApparentSiderealTime(jd float64, deltaPsi dimension.Degrees, epsilon dimension.Degrees) dimension.Degrees {
+	return dimension.Degrees(apparentSiderealTime(jd, float64(deltaPsi), float64(epsilon)))
+}
@@ -0,0 +1,72 @@
+package calculator
+
+import (
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"time"
+)
+
+/*
+Season an equinox or a solstice, the instant the apparent geocentric longitude of the sun is a multiple of 90 deg.
+*/
+type Season int
+
+const (
+	// MarchEquinox the apparent longitude of the sun is 0 deg
+	MarchEquinox Season = iota
+	// JuneSolstice the apparent longitude of the sun is 90 deg
+	JuneSolstice
+	// SeptemberEquinox the apparent longitude of the sun is 180 deg
+	SeptemberEquinox
+	// DecemberSolstice the apparent longitude of the sun is 270 deg
+	DecemberSolstice
+)
+
+/*
+EquinoxOrSolstice returns the UTC instant of the season of the Gregorian year passed in (Astronomical Algorithms by
+Jean Meeus, chapter 27). The mean instant of table 27.b is corrected with the apparent longitude of the sun of
+ApparentSunPosition until it converges, which gives an accuracy of about a second for the years 1000 - 3000.
+*/
+func EquinoxOrSolstice(year int, season Season) time.Time {
+	if season < MarchEquinox || season > DecemberSolstice {
+		panic("unknown Season")
+	}
+
+	// mean instant, table 27.b (years 1000 - 3000)
+	y := (float64(year) - 2000) / 1000
+	var jde float64
+	switch season {
+	case MarchEquinox:
+		jde = 2451623.80984 + y*(365242.37404+y*(0.05169+y*(-0.00411-y*0.00057)))
+	case JuneSolstice:
+		jde = 2451716.56767 + y*(365241.62603+y*(0.00325+y*(0.00888-y*0.00030)))
+	case SeptemberEquinox:
+		jde = 2451810.21715 + y*(365242.01767+y*(-0.11575+y*(0.00337+y*0.00078)))
+	case DecemberSolstice:
+		jde = 2451900.05952 + y*(365242.74049+y*(-0.06223+y*(-0.00823+y*0.00032)))
+	}
+
+	longitude := dimension.Degrees(float64(season) * 90)
+	for i := 0; i < 20; i++ {
+		correction := 58 * (longitude - ApparentSunPosition(jde).Longitude).Sin()
+		jde += correction
+		if math.Abs(correction) < 1e-7 {
+			break
+		}
+	}
+
+	return TimeFromJulianEphemerisDay(jde)
+}
+
+/*
+Seasons returns the MarchEquinox, JuneSolstice, SeptemberEquinox and DecemberSolstice of the Gregorian year passed in,
+see EquinoxOrSolstice.
+*/
+func Seasons(year int) [4]time.Time {
+	return [4]time.Time{
+		EquinoxOrSolstice(year, MarchEquinox),
+		EquinoxOrSolstice(year, JuneSolstice),
+		EquinoxOrSolstice(year, SeptemberEquinox),
+		EquinoxOrSolstice(year, DecemberSolstice),
+	}
+}
@@ -19,12 +19,16 @@ type GeoLocation interface {
 	LocationName() string
 	TimeZone() *time.Location
 	StandardTimeOffset() gdt.GMillisecond
+	TimeZoneOffsetAt(tm time.Time) gdt.GMillisecond
 	LocalMeanTimeOffset() gdt.GMillisecond
 	AntimeridianAdjustment() int32
 	GeodesicInitialBearing(location GeoLocation) float64
 	GeodesicFinalBearing(location GeoLocation) float64
 	GeodesicDistance(location GeoLocation) float64
 	RhumbLineDistance(location GeoLocation) float64
+	GeodesicDestination(initialBearing float64, distance float64) (destination GeoLocation, err error)
+	GeodesicMidpoint(location GeoLocation) (midpoint GeoLocation, err error)
+	GeodesicPoints(location GeoLocation, n int) (points []GeoLocation, err error)
 	// SetElevation and other setters
 	//
 	SetElevation(elevation dimension.Meters)
@@ -68,6 +72,8 @@ type geoLocation struct {
 	timeZone *time.Location
 	// elevation the elevation above sea level in dimension.Meters. Elevation is not used in most algorithms used for calculating
 	elevation dimension.Meters
+	// horizonProfile the visible horizon, nil for a flat horizon
+	horizonProfile HorizonProfile
 }
 
 const (
@@ -241,7 +247,7 @@ func (t *geoLocation) SetLongitude2(degrees dimension.Degrees, minutes dimension
 	}
 
 	longTemp := float64(degrees) + ((float64(minutes) + (float64(seconds) / 60.0)) / 60.0)
-	if longTemp > 180 || t.longitude < 0 {
+	if longTemp > 180 || longTemp < 0 {
 		panic("Longitude must be between 0 and  180.  Use a direction of W instead of negative.")
 	}
 
@@ -273,9 +279,18 @@ func (t *geoLocation) SetTimeZone(timeZone *time.Location) {
 	t.timeZone = timeZone
 }
 
+func (t *geoLocation) HorizonProfile() HorizonProfile {
+	return t.horizonProfile
+}
+
+func (t *geoLocation) SetHorizonProfile(horizonProfile HorizonProfile) {
+	t.horizonProfile = horizonProfile
+}
+
 /*
 StandardTimeOffset returns the amount of time in milliseconds to add to UTC to get standard time in this time zone
 See TimeZone.java public abstract int getRawOffset()
+It is the StandardTimeOffsetAt of now, use StandardTimeOffsetAt for the standard time of a date.
 */
 func (t *geoLocation) StandardTimeOffset() gdt.GMillisecond {
 	/*
@@ -285,11 +300,34 @@ func (t *geoLocation) StandardTimeOffset() gdt.GMillisecond {
 		   return int((now.utcoffset() - now.dst()).total_seconds()) * 1000
 
 	*/
-	now := time.Now() // Now returns the current local time
-	_, destOffset := now.In(t.TimeZone()).Zone()
-	_, utcOffset := now.UTC().Zone()
+	return StandardTimeOffsetAt(t, time.Now())
+}
 
-	return gdt.GSecond(destOffset - utcOffset).ToMilliseconds()
+/*
+StandardTimeOffsetAt returns the amount of time in milliseconds to add to UTC to get standard time in the time zone of
+the geoLocation in the year of the tm. The offset of the daylight saving time is not added, whether it is in effect at
+the tm or not. It is the offset of January or of July of the year, of the one that isn't the daylight saving time, so
+that both hemispheres are supported.
+*/
+func StandardTimeOffsetAt(geoLocation GeoLocation, tm time.Time) gdt.GMillisecond {
+	timeZone := timeutil.TimeZoneOrGmt(geoLocation.TimeZone())
+	year := tm.In(timeZone).Year()
+	standardTime := time.Date(year, time.January, 1, 0, 0, 0, 0, timeZone)
+	if standardTime.IsDST() {
+		standardTime = time.Date(year, time.July, 1, 0, 0, 0, 0, timeZone)
+	}
+	_, offset := standardTime.Zone()
+
+	return gdt.GSecond(offset).ToMilliseconds()
+}
+
+/*
+TimeZoneOffsetAt returns the amount of time in milliseconds to add to UTC to get the local time in this time zone at
+the tm, including the daylight saving time offset if it is in effect then.
+*/
+func (t *geoLocation) TimeZoneOffsetAt(tm time.Time) gdt.GMillisecond {
+	_, offset := tm.In(t.TimeZone()).Zone()
+	return gdt.GSecond(offset).ToMilliseconds()
 }
 
 /*
@@ -370,77 +408,17 @@ this Object and a second Object passed to this method using [Thaddeus Vincenty's
 [Direct and Inverse Solutions of Geodesics on the Ellipsoid with application of nested equations]: https://www.ngs.noaa.gov/PUBS_LIB/inverse.pdf, Survey Review, vol XXII no 176, 1975
 */
 func (t *geoLocation) VincentyFormula(location GeoLocation, formula int32) float64 {
-	var a = 6378137.0
-	var b = 6356752.3142
-	var f = 1 / 298.257223563 // WGS-84 ellipsiod
-	var L = float64(dimension.Degrees(location.Longitude() - t.Longitude()).ToRadians())
-	var U1 = math.Atan((1 - f) * math.Tan(float64(dimension.Degrees(t.Latitude()).ToRadians())))
-	var U2 = math.Atan((1 - f) * math.Tan(float64(dimension.Degrees(location.Latitude()).ToRadians())))
-	var sinU1 = math.Sin(U1)
-	var cosU1 = math.Cos(U1)
-	var sinU2 = math.Sin(U2)
-	var cosU2 = math.Cos(U2)
-
-	lambda := L
-	lambdaP := 2 * math.Pi
-	var iterLimit float64 = 20
-	var sinLambda float64 = 0
-	var cosLambda float64 = 0
-	var sinSigma float64 = 0
-	var cosSigma float64 = 0
-	var sigma float64 = 0
-	var sinAlpha float64 = 0
-	var cosSqAlpha float64 = 0
-	var cos2SigmaM float64 = 0
-	var C float64
-
-	// while (Math.abs(lambda - lambdaP) > 1e-12 && --iterLimit > 0) {
-	for math.Abs(lambda-lambdaP) > 1e-12 {
-
-		iterLimit--
-		if iterLimit <= 0 {
-			break
-		}
-
-		sinLambda = math.Sin(lambda)
-		cosLambda = math.Cos(lambda)
-		sinSigma = math.Sqrt((cosU2*sinLambda)*(cosU2*sinLambda) + (cosU1*sinU2-sinU1*cosU2*cosLambda)*(cosU1*sinU2-sinU1*cosU2*cosLambda))
-		if sinSigma == 0 {
-			return 0 // co-incident points
-		}
-		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
-		sigma = math.Atan2(sinSigma, cosSigma)
-		sinAlpha = cosU1 * cosU2 * sinLambda / sinSigma
-		cosSqAlpha = 1 - sinAlpha*sinAlpha
-		cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
-		if math.IsNaN(cos2SigmaM) {
-			cos2SigmaM = 0 // equatorial line: cosSqAlpha=0 (§6)
-		}
-		C = f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
-		lambdaP = lambda
-		lambda = L + (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
-	}
-
-	if iterLimit == 0 {
+	distance, initialBearing, finalBearing, err := vincentyInverse(t.Latitude(), t.Longitude(), location.Latitude(), location.Longitude(), vincentyFormulaIterationLimit)
+	if err != nil {
 		return math.NaN() // formula failed to converge
 	}
 
-	uSq := cosSqAlpha * (a*a - b*b) / (b * b)
-	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
-	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
-	deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
-	distance := b * A * (sigma - deltaSigma)
-
-	// initial bearing
-	fwdAz := float64(dimension.Radians(math.Atan2(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)).ToDegrees())
-	// final bearing
-	revAz := float64(dimension.Radians(math.Atan2(cosU1*sinLambda, -sinU1*cosU2+cosU1*sinU2*cosLambda)).ToDegrees())
 	if formula == Distance {
 		return distance
 	} else if formula == InitialBearing {
-		return fwdAz
+		return initialBearing
 	} else if formula == FinalBearing {
-		return revAz
+		return finalBearing
 	} else { // should never happen
 		return math.NaN()
 	}
@@ -469,20 +447,21 @@ func (t *geoLocation) RhumbLineDistance(location GeoLocation) float64 {
 	return d * earthRadius
 }
 
-func (t *geoLocation) String() string {
-	/*
-			StringBuilder sb = new StringBuilder();
-		sb.append("\nLocation Name:\t\t\t").append(getLocationName());
-		sb.append("\nLatitude:\t\t\t").append(getLatitude()).append("\u00B0");
-		sb.append("\nLongitude:\t\t\t").append(getLongitude()).append("\u00B0");
-		sb.append("\nElevation:\t\t\t").append(getElevation()).append(" Meters");
-		sb.append("\nTimezone ID:\t\t\t").append(getTimeZone().getID());
-		sb.append("\nTimezone Display Name:\t\t").append(getTimeZone().getDisplayName())
-				.append(" (").append(getTimeZone().getDisplayName(false, TimeZone.SHORT)).append(")");
-		sb.append("\nTimezone GMT Offset:\t\t").append(getTimeZone().getRawOffset() / HOUR_MILLIS);
-		sb.append("\nTimezone DST Offset:\t\t").append(getTimeZone().getDSTSavings() / HOUR_MILLIS);
-		return sb.toString();
+/*
+Format returns the coordinates in the notation, see FormatCoordinates.
+*/
+func (t *geoLocation) Format(notation CoordinateNotation) string {
+	return FormatCoordinates(t.latitude, t.longitude, notation)
+}
 
-	*/
-	return "" //fmt.Sprintf("%b", b)
+/*
+String returns the location name followed by the coordinates in the NotationDecimal, such as
+Lakewood, NJ (40.095600, -74.222000), or only the coordinates if the location has no name. Format renders the
+coordinates in another notation.
+*/
+func (t *geoLocation) String() string {
+	if t.locationName == "" {
+		return t.Format(NotationDecimal)
+	}
+	return fmt.Sprintf("%s (%s)", t.locationName, t.Format(NotationDecimal))
 }
@@ -0,0 +1,211 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+)
+
+const (
+	// wgs84A the semi-major axis of the WGS-84 ellipsoid in meters
+	wgs84A = 6378137.0
+	// wgs84B the semi-minor axis of the WGS-84 ellipsoid in meters
+	wgs84B = 6356752.3142
+	// wgs84F the flattening of the WGS-84 ellipsoid
+	wgs84F = 1 / 298.257223563
+	// vincentyIterationLimit the iterations of Vincenty's formulae before they are reported as not converging
+	vincentyIterationLimit = 200
+	// vincentyFormulaIterationLimit the iterations of the GeoLocation.VincentyFormula, as the iteration limit 20 of
+	// KosherJava, which counts down to 0 before the first iteration, so that its results don't change
+	vincentyFormulaIterationLimit = 19
+)
+
+/*
+ErrVincentyNotConverged the error of Vincenty's formulae that don't converge, which happens for the nearly antipodal
+points of the inverse problem.
+*/
+var ErrVincentyNotConverged = errors.New("vincenty formula failed to converge")
+
+/*
+VincentyInverse solves the inverse geodesic problem on the WGS-84 ellipsoid with [Thaddeus Vincenty's]: https://en.wikipedia.org/wiki/Thaddeus_Vincenty
+inverse formula, see [Direct and Inverse Solutions of Geodesics on the Ellipsoid with application of nested equations]: https://www.ngs.noaa.gov/PUBS_LIB/inverse.pdf,
+Survey Review, vol XXII no 176, 1975. It returns the distance in meters and the initial and the final bearings in
+degrees between the points, and ErrVincentyNotConverged for the nearly antipodal points. The bearings of the
+co-incident points are 0.
+Up to 200 iterations are done, so that some of the nearly antipodal points, for which the GeoLocation.GeodesicDistance,
+of the 19 iterations of KosherJava, is NaN, converge.
+*/
+func VincentyInverse(latitude1 float64, longitude1 float64, latitude2 float64, longitude2 float64) (distance float64, initialBearing float64, finalBearing float64, err error) {
+	return vincentyInverse(latitude1, longitude1, latitude2, longitude2, vincentyIterationLimit)
+}
+
+func vincentyInverse(latitude1 float64, longitude1 float64, latitude2 float64, longitude2 float64, iterationLimit int) (distance float64, initialBearing float64, finalBearing float64, err error) {
+	L := float64(dimension.Degrees(longitude2 - longitude1).ToRadians())
+	U1 := math.Atan((1 - wgs84F) * math.Tan(float64(dimension.Degrees(latitude1).ToRadians())))
+	U2 := math.Atan((1 - wgs84F) * math.Tan(float64(dimension.Degrees(latitude2).ToRadians())))
+	sinU1, cosU1 := math.Sin(U1), math.Cos(U1)
+	sinU2, cosU2 := math.Sin(U2), math.Cos(U2)
+
+	lambda := L
+	lambdaP := 2 * math.Pi
+	var sinLambda, cosLambda, sinSigma, cosSigma, sigma, sinAlpha, cosSqAlpha, cos2SigmaM float64
+	converged := false
+	for i := 0; i < iterationLimit; i++ {
+		sinLambda = math.Sin(lambda)
+		cosLambda = math.Cos(lambda)
+		sinSigma = math.Sqrt((cosU2*sinLambda)*(cosU2*sinLambda) + (cosU1*sinU2-sinU1*cosU2*cosLambda)*(cosU1*sinU2-sinU1*cosU2*cosLambda))
+		if sinSigma == 0 {
+			return 0, 0, 0, nil // co-incident points
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha = cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		if math.IsNaN(cos2SigmaM) {
+			cos2SigmaM = 0 // equatorial line: cosSqAlpha=0 (§6)
+		}
+		C := wgs84F / 16 * cosSqAlpha * (4 + wgs84F*(4-3*cosSqAlpha))
+		lambdaP = lambda
+		lambda = L + (1-C)*wgs84F*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-lambdaP) <= 1e-12 {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return math.NaN(), math.NaN(), math.NaN(), fmt.Errorf("%w: from %v, %v to %v, %v", ErrVincentyNotConverged, latitude1, longitude1, latitude2, longitude2)
+	}
+
+	uSq := cosSqAlpha * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+	distance = wgs84B * A * (sigma - deltaSigma)
+
+	initialBearing = float64(dimension.Radians(math.Atan2(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)).ToDegrees())
+	finalBearing = float64(dimension.Radians(math.Atan2(cosU1*sinLambda, -sinU1*cosU2+cosU1*sinU2*cosLambda)).ToDegrees())
+	return distance, initialBearing, finalBearing, nil
+}
+
+/*
+VincentyDirect solves the direct geodesic problem on the WGS-84 ellipsoid with Thaddeus Vincenty's direct formula (see
+VincentyInverse): the point at the distance in meters from the point of the latitude and the longitude along the
+geodesic of the initial bearing in degrees, and the final bearing of the geodesic at it. The longitude is from -180 to
+180 deg. It returns ErrVincentyNotConverged if the formula fails to converge.
+A panic will be if the distance is negative or not finite.
+*/
+func VincentyDirect(latitude float64, longitude float64, initialBearing float64, distance float64) (latitude2 float64, longitude2 float64, finalBearing float64, err error) {
+	if distance < 0 || math.IsNaN(distance) || math.IsInf(distance, 0) {
+		panic(fmt.Sprintf("distance %v is not a finite non-negative number", distance))
+	}
+
+	alpha1 := float64(dimension.Degrees(initialBearing).ToRadians())
+	sinAlpha1, cosAlpha1 := math.Sin(alpha1), math.Cos(alpha1)
+
+	tanU1 := (1 - wgs84F) * math.Tan(float64(dimension.Degrees(latitude).ToRadians()))
+	cosU1 := 1 / math.Sqrt(1+tanU1*tanU1)
+	sinU1 := tanU1 * cosU1
+	sigma1 := math.Atan2(tanU1, cosAlpha1)
+	sinAlpha := cosU1 * sinAlpha1
+	cosSqAlpha := 1 - sinAlpha*sinAlpha
+	uSq := cosSqAlpha * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+
+	sigma := distance / (wgs84B * A)
+	var sinSigma, cosSigma, cos2SigmaM float64
+	converged := false
+	for i := 0; i < vincentyIterationLimit; i++ {
+		cos2SigmaM = math.Cos(2*sigma1 + sigma)
+		sinSigma, cosSigma = math.Sin(sigma), math.Cos(sigma)
+		deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+		sigmaP := sigma
+		sigma = distance/(wgs84B*A) + deltaSigma
+		if math.Abs(sigma-sigmaP) <= 1e-12 {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return math.NaN(), math.NaN(), math.NaN(), fmt.Errorf("%w: from %v, %v bearing %v distance %v", ErrVincentyNotConverged, latitude, longitude, initialBearing, distance)
+	}
+	cos2SigmaM = math.Cos(2*sigma1 + sigma)
+	sinSigma, cosSigma = math.Sin(sigma), math.Cos(sigma)
+
+	x := sinU1*sinSigma - cosU1*cosSigma*cosAlpha1
+	phi2 := math.Atan2(sinU1*cosSigma+cosU1*sinSigma*cosAlpha1, (1-wgs84F)*math.Sqrt(sinAlpha*sinAlpha+x*x))
+	lambda := math.Atan2(sinSigma*sinAlpha1, cosU1*cosSigma-sinU1*sinSigma*cosAlpha1)
+	C := wgs84F / 16 * cosSqAlpha * (4 + wgs84F*(4-3*cosSqAlpha))
+	L := lambda - (1-C)*wgs84F*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+	longitude2 = math.Mod(longitude+float64(dimension.Radians(L).ToDegrees())+540, 360) - 180
+	finalBearing = float64(dimension.Radians(math.Atan2(sinAlpha, -x)).ToDegrees())
+	return float64(dimension.Radians(phi2).ToDegrees()), longitude2, finalBearing, nil
+}
+
+/*
+GeodesicDestination returns the GeoLocation at the distance in meters from this location along the geodesic of the
+initialBearing in degrees, see VincentyDirect. The destination has the time zone of this location, no name and the
+elevation 0.
+*/
+func (t *geoLocation) GeodesicDestination(initialBearing float64, distance float64) (destination GeoLocation, err error) {
+	latitude, longitude, _, err := VincentyDirect(t.Latitude(), t.Longitude(), initialBearing, distance)
+	if err != nil {
+		return nil, err
+	}
+	return t.geodesicPoint(latitude, longitude), nil
+}
+
+/*
+GeodesicMidpoint returns the GeoLocation halfway along the geodesic from this location to the location passed in, as
+GeodesicDestination.
+*/
+func (t *geoLocation) GeodesicMidpoint(location GeoLocation) (midpoint GeoLocation, err error) {
+	points, err := t.GeodesicPoints(location, 3)
+	if err != nil {
+		return nil, err
+	}
+	return points[1], nil
+}
+
+/*
+GeodesicPoints returns the n points evenly spaced along the geodesic from this location to the location passed in,
+the first and the last points are the copies of the two locations, the ones in between are as GeodesicDestination.
+A panic will be if n < 2.
+*/
+func (t *geoLocation) GeodesicPoints(location GeoLocation, n int) (points []GeoLocation, err error) {
+	if n < 2 {
+		panic(fmt.Sprintf("n = %d < 2", n))
+	}
+
+	distance, initialBearing, _, err := VincentyInverse(t.Latitude(), t.Longitude(), location.Latitude(), location.Longitude())
+	if err != nil {
+		return nil, err
+	}
+
+	points = make([]GeoLocation, n)
+	points[0], points[n-1] = copyGeoLocation(t), copyGeoLocation(location)
+	for i := 1; i < n-1; i++ {
+		if points[i], err = t.GeodesicDestination(initialBearing, distance*float64(i)/float64(n-1)); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func (t *geoLocation) geodesicPoint(latitude float64, longitude float64) GeoLocation {
+	return NewGeoLocation1("", latitude, longitude, t.TimeZone())
+}
+
+/*
+copyGeoLocation returns a copy of the location, so that the modifications of either don't change the other.
+*/
+func copyGeoLocation(location GeoLocation) GeoLocation {
+	if l, ok := location.(*geoLocation); ok {
+		c := *l
+		return &c
+	}
+	return NewGeoLocation2(location.LocationName(), location.Latitude(), location.Longitude(), location.Elevation(), location.TimeZone())
+}
@@ -0,0 +1,190 @@
+package calculator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+HorizonPoint the altitude of the visible horizon (the terrain) in the direction of the azimuth.
+*/
+type HorizonPoint struct {
+	// Azimuth the direction from the true north eastward, from 0 to 360 deg exclusive
+	Azimuth dimension.Degrees `json:"azimuth"`
+	// Altitude the angle of the horizon above (or below, if negative) the astronomical horizon of the observer
+	Altitude dimension.Degrees `json:"altitude"`
+}
+
+/*
+HorizonProfile the visible horizon of a GeoLocation, see HorizonGeoLocation.SetHorizonProfile. The altitude of the horizon is
+linearly interpolated between the HorizonPoint azimuths, wrapping around the north.
+The altitudes are measured from the astronomical horizon of the observer, so that they include the dip of the
+horizon for the elevation, and a horizon lower than the observer (the sea seen from a mountain) is negative.
+*/
+type HorizonProfile interface {
+	// Altitude returns the altitude of the horizon in the direction of the azimuth
+	Altitude(azimuth dimension.Degrees) dimension.Degrees
+	// Points returns the HorizonPoint of the profile ordered by azimuth
+	Points() []HorizonPoint
+}
+
+/*
+HorizonGeoLocation a GeoLocation of a visible horizon. The GeoLocation of NewGeoLocation, NewGeoLocation1 and
+NewGeoLocation2 implements it. The methods are not in the GeoLocation, so that its implementations outside this
+package don't have to implement them. See HorizonProfileOf.
+*/
+type HorizonGeoLocation interface {
+	GeoLocation
+	/*
+		HorizonProfile returns the visible horizon of the location, nil for a flat horizon. See SetHorizonProfile.
+	*/
+	HorizonProfile() HorizonProfile
+	/*
+		SetHorizonProfile sets the visible horizon of the location (for instance the mountains around it), nil for a flat
+		horizon (the default). When it is set, the sunrise and the sunset are the moment the upper limb of the sun clears
+		the horizonProfile instead of the flat horizon adjusted for the elevation.
+	*/
+	SetHorizonProfile(horizonProfile HorizonProfile)
+}
+
+/*
+HorizonProfileOf returns the HorizonProfile of the geoLocation if it is a HorizonGeoLocation, otherwise nil for a flat
+horizon.
+*/
+func HorizonProfileOf(geoLocation GeoLocation) HorizonProfile {
+	if h, ok := geoLocation.(HorizonGeoLocation); ok {
+		return h.HorizonProfile()
+	}
+	return nil
+}
+
+type horizonProfile struct {
+	points []HorizonPoint
+}
+
+/*
+NewHorizonProfile returns the HorizonProfile of the points passed in. A panic will be if there are no points, an
+azimuth is not from 0 to 360 deg exclusive or repeated, or an altitude is not between -90 and 90 deg exclusive.
+*/
+func NewHorizonProfile(points []HorizonPoint) HorizonProfile {
+	t, err := newHorizonProfile(points)
+	if err != nil {
+		panic(err.Error())
+	}
+	return t
+}
+
+func newHorizonProfile(points []HorizonPoint) (HorizonProfile, error) {
+	if len(points) == 0 {
+		return nil, fmt.Errorf("the horizon profile has no points")
+	}
+
+	sorted := append([]HorizonPoint(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Azimuth < sorted[j].Azimuth })
+	for i, point := range sorted {
+		if math.IsNaN(float64(point.Azimuth)) || point.Azimuth < 0 || point.Azimuth >= 360 {
+			return nil, fmt.Errorf("azimuth %v is not from 0 to 360", point.Azimuth)
+		}
+		if math.IsNaN(float64(point.Altitude)) || point.Altitude <= -90 || point.Altitude >= 90 {
+			return nil, fmt.Errorf("altitude %v is not between -90 and 90", point.Altitude)
+		}
+		if i > 0 && sorted[i-1].Azimuth == point.Azimuth {
+			return nil, fmt.Errorf("azimuth %v is repeated", point.Azimuth)
+		}
+	}
+
+	return &horizonProfile{points: sorted}, nil
+}
+
+func (t *horizonProfile) Points() []HorizonPoint {
+	return append([]HorizonPoint(nil), t.points...)
+}
+
+func (t *horizonProfile) Altitude(azimuth dimension.Degrees) dimension.Degrees {
+	azimuth = dimension.Degrees(math.Mod(math.Mod(float64(azimuth), 360)+360, 360))
+
+	// the first point with the azimuth after the one passed in, the previous point wraps around the north
+	i := sort.Search(len(t.points), func(i int) bool { return t.points[i].Azimuth > azimuth })
+	previous, next := t.points[(i+len(t.points)-1)%len(t.points)], t.points[i%len(t.points)]
+
+	span := math.Mod(float64(next.Azimuth-previous.Azimuth)+360, 360)
+	if span == 0 {
+		return previous.Altitude
+	}
+	fraction := math.Mod(float64(azimuth-previous.Azimuth)+360, 360) / span
+	return previous.Altitude + dimension.Degrees(fraction)*(next.Altitude-previous.Altitude)
+}
+
+/*
+ReadHorizonProfileCSV reads a HorizonProfile from the CSV of the lines azimuth,altitude in degrees. An optional header
+line and the lines starting with # are skipped.
+*/
+func ReadHorizonProfileCSV(r io.Reader) (HorizonProfile, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var points []HorizonPoint
+	for i, record := range records {
+		azimuth, azimuthErr := strconv.ParseFloat(strings.TrimSpace(record[0]), 64)
+		altitude, altitudeErr := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if azimuthErr != nil || altitudeErr != nil {
+			if i == 0 {
+				// the header
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %v is not azimuth,altitude", i+1, record)
+		}
+		points = append(points, HorizonPoint{Azimuth: dimension.Degrees(azimuth), Altitude: dimension.Degrees(altitude)})
+	}
+
+	return newHorizonProfile(points)
+}
+
+/*
+ReadHorizonProfileJSON reads a HorizonProfile from the JSON array of the HorizonPoint objects, such as
+[{"azimuth": 90, "altitude": 1.5}, {"azimuth": 270, "altitude": 0.2}].
+*/
+func ReadHorizonProfileJSON(r io.Reader) (HorizonProfile, error) {
+	var points []HorizonPoint
+	if err := json.NewDecoder(r).Decode(&points); err != nil {
+		return nil, err
+	}
+	return newHorizonProfile(points)
+}
+
+/*
+LoadHorizonProfile loads a HorizonProfile from the .csv (see ReadHorizonProfileCSV) or the .json
+(see ReadHorizonProfileJSON) file.
+*/
+func LoadHorizonProfile(path string) (HorizonProfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ReadHorizonProfileCSV(file)
+	case ".json":
+		return ReadHorizonProfileJSON(file)
+	default:
+		return nil, fmt.Errorf("%s is not a .csv or a .json file", path)
+	}
+}
@@ -0,0 +1,308 @@
+package calculator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+ErrInvalidLocation the error of the latitude, the longitude or the elevation out of their range, or of the time zone
+that can't be loaded.
+*/
+var ErrInvalidLocation = errors.New("invalid location")
+
+/*
+Location the immutable value of a location, the name, the coordinates, the elevation and the time zone. Unlike the
+GeoLocation, it may be shared by the calendars of many goroutines, and the modifiers return a modified copy. It is
+validated by NewLocation, which returns the errors instead of the panics of the setters of the GeoLocation. The zero
+Location is Null Island at the sea level in UTC.
+*/
+type Location struct {
+	name      string
+	latitude  float64
+	longitude float64
+	elevation dimension.Meters
+	timeZone  *time.Location
+}
+
+/*
+NewLocation returns the Location of the latitude from -90 to 90 deg, the longitude from -180 to 180 deg and the
+non-negative elevation in the timeZone, nil for UTC. The timeZone must be loadable by its name, as the IANA time zones
+of time.LoadLocation, so that the Location can be unmarshalled from the MarshalJSON and the MarshalText, unlike a
+time.FixedZone. The error wraps ErrInvalidLocation.
+*/
+func NewLocation(name string, latitude float64, longitude float64, elevation dimension.Meters, timeZone *time.Location) (Location, error) {
+	if err := validateLatitude(latitude); err != nil {
+		return Location{}, err
+	}
+	if err := validateLongitude(longitude); err != nil {
+		return Location{}, err
+	}
+	if err := validateElevation(elevation); err != nil {
+		return Location{}, err
+	}
+	if err := validateTimeZone(timeZone); err != nil {
+		return Location{}, err
+	}
+	return Location{name: name, latitude: latitude, longitude: longitude, elevation: elevation, timeZone: timeZone}, nil
+}
+
+/*
+NewLocation1 returns the Location as NewLocation in the IANA time zone of the timeZoneName, such as Asia/Jerusalem, ""
+for UTC.
+*/
+func NewLocation1(name string, latitude float64, longitude float64, elevation dimension.Meters, timeZoneName string) (Location, error) {
+	timeZone, err := loadTimeZone(timeZoneName)
+	if err != nil {
+		return Location{}, err
+	}
+	return NewLocation(name, latitude, longitude, elevation, timeZone)
+}
+
+/*
+LocationOf returns the Location of the name, the coordinates, the elevation and the time zone of the geoLocation.
+*/
+func LocationOf(geoLocation GeoLocation) (Location, error) {
+	return NewLocation(geoLocation.LocationName(), geoLocation.Latitude(), geoLocation.Longitude(), geoLocation.Elevation(), geoLocation.TimeZone())
+}
+
+func (l Location) Name() string {
+	return l.name
+}
+
+func (l Location) Latitude() float64 {
+	return l.latitude
+}
+
+func (l Location) Longitude() float64 {
+	return l.longitude
+}
+
+func (l Location) Elevation() dimension.Meters {
+	return l.elevation
+}
+
+/*
+TimeZone returns the time zone, time.UTC if it is not set.
+*/
+func (l Location) TimeZone() *time.Location {
+	if l.timeZone == nil {
+		return time.UTC
+	}
+	return l.timeZone
+}
+
+/*
+TimeZoneName returns the IANA name of the TimeZone, such as Asia/Jerusalem.
+*/
+func (l Location) TimeZoneName() string {
+	return l.TimeZone().String()
+}
+
+/*
+GeoLocation returns a new GeoLocation of the Location, so that the calendars don't share it. The calendars of a
+Location, such as of zmanim.NewZmanimCalendar1, have their own GeoLocation of it.
+*/
+func (l Location) GeoLocation() GeoLocation {
+	return NewGeoLocation2(l.name, l.latitude, l.longitude, l.elevation, l.TimeZone())
+}
+
+/*
+Equal returns if the locations have the same name, coordinates, elevation and time zone name.
+*/
+func (l Location) Equal(location Location) bool {
+	return l.name == location.name &&
+		l.latitude == location.latitude &&
+		l.longitude == location.longitude &&
+		l.elevation == location.elevation &&
+		l.TimeZoneName() == location.TimeZoneName()
+}
+
+func (l Location) WithName(name string) Location {
+	l.name = name
+	return l
+}
+
+func (l Location) WithLatitude(latitude float64) (Location, error) {
+	if err := validateLatitude(latitude); err != nil {
+		return Location{}, err
+	}
+	l.latitude = latitude
+	return l, nil
+}
+
+func (l Location) WithLongitude(longitude float64) (Location, error) {
+	if err := validateLongitude(longitude); err != nil {
+		return Location{}, err
+	}
+	l.longitude = longitude
+	return l, nil
+}
+
+func (l Location) WithCoordinates(latitude float64, longitude float64) (Location, error) {
+	l, err := l.WithLatitude(latitude)
+	if err != nil {
+		return Location{}, err
+	}
+	return l.WithLongitude(longitude)
+}
+
+func (l Location) WithElevation(elevation dimension.Meters) (Location, error) {
+	if err := validateElevation(elevation); err != nil {
+		return Location{}, err
+	}
+	l.elevation = elevation
+	return l, nil
+}
+
+/*
+WithTimeZone returns the Location in the timeZone, nil for UTC, validated as NewLocation.
+*/
+func (l Location) WithTimeZone(timeZone *time.Location) (Location, error) {
+	if err := validateTimeZone(timeZone); err != nil {
+		return Location{}, err
+	}
+	l.timeZone = timeZone
+	return l, nil
+}
+
+/*
+String returns the name followed by the coordinates in the NotationDecimal, such as Jerusalem, Israel (31.778116,
+35.233804), or only the coordinates if the Location has no name.
+*/
+func (l Location) String() string {
+	coordinates := FormatCoordinates(l.latitude, l.longitude, NotationDecimal)
+	if l.name == "" {
+		return coordinates
+	}
+	return fmt.Sprintf("%s (%s)", l.name, coordinates)
+}
+
+/*
+locationJSON the JSON of the Location.
+*/
+type locationJSON struct {
+	Name      string  `json:"name,omitempty"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Elevation float64 `json:"elevation"`
+	TimeZone  string  `json:"timeZone"`
+}
+
+/*
+MarshalJSON returns the JSON object of the name, latitude, longitude, elevation and the IANA timeZone name, such as
+{"name":"Jerusalem","latitude":31.7781,"longitude":35.2338,"elevation":754,"timeZone":"Asia/Jerusalem"}.
+*/
+func (l Location) MarshalJSON() ([]byte, error) {
+	return json.Marshal(locationJSON{
+		Name:      l.name,
+		Latitude:  l.latitude,
+		Longitude: l.longitude,
+		Elevation: float64(l.elevation),
+		TimeZone:  l.TimeZoneName(),
+	})
+}
+
+/*
+UnmarshalJSON sets the Location to the JSON of MarshalJSON, validated as NewLocation1.
+*/
+func (l *Location) UnmarshalJSON(data []byte) error {
+	var j locationJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	location, err := NewLocation1(j.Name, j.Latitude, j.Longitude, dimension.Meters(j.Elevation), j.TimeZone)
+	if err != nil {
+		return err
+	}
+	*l = location
+	return nil
+}
+
+/*
+MarshalText returns the text of the comma separated latitude, longitude, elevation, IANA time zone name and name, such
+as 31.7781,35.2338,754,Asia/Jerusalem,Jerusalem. The name is the last, so that it may contain the commas.
+*/
+func (l Location) MarshalText() ([]byte, error) {
+	return []byte(strings.Join([]string{
+		strconv.FormatFloat(l.latitude, 'f', -1, 64),
+		strconv.FormatFloat(l.longitude, 'f', -1, 64),
+		strconv.FormatFloat(float64(l.elevation), 'f', -1, 64),
+		l.TimeZoneName(),
+		l.name,
+	}, ",")), nil
+}
+
+/*
+UnmarshalText sets the Location to the text of MarshalText, validated as NewLocation1.
+*/
+func (l *Location) UnmarshalText(text []byte) error {
+	fields := strings.SplitN(string(text), ",", 5)
+	if len(fields) != 5 {
+		return fmt.Errorf("%w: %q is not latitude,longitude,elevation,timeZone,name", ErrInvalidLocation, text)
+	}
+
+	var values [3]float64
+	for i, field := range fields[:3] {
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return fmt.Errorf("%w: %q is not a number", ErrInvalidLocation, field)
+		}
+		values[i] = value
+	}
+
+	location, err := NewLocation1(fields[4], values[0], values[1], dimension.Meters(values[2]), strings.TrimSpace(fields[3]))
+	if err != nil {
+		return err
+	}
+	*l = location
+	return nil
+}
+
+func validateLatitude(latitude float64) error {
+	if !(latitude >= -90 && latitude <= 90) {
+		return fmt.Errorf("%w: the latitude %v is not between -90 and 90", ErrInvalidLocation, latitude)
+	}
+	return nil
+}
+
+func validateLongitude(longitude float64) error {
+	if !(longitude >= -180 && longitude <= 180) {
+		return fmt.Errorf("%w: the longitude %v is not between -180 and 180", ErrInvalidLocation, longitude)
+	}
+	return nil
+}
+
+func validateElevation(elevation dimension.Meters) error {
+	if !(elevation >= 0) || math.IsInf(float64(elevation), 1) {
+		return fmt.Errorf("%w: the elevation %v is not a finite non-negative number", ErrInvalidLocation, elevation)
+	}
+	return nil
+}
+
+func validateTimeZone(timeZone *time.Location) error {
+	if timeZone == nil {
+		return nil
+	}
+	if _, err := time.LoadLocation(timeZone.String()); err != nil {
+		return fmt.Errorf("%w: the time zone %q can't be loaded by its name: %v", ErrInvalidLocation, timeZone, err)
+	}
+	return nil
+}
+
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	timeZone, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLocation, err)
+	}
+	return timeZone, nil
+}
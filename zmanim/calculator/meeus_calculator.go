@@ -0,0 +1,209 @@
+package calculator
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"time"
+)
+
+/*
+meeusCalculator implementation of sunrise and sunset methods to calculate astronomical times based on
+[Astronomical Algorithms]: http://www.willbell.com/math/mc1.htm by [Jean Meeus]: http://en.wikipedia.org/wiki/Jean_Meeus.
+The sun's apparent position is calculated from the VSOP87 theory (chapter 32), converted to the FK5 system, with the
+complete nutation of chapter 22 and the aberration correction of chapter 25. The times of rising and setting are
+calculated by the iterative method of chapter 15, interpolating the position of the day before, the day and the day
+after. The difference between the Terrestrial Time used by the theory and the Universal Time is taken from the
+DeltaT model, so unlike the noaaCalculator, which uses fixed julian century approximations, the results remain
+valid for historical dates (well before 1900) and far-future dates.
+Added to the algorithm is an adjustment of the zenith to account for elevation.
+*/
+type meeusCalculator struct {
+	astronomicalCalculator
+}
+
+func newMeeusCalculator() *meeusCalculator {
+	return &meeusCalculator{}
+}
+
+func NewMeeusCalculator() AstronomicalCalculator {
+	t := newMeeusCalculator()
+
+	t.initAstronomicalCalculator()
+
+	return t
+}
+
+func (t *meeusCalculator) CalculatorName() string {
+	return "Jean Meeus Astronomical Algorithms"
+}
+
+/*
+SunHorizontalPosition returns the horizontal position of the sun as the package SunHorizontalPosition, see
+SunPositionCalculator.
+*/
+func (t *meeusCalculator) SunHorizontalPosition(tm time.Time, geoLocation GeoLocation) (altitude dimension.Degrees, azimuth dimension.Degrees) {
+	return SunHorizontalPosition(tm, geoLocation)
+}
+
+func (t *meeusCalculator) UTCSunrise(targetDateTime gdt.GDateTime, geoLocation GeoLocation, zenith dimension.Degrees, adjustForElevation bool) float64 {
+	elevation := dimension.Meters(0)
+	if adjustForElevation {
+		elevation = geoLocation.Elevation()
+	}
+	adjustedZenith := t.adjustZenith(targetDateTime, zenith, elevation)
+
+	return meeusRiseSet(targetDateTime, geoLocation, adjustedZenith, true)
+}
+
+func (t *meeusCalculator) UTCSunset(targetDateTime gdt.GDateTime, geoLocation GeoLocation, zenith dimension.Degrees, adjustForElevation bool) float64 {
+	elevation := dimension.Meters(0)
+	if adjustForElevation {
+		elevation = geoLocation.Elevation()
+	}
+	adjustedZenith := t.adjustZenith(targetDateTime, zenith, elevation)
+
+	return meeusRiseSet(targetDateTime, geoLocation, adjustedZenith, false)
+}
+
+/*
+meeusApparentSun holds the apparent geocentric position of the sun calculated by meeusApparentSunPosition.
+All angles are in Degrees.
+*/
+type meeusApparentSun struct {
+	// lambda apparent longitude
+	lambda float64
+	// beta latitude
+	beta float64
+	// r earth radius vector in Astronomical Units (AU)
+	r float64
+	// epsilon true obliquity of the ecliptic
+	epsilon float64
+	// deltaPsi nutation in longitude
+	deltaPsi float64
+	// alpha apparent right ascension
+	alpha float64
+	// delta apparent declination
+	delta float64
+}
+
+/*
+meeusApparentSunPosition calculates the apparent geocentric position of the sun for the Julian Ephemeris Day jde.
+*/
+func meeusApparentSunPosition(jde float64) meeusApparentSun {
+	jce := julianCenturiesFromJulianDay(jde)
+	jme := jce / 10
+
+	l := limitDegrees(float64(dimension.Radians(earthPeriodicTermSum(spaLTerms, jme)).ToDegrees()))
+	b := float64(dimension.Radians(earthPeriodicTermSum(spaBTerms, jme)).ToDegrees())
+
+	var sun meeusApparentSun
+	sun.r = earthPeriodicTermSum(spaRTerms, jme)
+
+	// geometric geocentric position in the dynamical reference frame
+	theta := limitDegrees(l + 180)
+	beta := -b
+
+	// conversion to the FK5 system (32.3)
+	lambdaPrime := dimension.Degrees(theta - 1.397*jce - 0.00031*jce*jce)
+	theta += -0.09033 / 3600
+	beta += 0.03916 / 3600 * (lambdaPrime.Cos() - lambdaPrime.Sin())
+
+	var deltaEpsilon float64
+	sun.deltaPsi, deltaEpsilon = nutation(jce)
+	sun.epsilon = trueObliquityOfEcliptic(jme, deltaEpsilon)
+
+	// nutation and aberration (25.10)
+	sun.lambda = limitDegrees(theta + sun.deltaPsi - 20.4898/(3600*sun.r))
+	sun.beta = beta
+
+	sun.alpha, sun.delta = eclipticToEquatorial(sun.lambda, sun.beta, sun.epsilon)
+
+	return sun
+}
+
+/*
+apparentSiderealTime returns the apparent sidereal time at Greenwich in Degrees (12.4) for the Julian Day (UT) jd.
+deltaPsi the nutation in longitude
+epsilon the true obliquity of the ecliptic
+*/
+func apparentSiderealTime(jd float64, deltaPsi float64, epsilon float64) float64 {
+	t := julianCenturiesFromJulianDay(jd)
+	theta0 := limitDegrees(280.46061837 + 360.98564736629*(jd-JulianDayJan12000) + t*t*(0.000387933-t/38710000))
+	return limitDegrees(theta0 + deltaPsi*dimension.Degrees(epsilon).Cos())
+}
+
+/*
+meeusInterpolate interpolates (3.3) the three values passed in (for the day before, the day and the day after) for
+the interpolating factor n.
+*/
+func meeusInterpolate(y [3]float64, n float64) float64 {
+	a := y[1] - y[0]
+	b := y[2] - y[1]
+	c := b - a
+	return y[1] + n/2*(a+b+n*c)
+}
+
+/*
+meeusRiseSet calculates the time of sunrise or sunset following chapter 15 of Astronomical Algorithms.
+The method return the UTC time of the event in 24 hours format. 5:45:00 AM will return 5.75.0.
+If the sun does not reach the adjustedZenith, math.NaN will be returned.
+*/
+func meeusRiseSet(targetDateTime gdt.GDateTime, geoLocation GeoLocation, adjustedZenith dimension.Degrees, isSunrise bool) float64 {
+	date := time.Date(int(targetDateTime.D.Year), targetDateTime.D.Month, int(targetDateTime.D.Day), 0, 0, 0, 0, time.UTC)
+	jd := julianDayFromTime(date)
+	deltaT := DeltaT(date).Seconds()
+
+	var alpha, delta [3]float64
+	for i := 0; i < 3; i++ {
+		sun := meeusApparentSunPosition(jd + float64(i-1))
+		alpha[i] = sun.alpha
+		delta[i] = sun.delta
+	}
+	// keep the right ascension continuous across 360 deg
+	for i := 1; i < 3; i++ {
+		for alpha[i]-alpha[i-1] < -180 {
+			alpha[i] += 360
+		}
+		for alpha[i]-alpha[i-1] > 180 {
+			alpha[i] -= 360
+		}
+	}
+
+	sunAtJD := meeusApparentSunPosition(jd + deltaT/86400)
+	theta0 := apparentSiderealTime(jd, sunAtJD.deltaPsi, sunAtJD.epsilon)
+
+	latitude := dimension.Degrees(geoLocation.Latitude())
+	longitude := geoLocation.Longitude()
+	h0 := dimension.Degrees(90) - adjustedZenith
+
+	cosH0 := (h0.Sin() - latitude.Sin()*dimension.Degrees(delta[1]).Sin()) / (latitude.Cos() * dimension.Degrees(delta[1]).Cos())
+	if math.Abs(cosH0) > 1 {
+		return math.NaN()
+	}
+	hourAngle0 := float64(dimension.ACos(cosH0))
+
+	m := (alpha[1] - longitude - theta0) / 360
+	if isSunrise {
+		m -= hourAngle0 / 360
+	} else {
+		m += hourAngle0 / 360
+	}
+	m = limitZeroToOne(m)
+
+	for i := 0; i < 10; i++ {
+		siderealTime := theta0 + 360.985647*m
+		n := m + deltaT/86400
+		alphaM := meeusInterpolate(alpha, n)
+		deltaM := dimension.Degrees(meeusInterpolate(delta, n))
+		hourAngle := dimension.Degrees(limitDegrees180pm(siderealTime + longitude - alphaM))
+		altitude := dimension.ASin(latitude.Sin()*deltaM.Sin() + latitude.Cos()*deltaM.Cos()*hourAngle.Cos())
+		deltaM2 := float64(altitude-h0) / (360 * deltaM.Cos() * latitude.Cos() * hourAngle.Sin())
+		m += deltaM2
+		if math.Abs(deltaM2) < 1e-7 {
+			break
+		}
+	}
+
+	return 24 * limitZeroToOne(m)
+}
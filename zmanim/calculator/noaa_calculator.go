@@ -4,6 +4,7 @@ import (
 	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
 	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
 	"math"
+	"time"
 )
 
 /*
@@ -56,7 +57,7 @@ func (t *noaaCalculator) UTCSunrise(targetDateTime gdt.GDateTime, geoLocation Ge
 		elevation = geoLocation.Elevation()
 	}
 
-	adjustedZenith := t.adjustZenith(zenith, elevation)
+	adjustedZenith := t.adjustZenith(targetDateTime, zenith, elevation)
 
 	var sunrise = sunriseUTC(julianDay(targetDateTime), dimension.Degrees(geoLocation.Latitude()), -dimension.Degrees(geoLocation.Longitude()), adjustedZenith)
 	sunrise = sunrise / 60
@@ -79,7 +80,7 @@ func (t *noaaCalculator) UTCSunset(targetDateTime gdt.GDateTime, geoLocation Geo
 		elevation = geoLocation.Elevation()
 	}
 
-	adjustedZenith := t.adjustZenith(zenith, elevation)
+	adjustedZenith := t.adjustZenith(targetDateTime, zenith, elevation)
 
 	var sunset = sunsetUTC(julianDay(targetDateTime), dimension.Degrees(geoLocation.Latitude()), -dimension.Degrees(geoLocation.Longitude()), adjustedZenith)
 	sunset = sunset / 60
@@ -96,6 +97,22 @@ func (t *noaaCalculator) UTCSunset(targetDateTime gdt.GDateTime, geoLocation Geo
 	return sunset
 }
 
+/*
+SunHorizontalPosition returns the horizontal position of the sun of the NOAA equations of the declination and the
+equation of time, see SunPositionCalculator.
+*/
+func (t *noaaCalculator) SunHorizontalPosition(tm time.Time, geoLocation GeoLocation) (altitude dimension.Degrees, azimuth dimension.Degrees) {
+	julianCenturies := julianCenturiesFromJulianDay(julianDayFromTime(tm))
+
+	utc := tm.UTC()
+	minutes := float64(utc.Hour()*60+utc.Minute()) + (float64(utc.Second())+float64(utc.Nanosecond())/1e9)/60
+	// the true solar time in minutes of the day, at its noon the hour angle is 0
+	trueSolarTime := minutes + equationOfTime(julianCenturies) + 4*geoLocation.Longitude()
+	hourAngle := dimension.Degrees(trueSolarTime/4 - 180)
+
+	return horizontalPosition(hourAngle, sunDeclination(julianCenturies), dimension.Degrees(geoLocation.Latitude()))
+}
+
 /*
 julianDay return the [Julian Day]: http://en.wikipedia.org/wiki/Julian_day
 the Julian Day corresponding to the date Note: Number is returned for start of Day. Fractional days
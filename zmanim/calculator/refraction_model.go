@@ -0,0 +1,133 @@
+package calculator
+
+import (
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+)
+
+/*
+RefractionModel the model of the atmospheric refraction used by the AstronomicalCalculator to adjust the zenith of
+sunrise and sunset (see AdjustableCalculator.SetRefractionModel).
+*/
+type RefractionModel interface {
+	/*
+		Refraction returns the atmospheric refraction of a body seen at the apparentAltitude above the horizon.
+		Sunrise and sunset use the refraction at the apparent altitude of 0 deg, the upper limb of the sun touching
+		the horizon.
+	*/
+	Refraction(apparentAltitude dimension.Degrees) dimension.ArcMinutes
+}
+
+/*
+RefractionFunc an adapter to allow the use of an ordinary function as a RefractionModel.
+*/
+type RefractionFunc func(apparentAltitude dimension.Degrees) dimension.ArcMinutes
+
+func (f RefractionFunc) Refraction(apparentAltitude dimension.Degrees) dimension.ArcMinutes {
+	return f(apparentAltitude)
+}
+
+/*
+DefaultRefraction the commonly used average solar refraction of 34 dimension.ArcMinutes.
+*/
+const DefaultRefraction = dimension.ArcMinutes(34)
+
+/*
+ReingoldRefraction the more accurate global average refraction of 34.478885263888294 dimension.ArcMinutes
+(approximately 34' 29") listed by the [Errata and Notes for Calendrical Calculations: The Millennium Edition]: https://web.archive.org/web/20150915094635/http://emr.cs.iit.edu/home/reingold/calendar-book/second-edition/errata.pdf
+by Edward M. Reingold and Nachum Dershowitz.
+*/
+const ReingoldRefraction = dimension.ArcMinutes(34.478885263888294)
+
+/*
+constantRefraction a RefractionModel that returns the same refraction at every altitude.
+*/
+type constantRefraction struct {
+	refraction dimension.ArcMinutes
+}
+
+func NewConstantRefraction(refraction dimension.ArcMinutes) RefractionModel {
+	if refraction < 0 {
+		panic("refraction is negative.")
+	}
+	return &constantRefraction{refraction: refraction}
+}
+
+func (t *constantRefraction) Refraction(_ dimension.Degrees) dimension.ArcMinutes {
+	return t.refraction
+}
+
+/*
+pressureTemperatureFactor returns the factor the refraction at the standard conditions of 1010 millibars and
+10 deg Celsius is multiplied by for the pressure and temperature passed in
+(Astronomical Algorithms by Jean Meeus, chapter 16).
+*/
+func pressureTemperatureFactor(pressure dimension.Millibars, temperature dimension.Celsius) float64 {
+	return float64(pressure) / 1010 * 283 / (273 + float64(temperature))
+}
+
+/*
+bennettRefraction a RefractionModel using the formula of [G. G. Bennett]: https://doi.org/10.1017/S0373463300022037
+(The Calculation of Astronomical Refraction in Marine Navigation, 1982) computed from the apparent altitude,
+adjusted for the pressure and temperature.
+*/
+type bennettRefraction struct {
+	pressure    dimension.Millibars
+	temperature dimension.Celsius
+}
+
+func NewBennettRefraction(pressure dimension.Millibars, temperature dimension.Celsius) RefractionModel {
+	if pressure < 0 {
+		panic("pressure is negative.")
+	}
+	if temperature <= -273 {
+		panic("temperature is below absolute zero.")
+	}
+	return &bennettRefraction{pressure: pressure, temperature: temperature}
+}
+
+func (t *bennettRefraction) Refraction(apparentAltitude dimension.Degrees) dimension.ArcMinutes {
+	return dimension.ArcMinutes(bennett(float64(apparentAltitude)) * pressureTemperatureFactor(t.pressure, t.temperature))
+}
+
+/*
+bennett returns the refraction in arc minutes at the standard conditions for the apparent altitude h in Degrees.
+*/
+func bennett(h float64) float64 {
+	return 1 / dimension.Degrees(h+7.31/(h+4.4)).Tan()
+}
+
+/*
+saemundssonRefraction a RefractionModel using the formula of Þorsteinn Sæmundsson
+(Sky and Telescope, 1986) computed from the true (airless) altitude, adjusted for the pressure and temperature.
+Since the RefractionModel receives the apparent altitude, the true altitude is found by iteration.
+*/
+type saemundssonRefraction struct {
+	pressure    dimension.Millibars
+	temperature dimension.Celsius
+}
+
+func NewSaemundssonRefraction(pressure dimension.Millibars, temperature dimension.Celsius) RefractionModel {
+	if pressure < 0 {
+		panic("pressure is negative.")
+	}
+	if temperature <= -273 {
+		panic("temperature is below absolute zero.")
+	}
+	return &saemundssonRefraction{pressure: pressure, temperature: temperature}
+}
+
+func (t *saemundssonRefraction) Refraction(apparentAltitude dimension.Degrees) dimension.ArcMinutes {
+	factor := pressureTemperatureFactor(t.pressure, t.temperature)
+
+	refraction := bennett(float64(apparentAltitude)) * factor
+	for i := 0; i < 10; i++ {
+		trueAltitude := float64(apparentAltitude) - refraction/60
+		next := 1.02 / dimension.Degrees(trueAltitude+10.3/(trueAltitude+5.11)).Tan() * factor
+		if math.Abs(next-refraction) < 1e-9 {
+			return dimension.ArcMinutes(next)
+		}
+		refraction = next
+	}
+	return dimension.ArcMinutes(refraction)
+}
@@ -0,0 +1,116 @@
+package calculator
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"time"
+)
+
+/*
+UTCSolarNoon returns the solar noon, the upper transit of the sun over the meridian of the geoLocation, of the date of
+the targetDateTime in the format of AstronomicalCalculator.UTCSunrise: 12.5 for 12:30:00 UTC. Unlike sunrise and
+sunset, it exists on every date at every location.
+*/
+func UTCSolarNoon(targetDateTime gdt.GDateTime, geoLocation GeoLocation) float64 {
+	date := time.Date(int(targetDateTime.D.Year), targetDateTime.D.Month, int(targetDateTime.D.Day), 0, 0, 0, 0, time.UTC)
+
+	hours := 12 - geoLocation.Longitude()/15
+	for i := 0; i < 3; i++ {
+		tm := date.Add(time.Duration(hours * float64(time.Hour)))
+		hours -= float64(solarHourAngle(tm, geoLocation.Longitude())) / 15
+	}
+
+	return math.Mod(math.Mod(hours, 24)+24, 24)
+}
+
+/*
+UTCSolarMidnight returns the solar midnight, the lower transit of the sun, 12 hours after the UTCSolarNoon of the date
+of the targetDateTime, in the format of AstronomicalCalculator.UTCSunrise.
+*/
+func UTCSolarMidnight(targetDateTime gdt.GDateTime, geoLocation GeoLocation) float64 {
+	return math.Mod(UTCSolarNoon(targetDateTime, geoLocation)+12, 24)
+}
+
+/*
+SunDeclination returns the apparent declination of the sun at the time tm.
+*/
+func SunDeclination(tm time.Time) dimension.Degrees {
+	jde := JulianEphemerisDay(tm)
+	sun := ApparentSunPosition(jde)
+	_, declination := EclipticToEquatorial(sun.Longitude, sun.Latitude, TrueObliquityOfEcliptic(jde))
+	return declination
+}
+
+/*
+SunHorizontalPosition returns the geometric altitude (not corrected for the refraction) and the azimuth (from the true
+north eastward) of the center of the sun at the time tm at the geoLocation.
+*/
+func SunHorizontalPosition(tm time.Time, geoLocation GeoLocation) (altitude dimension.Degrees, azimuth dimension.Degrees) {
+	hourAngle, declination := sunHourAngleAndDeclination(tm, geoLocation.Longitude())
+	return horizontalPosition(hourAngle, declination, dimension.Degrees(geoLocation.Latitude()))
+}
+
+/*
+SunPositionCalculator an AstronomicalCalculator that calculates the horizontal position of the sun with the algorithm
+of its sunrise and sunset, so that the visible sunrise and sunset over a HorizonProfile are of the same algorithm as
+the sunrise and sunset over the flat horizon. The NOAA, the Meeus and the SPA calculators implement it.
+*/
+type SunPositionCalculator interface {
+	AstronomicalCalculator
+	/*
+		SunHorizontalPosition returns the geometric altitude (not corrected for the refraction) and the azimuth (from the
+		true north eastward) of the center of the sun at the time tm at the geoLocation.
+	*/
+	SunHorizontalPosition(tm time.Time, geoLocation GeoLocation) (altitude dimension.Degrees, azimuth dimension.Degrees)
+}
+
+/*
+SunHorizontalPositionOf returns the SunHorizontalPosition of the astronomicalCalculator if it is a
+SunPositionCalculator, otherwise the one of the package SunHorizontalPosition (the algorithms of Jean Meeus), for
+instance for the SunTimesCalculator, whose almanac algorithm doesn't calculate the position of the sun.
+*/
+func SunHorizontalPositionOf(astronomicalCalculator AstronomicalCalculator, tm time.Time, geoLocation GeoLocation) (altitude dimension.Degrees, azimuth dimension.Degrees) {
+	if c, ok := astronomicalCalculator.(SunPositionCalculator); ok {
+		return c.SunHorizontalPosition(tm, geoLocation)
+	}
+	return SunHorizontalPosition(tm, geoLocation)
+}
+
+/*
+horizontalPosition returns the altitude and the azimuth, from the true north eastward, of the hourAngle and the
+declination at the latitude.
+*/
+func horizontalPosition(hourAngle dimension.Degrees, declination dimension.Degrees, latitude dimension.Degrees) (altitude dimension.Degrees, azimuth dimension.Degrees) {
+	altitude = dimension.ASin(latitude.Sin()*declination.Sin() + latitude.Cos()*declination.Cos()*hourAngle.Cos())
+	azimuth = dimension.Radians(math.Atan2(hourAngle.Sin(), hourAngle.Cos()*latitude.Sin()-declination.Tan()*latitude.Cos())).ToDegrees() + 180
+	return altitude, dimension.Degrees(math.Mod(float64(azimuth), 360))
+}
+
+/*
+solarHourAngle returns the local hour angle of the sun at the time tm at the longitude, from -180 to 180 deg.
+*/
+func solarHourAngle(tm time.Time, longitude float64) dimension.Degrees {
+	hourAngle, _ := sunHourAngleAndDeclination(tm, longitude)
+	return hourAngle
+}
+
+/*
+sunHourAngleAndDeclination returns the local hour angle, from -180 to 180 deg, and the apparent declination of the sun
+at the time tm at the longitude.
+*/
+func sunHourAngleAndDeclination(tm time.Time, longitude float64) (hourAngle dimension.Degrees, declination dimension.Degrees) {
+	jd := JulianDay(tm)
+	jde := JulianEphemerisDay(tm)
+	sun := ApparentSunPosition(jde)
+	epsilon := TrueObliquityOfEcliptic(jde)
+	rightAscension, declination := EclipticToEquatorial(sun.Longitude, sun.Latitude, epsilon)
+	deltaPsi, _ := Nutation(jde)
+
+	h := float64(ApparentSiderealTime(jd, deltaPsi, epsilon)) + longitude - float64(rightAscension)
+	h = math.Mod(math.Mod(h, 360)+360, 360)
+	if h > 180 {
+		h -= 360
+	}
+	return dimension.Degrees(h), declination
+}
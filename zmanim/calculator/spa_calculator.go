@@ -0,0 +1,472 @@
+package calculator
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"time"
+)
+
+/*
+SPACalculator implementation of sunrise and sunset methods to calculate astronomical times based on the
+[NREL]: https://www.nrel.gov [Solar Position Algorithm (SPA)]: https://midcdmz.nrel.gov/spa/ by Ibrahim Reda and
+Afshin Andreas, [Solar Position Algorithm for Solar Radiation Applications]: https://www.nrel.gov/docs/fy08osti/34302.pdf,
+NREL/TP-560-34302, 2008. The algorithm calculates the solar zenith and azimuth angles in the period from the year
+-2000 to 6000, with uncertainties of +/- 0.0003 deg, based on the date, time, and location on Earth.
+Added to the algorithm is an adjustment of the zenith to account for elevation.
+The SPA needs the difference between the Earth rotation time and the Terrestrial Time (DeltaT), as well as the
+annual average local pressure and temperature, used for the atmospheric refraction correction of the topocentric
+solar position.
+*/
+type SPACalculator interface {
+	AdjustableCalculator
+	// DeltaT and other getters
+	//
+	DeltaT() time.Duration
+	Pressure() dimension.Millibars
+	Temperature() dimension.Celsius
+	SolarPosition(tm time.Time, geoLocation GeoLocation) SolarPosition
+	// SetDeltaT and other setters
+	//
+	SetDeltaT(deltaT time.Duration)
+	SetPressure(pressure dimension.Millibars)
+	SetTemperature(temperature dimension.Celsius)
+}
+
+/*
+SolarPosition the topocentric position of the sun for a time and location as calculated by the SPACalculator.
+*/
+type SolarPosition struct {
+	// Zenith the topocentric zenith angle, corrected for atmospheric refraction
+	Zenith dimension.Degrees
+	// Elevation the topocentric elevation angle (90 deg - Zenith), corrected for atmospheric refraction
+	Elevation dimension.Degrees
+	// Azimuth the topocentric azimuth angle, measured eastward from north
+	Azimuth dimension.Degrees
+	// RightAscension the topocentric sun right ascension
+	RightAscension dimension.Degrees
+	// Declination the topocentric sun declination
+	Declination dimension.Degrees
+	// EarthSunDistance the Earth radius vector in Astronomical Units (AU)
+	EarthSunDistance float64
+}
+
+type spaCalculator struct {
+	astronomicalCalculator
+
+	/*
+		deltaT the difference between the Earth rotation time and the Terrestrial Time. It is derived from observation
+		only and is reported in the [Bulletin A]: https://maia.usno.navy.mil/products/bulletin-a.
+		The default value is 69 seconds, close to the observed value in the 2020s.
+	*/
+	deltaT time.Duration
+
+	// pressure the annual average local pressure. The default value is 1010 dimension.Millibars.
+	pressure dimension.Millibars
+
+	// temperature the annual average local temperature. The default value is 10 dimension.Celsius.
+	temperature dimension.Celsius
+}
+
+func newSPACalculator() *spaCalculator {
+	return &spaCalculator{deltaT: 69 * time.Second, pressure: 1010, temperature: 10}
+}
+
+func NewSPACalculator() SPACalculator {
+	t := newSPACalculator()
+
+	t.initAstronomicalCalculator()
+
+	return t
+}
+
+func (t *spaCalculator) CalculatorName() string {
+	return "US National Renewable Energy Laboratory Solar Position Algorithm"
+}
+
+func (t *spaCalculator) DeltaT() time.Duration {
+	return t.deltaT
+}
+
+func (t *spaCalculator) SetDeltaT(deltaT time.Duration) {
+	t.deltaT = deltaT
+}
+
+func (t *spaCalculator) Pressure() dimension.Millibars {
+	return t.pressure
+}
+
+func (t *spaCalculator) SetPressure(pressure dimension.Millibars) {
+	t.pressure = pressure
+}
+
+func (t *spaCalculator) Temperature() dimension.Celsius {
+	return t.temperature
+}
+
+func (t *spaCalculator) SetTemperature(temperature dimension.Celsius) {
+	t.temperature = temperature
+}
+
+func (t *spaCalculator) UTCSunrise(targetDateTime gdt.GDateTime, geoLocation GeoLocation, zenith dimension.Degrees, adjustForElevation bool) float64 {
+	elevation := dimension.Meters(0)
+	if adjustForElevation {
+		elevation = geoLocation.Elevation()
+	}
+	adjustedZenith := t.adjustZenith(targetDateTime, zenith, elevation)
+
+	return t.riseTransitSet(targetDateTime, geoLocation, adjustedZenith, spaSunrise)
+}
+
+func (t *spaCalculator) UTCSunset(targetDateTime gdt.GDateTime, geoLocation GeoLocation, zenith dimension.Degrees, adjustForElevation bool) float64 {
+	elevation := dimension.Meters(0)
+	if adjustForElevation {
+		elevation = geoLocation.Elevation()
+	}
+	adjustedZenith := t.adjustZenith(targetDateTime, zenith, elevation)
+
+	return t.riseTransitSet(targetDateTime, geoLocation, adjustedZenith, spaSunset)
+}
+
+/*
+SolarPosition returns the topocentric SolarPosition of the sun at the time tm for the geoLocation passed in.
+The zenith and elevation angles are corrected for atmospheric refraction using the Pressure and Temperature
+of the calculator, if the sun is above the horizon.
+*/
+func (t *spaCalculator) SolarPosition(tm time.Time, geoLocation GeoLocation) SolarPosition {
+	p := t.topocentricPosition(tm, geoLocation)
+	e0 := p.elevation
+
+	// atmospheric refraction correction
+	deltaE := dimension.Degrees(0)
+	if e0 >= -(SolarRadiusOf(t, tm).InDegrees() + t.horizonRefraction().InDegrees()) {
+		deltaE = dimension.Degrees((float64(t.pressure) / 1010) * (283 / (273 + float64(t.temperature))) * 1.02 / (60 * (e0 + 10.3/(e0+5.11)).Tan()))
+	}
+	e := e0 + deltaE
+
+	return SolarPosition{
+		Zenith:           90 - e,
+		Elevation:        e,
+		Azimuth:          p.azimuth,
+		RightAscension:   p.rightAscension,
+		Declination:      p.declination,
+		EarthSunDistance: p.earthSunDistance,
+	}
+}
+
+/*
+SunHorizontalPosition returns the topocentric elevation angle of the SolarPosition without the atmospheric refraction
+correction and its azimuth, see SunPositionCalculator.
+*/
+func (t *spaCalculator) SunHorizontalPosition(tm time.Time, geoLocation GeoLocation) (altitude dimension.Degrees, azimuth dimension.Degrees) {
+	p := t.topocentricPosition(tm, geoLocation)
+	return p.elevation, p.azimuth
+}
+
+/*
+spaTopocentric the topocentric position of the sun of the SolarPosition, of the elevation angle not corrected for the
+atmospheric refraction.
+*/
+type spaTopocentric struct {
+	elevation        dimension.Degrees
+	azimuth          dimension.Degrees
+	rightAscension   dimension.Degrees
+	declination      dimension.Degrees
+	earthSunDistance float64
+}
+
+func (t *spaCalculator) topocentricPosition(tm time.Time, geoLocation GeoLocation) spaTopocentric {
+	g := spaGeocentricPosition(julianDayFromTime(tm), t.deltaT.Seconds())
+
+	latitude := dimension.Degrees(geoLocation.Latitude())
+
+	// observer local hour angle
+	h := limitDegrees(g.nu + geoLocation.Longitude() - g.alpha)
+
+	// topocentric sun right ascension and declination, corrected for the parallax
+	xi := dimension.Degrees(8.794 / (3600 * g.r))
+	u := dimension.ATan(0.99664719 * latitude.Tan())
+	x := u.Cos() + float64(geoLocation.Elevation())/6378140*latitude.Cos()
+	y := 0.99664719*u.Sin() + float64(geoLocation.Elevation())/6378140*latitude.Sin()
+
+	deltaRad := dimension.Degrees(g.delta).ToRadians()
+	hRad := dimension.Degrees(h).ToRadians()
+	deltaAlpha := dimension.Radians(math.Atan2(-x*xi.Sin()*math.Sin(float64(hRad)), math.Cos(float64(deltaRad))-x*xi.Sin()*math.Cos(float64(hRad)))).ToDegrees()
+	deltaPrime := dimension.Radians(math.Atan2((math.Sin(float64(deltaRad))-y*xi.Sin())*deltaAlpha.Cos(), math.Cos(float64(deltaRad))-x*xi.Sin()*math.Cos(float64(hRad)))).ToDegrees()
+	alphaPrime := dimension.Degrees(g.alpha) + deltaAlpha
+	hPrime := dimension.Degrees(h) - deltaAlpha
+
+	// topocentric elevation angle without atmospheric refraction correction
+	e0 := dimension.ASin(latitude.Sin()*deltaPrime.Sin() + latitude.Cos()*deltaPrime.Cos()*hPrime.Cos())
+
+	gamma := dimension.Radians(math.Atan2(hPrime.Sin(), hPrime.Cos()*latitude.Sin()-deltaPrime.Tan()*latitude.Cos())).ToDegrees()
+
+	return spaTopocentric{
+		elevation:        e0,
+		azimuth:          dimension.Degrees(limitDegrees(float64(gamma) + 180)),
+		rightAscension:   dimension.Degrees(limitDegrees(float64(alphaPrime))),
+		declination:      deltaPrime,
+		earthSunDistance: g.r,
+	}
+}
+
+const (
+	spaSunTransit = 0
+	spaSunrise    = 1
+	spaSunset     = 2
+)
+
+/*
+riseTransitSet calculates the sunrise, sun transit or sunset (according to the event passed in) following the
+appendix A.2 of the SPA paper.
+The method return the UTC time of the event in 24 hours format. 5:45:00 AM will return 5.75.0.
+If the sun does not reach the adjustedZenith, math.NaN will be returned.
+*/
+func (t *spaCalculator) riseTransitSet(targetDateTime gdt.GDateTime, geoLocation GeoLocation, adjustedZenith dimension.Degrees, event int) float64 {
+	jd := julianDayFromTime(time.Date(int(targetDateTime.D.Year), targetDateTime.D.Month, int(targetDateTime.D.Day), 0, 0, 0, 0, time.UTC))
+
+	// apparent sidereal time at Greenwich at 0 UT
+	nu := spaGeocentricPosition(jd, t.deltaT.Seconds()).nu
+
+	var alpha, delta [3]float64
+	for i := 0; i < 3; i++ {
+		g := spaGeocentricPosition(jd+float64(i-1), 0)
+		alpha[i] = g.alpha
+		delta[i] = g.delta
+	}
+
+	longitude := geoLocation.Longitude()
+	latitude := dimension.Degrees(geoLocation.Latitude())
+	h0Prime := dimension.Degrees(90) - adjustedZenith
+
+	mTransit := (alpha[1] - longitude - nu) / 360
+
+	var m float64
+	switch event {
+	case spaSunTransit:
+		m = limitZeroToOne(mTransit)
+	default:
+		argument := (h0Prime.Sin() - latitude.Sin()*dimension.Degrees(delta[1]).Sin()) / (latitude.Cos() * dimension.Degrees(delta[1]).Cos())
+		if math.Abs(argument) > 1 {
+			return math.NaN()
+		}
+		h0 := float64(dimension.ACos(argument))
+		if event == spaSunrise {
+			m = limitZeroToOne(mTransit - h0/360)
+		} else {
+			m = limitZeroToOne(mTransit + h0/360)
+		}
+	}
+
+	nuEvent := nu + 360.985647*m
+	n := m + t.deltaT.Seconds()/86400
+	alphaPrime := spaInterpolate(alpha, n)
+	deltaPrime := dimension.Degrees(spaInterpolate(delta, n))
+	hPrime := dimension.Degrees(limitDegrees180pm(nuEvent + longitude - alphaPrime))
+
+	if event == spaSunTransit {
+		return 24 * limitZeroToOne(m-float64(hPrime)/360)
+	}
+
+	h := dimension.ASin(latitude.Sin()*deltaPrime.Sin() + latitude.Cos()*deltaPrime.Cos()*hPrime.Cos())
+
+	return 24 * limitZeroToOne(m+float64(h-h0Prime)/(360*deltaPrime.Cos()*latitude.Cos()*hPrime.Sin()))
+}
+
+/*
+spaGeocentric holds the intermediate geocentric values calculated by spaGeocentricPosition.
+All angles are in Degrees.
+*/
+type spaGeocentric struct {
+	// l earth heliocentric longitude
+	l float64
+	// b earth heliocentric latitude
+	b float64
+	// r earth radius vector in Astronomical Units (AU)
+	r float64
+	// deltaPsi nutation in longitude
+	deltaPsi float64
+	// deltaEpsilon nutation in obliquity
+	deltaEpsilon float64
+	// epsilon true obliquity of the ecliptic
+	epsilon float64
+	// lambda apparent sun longitude
+	lambda float64
+	// nu apparent sidereal time at Greenwich
+	nu float64
+	// alpha geocentric sun right ascension
+	alpha float64
+	// delta geocentric sun declination
+	delta float64
+}
+
+/*
+spaGeocentricPosition calculates the geocentric position of the sun.
+jd the Julian Day (UT)
+deltaT the difference between the Earth rotation time and the Terrestrial Time in seconds
+*/
+func spaGeocentricPosition(jd float64, deltaT float64) spaGeocentric {
+	jc := julianCenturiesFromJulianDay(jd)
+	jde := jd + deltaT/86400
+	jce := julianCenturiesFromJulianDay(jde)
+	jme := jce / 10
+
+	var g spaGeocentric
+
+	g.l = limitDegrees(float64(dimension.Radians(earthPeriodicTermSum(spaLTerms, jme)).ToDegrees()))
+	g.b = float64(dimension.Radians(earthPeriodicTermSum(spaBTerms, jme)).ToDegrees())
+	g.r = earthPeriodicTermSum(spaRTerms, jme)
+
+	// geocentric longitude and latitude
+	theta := limitDegrees(g.l + 180)
+	beta := -g.b
+
+	g.deltaPsi, g.deltaEpsilon = nutation(jce)
+
+	g.epsilon = trueObliquityOfEcliptic(jme, g.deltaEpsilon)
+
+	// aberration correction
+	deltaTau := -20.4898 / (3600 * g.r)
+
+	g.lambda = theta + g.deltaPsi + deltaTau
+
+	nu0 := limitDegrees(280.46061837 + 360.98564736629*(jd-JulianDayJan12000) + jc*jc*(0.000387933-jc/38710000))
+	g.nu = nu0 + g.deltaPsi*dimension.Degrees(g.epsilon).Cos()
+
+	g.alpha, g.delta = eclipticToEquatorial(g.lambda, beta, g.epsilon)
+
+	return g
+}
+
+/*
+earthPeriodicTermSum sums the periodic terms series (L, B or R) passed in for the Julian Ephemeris Millennium jme.
+The method return the value in radians for L and B and in Astronomical Units for R.
+*/
+func earthPeriodicTermSum(series [][]spaTerm, jme float64) float64 {
+	sum := 0.0
+	for i := len(series) - 1; i >= 0; i-- {
+		seriesSum := 0.0
+		for _, term := range series[i] {
+			seriesSum += term.a * math.Cos(term.b+term.c*jme)
+		}
+		sum = sum*jme + seriesSum
+	}
+	return sum / 1.0e8
+}
+
+/*
+nutation calculates the nutation in longitude and in obliquity, in Degrees.
+jce the Julian Ephemeris Century
+*/
+func nutation(jce float64) (deltaPsi float64, deltaEpsilon float64) {
+	x := [5]float64{
+		// mean elongation of the moon from the sun
+		297.85036 + jce*(445267.111480+jce*(-0.0019142+jce/189474)),
+		// mean anomaly of the sun
+		357.52772 + jce*(35999.050340+jce*(-0.0001603-jce/300000)),
+		// mean anomaly of the moon
+		134.96298 + jce*(477198.867398+jce*(0.0086972+jce/56250)),
+		// moon's argument of latitude
+		93.27191 + jce*(483202.017538+jce*(-0.0036825+jce/327270)),
+		// longitude of the ascending node of the moon's mean orbit on the ecliptic
+		125.04452 + jce*(-1934.136261+jce*(0.0020708+jce/450000)),
+	}
+
+	sumPsi := 0.0
+	sumEpsilon := 0.0
+	for _, term := range nutationTerms {
+		argument := dimension.Degrees(0)
+		for j := 0; j < 5; j++ {
+			argument += dimension.Degrees(x[j] * term.y[j])
+		}
+		sumPsi += (term.a + term.b*jce) * argument.Sin()
+		sumEpsilon += (term.c + term.d*jce) * argument.Cos()
+	}
+
+	return sumPsi / 36000000, sumEpsilon / 36000000
+}
+
+/*
+trueObliquityOfEcliptic calculates the true obliquity of the ecliptic in Degrees, using the
+[Laskar]: https://articles.adsabs.harvard.edu/full/1986A%26A...157...59L polynomial for the mean obliquity.
+jme the Julian Ephemeris Millennium
+deltaEpsilon the nutation in obliquity in Degrees
+*/
+func trueObliquityOfEcliptic(jme float64, deltaEpsilon float64) float64 {
+	u := jme / 10
+	epsilon0 := 84381.448 + u*(-4680.93+u*(-1.55+u*(1999.25+u*(-51.38+u*(-249.67+u*(-39.05+u*(7.12+u*(27.87+u*(5.79+u*2.45)))))))))
+	return epsilon0/3600 + deltaEpsilon
+}
+
+/*
+eclipticToEquatorial converts the ecliptic longitude lambda and latitude beta to right ascension and declination, all
+in Degrees.
+epsilon the obliquity of the ecliptic
+*/
+func eclipticToEquatorial(lambda float64, beta float64, epsilon float64) (alpha float64, delta float64) {
+	l := dimension.Degrees(lambda)
+	b := dimension.Degrees(beta)
+	e := dimension.Degrees(epsilon)
+
+	alpha = limitDegrees(float64(dimension.Radians(math.Atan2(l.Sin()*e.Cos()-b.Tan()*e.Sin(), l.Cos())).ToDegrees()))
+	delta = float64(dimension.ASin(b.Sin()*e.Cos() + b.Cos()*e.Sin()*l.Sin()))
+
+	return alpha, delta
+}
+
+/*
+spaInterpolate interpolates the right ascension or declination of the day before, the day and the day after
+passed in as values for the fraction of day n.
+*/
+func spaInterpolate(values [3]float64, n float64) float64 {
+	a := values[1] - values[0]
+	b := values[2] - values[1]
+
+	if math.Abs(a) >= 2 {
+		a = limitZeroToOne(a)
+	}
+	if math.Abs(b) >= 2 {
+		b = limitZeroToOne(b)
+	}
+
+	return values[1] + n*(a+b+(b-a)*n)/2
+}
+
+/*
+julianDayFromTime returns the [Julian Day]: http://en.wikipedia.org/wiki/Julian_day (UT) including the fraction
+of day of the time passed in.
+The seconds and the nanoseconds are converted separately, since time.Time.UnixNano overflows for years before 1678
+and after 2262.
+*/
+func julianDayFromTime(tm time.Time) float64 {
+	return (float64(tm.Unix())+float64(tm.Nanosecond())/1e9)/86400 + 2440587.5
+}
+
+/*
+limitDegrees limits the angle passed in to the range 0 - 360 deg.
+*/
+func limitDegrees(degrees float64) float64 {
+	degrees = math.Mod(degrees, 360)
+	if degrees < 0 {
+		degrees += 360
+	}
+	return degrees
+}
+
+/*
+limitDegrees180pm limits the angle passed in to the range -180 - 180 deg.
+*/
+func limitDegrees180pm(degrees float64) float64 {
+	degrees = limitDegrees(degrees)
+	if degrees > 180 {
+		degrees -= 360
+	}
+	return degrees
+}
+
+/*
+limitZeroToOne limits the value passed in to the range 0 - 1.
+*/
+func limitZeroToOne(value float64) float64 {
+	return value - math.Floor(value)
+}
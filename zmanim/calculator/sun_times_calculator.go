@@ -42,7 +42,7 @@ func (t *sunTimesCalculator) UTCSunrise(targetDateTime gdt.GDateTime, geoLocatio
 	if adjustForElevation {
 		elevation = geoLocation.Elevation()
 	}
-	adjustedZenith := t.adjustZenith(zenith, elevation)
+	adjustedZenith := t.adjustZenith(targetDateTime, zenith, elevation)
 
 	doubleTime = timeUTC(targetDateTime, geoLocation, adjustedZenith, true)
 
@@ -55,7 +55,7 @@ func (t *sunTimesCalculator) UTCSunset(targetDateTime gdt.GDateTime, geoLocation
 	if adjustForElevation {
 		elevation = geoLocation.Elevation()
 	}
-	adjustedZenith := t.adjustZenith(zenith, elevation)
+	adjustedZenith := t.adjustZenith(targetDateTime, zenith, elevation)
 
 	doubleTime = timeUTC(targetDateTime, geoLocation, adjustedZenith, false)
 
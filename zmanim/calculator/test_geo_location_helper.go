@@ -24,11 +24,9 @@ func TokyoGeoLocation() GeoLocation {
 	return NewGeoLocation2("Tokyo, Japan", 35.6733227, 139.6403486, 40, timeutil.LoadLocationOrPanic("Asia/Tokyo"))
 }
 
-/*
 func ArcticNunavutGeoLocation() GeoLocation {
 	return NewGeoLocation2("Fort Conger, NU Canada", 81.7449398, -64.7945858, 127, timeutil.LoadLocationOrPanic("America/Toronto"))
 }
-*/
 
 func BasicTestGeoLocations() []GeoLocation {
 	return []GeoLocation{
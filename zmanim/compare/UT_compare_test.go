@@ -0,0 +1,130 @@
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"strings"
+	"testing"
+)
+
+/*
+noSunsetCalculator a calculator.AstronomicalCalculator that never calculates the sunset.
+*/
+type noSunsetCalculator struct {
+	calculator.AstronomicalCalculator
+}
+
+func (t noSunsetCalculator) CalculatorName() string {
+	return "No Sunset"
+}
+
+func (t noSunsetCalculator) UTCSunset(_ gdt.GDateTime, _ calculator.GeoLocation, _ dimension.Degrees, _ bool) float64 {
+	return math.NaN()
+}
+
+/*
+failingWriter an io.Writer that fails after the limit bytes.
+*/
+type failingWriter struct {
+	limit int
+}
+
+func (t *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > t.limit {
+		n := t.limit
+		t.limit = 0
+		return n, errors.New("failingWriter")
+	}
+	t.limit -= len(p)
+	return len(p), nil
+}
+
+func testRequest(calculators ...calculator.AstronomicalCalculator) Request {
+	sunrise, _ := ZmanByName("sunrise")
+	sunset, _ := ZmanByName("sunset")
+	return Request{
+		Calculators:  calculators,
+		GeoLocations: []calculator.GeoLocation{calculator.LakewoodGeoLocation()},
+		Zmanim:       []Zman{sunrise, sunset},
+		From:         gdt.NewGDate(2017, 10, 17),
+		To:           gdt.NewGDate(2017, 10, 23),
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	report := Compare(testRequest(calculator.NewNOAACalculator(), calculator.NewSunTimesCalculator(), calculator.NewMeeusCalculator()))
+
+	assert.Equal(t, tag, "2017-10-17", report.From)
+	assert.Equal(t, tag, "2017-10-23", report.To)
+	// 2 zmanim by 3 pairs of calculators
+	assert.Equal(t, tag, 6, len(report.Comparisons))
+	for _, c := range report.Comparisons {
+		assert.Equal(t, tag, 7, c.Days)
+		assert.Equal(t, tag, 0, len(c.Mismatches))
+		assert.True(t, tag, c.MaxDivergence < 60*1000)
+		assert.True(t, tag, c.MeanDivergence <= c.MaxDivergence)
+		assert.True(t, tag, len(c.WorstDates) > 0)
+	}
+	assert.Equal(t, tag, "sunrise", report.Comparisons[0].Zman)
+	assert.Equal(t, tag, [2]string{"US National Oceanic and Atmospheric Administration Algorithm", "US Naval Almanac Algorithm"}, report.Comparisons[0].Calculators)
+}
+
+func TestCompareMismatches(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	report := Compare(testRequest(calculator.NewNOAACalculator(), noSunsetCalculator{calculator.NewNOAACalculator()}))
+
+	sunrise, sunset := report.Comparisons[0], report.Comparisons[1]
+	assert.Equal(t, tag, 7, sunrise.Days)
+	assert.Equal(t, tag, gdt.GMillisecond(0), sunrise.MaxDivergence)
+	assert.Equal(t, tag, 7, len(sunrise.WorstDates))
+
+	assert.Equal(t, tag, 0, sunset.Days)
+	assert.Equal(t, tag, 7, len(sunset.Mismatches))
+	assert.Equal(t, tag, Mismatch{Date: "2017-10-17", Ok: "US National Oceanic and Atmospheric Administration Algorithm", NotOk: "No Sunset"}, sunset.Mismatches[0])
+}
+
+func TestReportWriters(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	report := Compare(testRequest(calculator.NewNOAACalculator(), noSunsetCalculator{calculator.NewNOAACalculator()}))
+
+	var buf bytes.Buffer
+	assert.Equal(t, tag, nil, report.WriteJSON(&buf))
+	var decoded Report
+	assert.Equal(t, tag, nil, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, tag, report.Comparisons[1].Mismatches, decoded.Comparisons[1].Mismatches)
+
+	buf.Reset()
+	assert.Equal(t, tag, nil, report.WriteTable(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	// the date range, the header, 2 comparisons and 7 mismatches
+	assert.Equal(t, tag, 11, len(lines))
+	assert.True(t, tag, strings.HasPrefix(lines[1], "LOCATION"))
+	assert.Equal(t, tag, "2017-10-17 Lakewood, NJ sunset: ok US National Oceanic and Atmospheric Administration Algorithm, not ok No Sunset", lines[4])
+}
+
+func TestWriteTableError(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	report := Compare(testRequest(calculator.NewNOAACalculator(), noSunsetCalculator{calculator.NewNOAACalculator()}))
+
+	var buf bytes.Buffer
+	assert.Equal(t, tag, nil, report.WriteTable(&buf))
+
+	// the failure of any write, of the date range, the table or the mismatches, is returned
+	for _, limit := range []int{0, 10, buf.Len() / 2, buf.Len() - 1} {
+		assert.True(t, tag, report.WriteTable(&failingWriter{limit: limit}) != nil)
+	}
+	assert.Equal(t, tag, nil, report.WriteTable(&failingWriter{limit: buf.Len()}))
+}
+
+func TestCompareOneCalculator(t *testing.T) {
+	defer assert.Raises(t, helper.CurrentFuncName())()
+	Compare(testRequest(calculator.NewNOAACalculator()))
+}
@@ -0,0 +1,207 @@
+/*
+Package compare runs two or more calculator.AstronomicalCalculator implementations over a date range and a set of
+calculator.GeoLocation for chosen zmanim and reports how far the results diverge. It allows to justify the choice of
+the algorithm used for publishing zmanim.
+*/
+package compare
+
+import (
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"sort"
+	"time"
+)
+
+/*
+Zman a named zman compared by Compare. Time returns the zman of the zmanim.ZmanimCalendar passed in,
+ok is false if the zman can't be computed (see zmanim.AstronomicalCalendar).
+*/
+type Zman struct {
+	Name string
+	Time func(zc zmanim.ZmanimCalendar) (tm time.Time, ok bool)
+}
+
+/*
+Zmanim the zmanim that can be chosen by name (see ZmanByName).
+*/
+var Zmanim = []Zman{
+	{"sunrise", zmanim.ZmanimCalendar.Sunrise},
+	{"sunset", zmanim.ZmanimCalendar.Sunset},
+	{"sea-level-sunrise", zmanim.ZmanimCalendar.SeaLevelSunrise},
+	{"sea-level-sunset", zmanim.ZmanimCalendar.SeaLevelSunset},
+	{"alos", zmanim.ZmanimCalendar.Alos},
+	{"alos72", zmanim.ZmanimCalendar.Alos72},
+	{"sof-zman-shma-gra", zmanim.ZmanimCalendar.SofZmanShmaGRA},
+	{"sof-zman-shma-mga", zmanim.ZmanimCalendar.SofZmanShmaMGA},
+	{"sof-zman-tfila-gra", zmanim.ZmanimCalendar.SofZmanTfilaGRA},
+	{"chatzos", zmanim.ZmanimCalendar.Chatzos},
+	{"mincha-gedola", zmanim.ZmanimCalendar.MinchaGedola},
+	{"mincha-ketana", zmanim.ZmanimCalendar.MinchaKetana},
+	{"plag-hamincha", zmanim.ZmanimCalendar.PlagHamincha},
+	{"candle-lighting", zmanim.ZmanimCalendar.CandleLighting},
+	{"tzais", zmanim.ZmanimCalendar.Tzais},
+	{"tzais72", zmanim.ZmanimCalendar.Tzais72},
+}
+
+/*
+ZmanByName returns the Zman of Zmanim with the name passed in, ok is false if there is no such Zman.
+*/
+func ZmanByName(name string) (zman Zman, ok bool) {
+	for _, z := range Zmanim {
+		if z.Name == name {
+			return z, true
+		}
+	}
+	return Zman{}, false
+}
+
+/*
+Calculators the constructors of the calculator.AstronomicalCalculator implementations that can be chosen by name.
+*/
+var Calculators = map[string]func() calculator.AstronomicalCalculator{
+	"noaa":     calculator.NewNOAACalculator,
+	"suntimes": calculator.NewSunTimesCalculator,
+	"spa":      func() calculator.AstronomicalCalculator { return calculator.NewSPACalculator() },
+	"meeus":    calculator.NewMeeusCalculator,
+}
+
+/*
+CalculatorNames returns the sorted names of Calculators.
+*/
+func CalculatorNames() []string {
+	var names []string
+	for name := range Calculators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+Request the input of Compare. Every pair of Calculators is compared for every GeoLocation and Zman on every date
+from From to To inclusive.
+*/
+type Request struct {
+	Calculators  []calculator.AstronomicalCalculator
+	GeoLocations []calculator.GeoLocation
+	Zmanim       []Zman
+	From         gdt.GDate
+	To           gdt.GDate
+}
+
+/*
+Mismatch a date on which only one of the calculators of a Comparison could compute the zman.
+*/
+type Mismatch struct {
+	Date string `json:"date"`
+	// Ok the name of the calculator that computed the zman
+	Ok string `json:"ok"`
+	// NotOk the name of the calculator that returned ok == false
+	NotOk string `json:"notOk"`
+}
+
+/*
+Comparison the divergence of a zman calculated by two calculators at a location over the date range.
+The divergence is the absolute difference of the two times, only days on which both calculators computed the
+zman are taken into account.
+*/
+type Comparison struct {
+	GeoLocation string    `json:"geoLocation"`
+	Zman        string    `json:"zman"`
+	Calculators [2]string `json:"calculators"`
+	// Days the number of days on which both calculators computed the zman
+	Days           int              `json:"days"`
+	MaxDivergence  gdt.GMillisecond `json:"maxDivergenceMillis"`
+	MeanDivergence gdt.GMillisecond `json:"meanDivergenceMillis"`
+	// WorstDates the dates of the MaxDivergence
+	WorstDates []string   `json:"worstDates"`
+	Mismatches []Mismatch `json:"mismatches"`
+}
+
+/*
+Report the result of Compare.
+*/
+type Report struct {
+	From        string       `json:"from"`
+	To          string       `json:"to"`
+	Comparisons []Comparison `json:"comparisons"`
+}
+
+const dateLayout = "2006-01-02"
+
+/*
+Compare runs the calculators of the request and returns the Report. A panic will be if there are less than two
+calculators or From is after To.
+*/
+func Compare(request Request) Report {
+	if len(request.Calculators) < 2 {
+		panic(fmt.Sprintf("%d calculators, at least 2 are required.", len(request.Calculators)))
+	}
+	from := request.From.ToTime(time.UTC)
+	to := request.To.ToTime(time.UTC)
+	if from.After(to) {
+		panic(fmt.Sprintf("from %s is after to %s.", from.Format(dateLayout), to.Format(dateLayout)))
+	}
+
+	report := Report{From: from.Format(dateLayout), To: to.Format(dateLayout)}
+
+	for _, geoLocation := range request.GeoLocations {
+		for _, zman := range request.Zmanim {
+			for i := 0; i < len(request.Calculators); i++ {
+				for j := i + 1; j < len(request.Calculators); j++ {
+					report.Comparisons = append(report.Comparisons,
+						compare(geoLocation, zman, request.Calculators[i], request.Calculators[j], from, to))
+				}
+			}
+		}
+	}
+
+	return report
+}
+
+func compare(geoLocation calculator.GeoLocation, zman Zman, calc1 calculator.AstronomicalCalculator, calc2 calculator.AstronomicalCalculator, from time.Time, to time.Time) Comparison {
+	comparison := Comparison{
+		GeoLocation: geoLocation.LocationName(),
+		Zman:        zman.Name,
+		Calculators: [2]string{calc1.CalculatorName(), calc2.CalculatorName()},
+		WorstDates:  []string{},
+		Mismatches:  []Mismatch{},
+	}
+
+	var total time.Duration
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		gDateTime := gdt.NewGDateTime(gdt.NewGDate1(date), gdt.NewGTime0())
+		tm1, ok1 := zman.Time(zmanim.NewZmanimCalendar(gDateTime, geoLocation, calc1))
+		tm2, ok2 := zman.Time(zmanim.NewZmanimCalendar(gDateTime, geoLocation, calc2))
+
+		switch {
+		case ok1 && !ok2:
+			comparison.Mismatches = append(comparison.Mismatches, Mismatch{Date: date.Format(dateLayout), Ok: calc1.CalculatorName(), NotOk: calc2.CalculatorName()})
+		case !ok1 && ok2:
+			comparison.Mismatches = append(comparison.Mismatches, Mismatch{Date: date.Format(dateLayout), Ok: calc2.CalculatorName(), NotOk: calc1.CalculatorName()})
+		case ok1 && ok2:
+			divergence := tm1.Sub(tm2)
+			if divergence < 0 {
+				divergence = -divergence
+			}
+			comparison.Days++
+			total += divergence
+
+			millis := gdt.GMillisecond(divergence.Milliseconds())
+			if millis > comparison.MaxDivergence || len(comparison.WorstDates) == 0 {
+				comparison.MaxDivergence = millis
+				comparison.WorstDates = []string{date.Format(dateLayout)}
+			} else if millis == comparison.MaxDivergence {
+				comparison.WorstDates = append(comparison.WorstDates, date.Format(dateLayout))
+			}
+		}
+	}
+
+	if comparison.Days > 0 {
+		comparison.MeanDivergence = gdt.GMillisecond((total / time.Duration(comparison.Days)).Milliseconds())
+	}
+
+	return comparison
+}
@@ -0,0 +1,55 @@
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+/*
+WriteJSON writes the Report to w as indented JSON.
+*/
+func (t Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(t)
+}
+
+/*
+WriteTable writes the Report to w as a text table, one row per Comparison, followed by the list of the dates on which
+only one of the calculators computed the zman.
+*/
+func (t Report) WriteTable(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%s - %s\n", t.From, t.To); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "LOCATION\tZMAN\tCALCULATORS\tDAYS\tMAX\tMEAN\tWORST DATES\tMISMATCHES"); err != nil {
+		return err
+	}
+	for _, c := range t.Comparisons {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s vs %s\t%d\t%s\t%s\t%s\t%d\n",
+			c.GeoLocation, c.Zman, c.Calculators[0], c.Calculators[1], c.Days,
+			time.Duration(c.MaxDivergence)*time.Millisecond, time.Duration(c.MeanDivergence)*time.Millisecond,
+			strings.Join(c.WorstDates, " "), len(c.Mismatches)); err != nil {
+			return err
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, c := range t.Comparisons {
+		for _, m := range c.Mismatches {
+			if _, err := fmt.Fprintf(w, "%s %s %s: ok %s, not ok %s\n", m.Date, c.GeoLocation, c.Zman, m.Ok, m.NotOk); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -51,6 +51,9 @@ type ComplexZmanimCalendar interface {
 	// Tzais19Point8Degrees and other Tzais*
 	//
 	Tzais19Point8Degrees() (tm time.Time, ok bool)
+	// SolarMidnight the midnight between the sea level sunset and the sea level sunrise of the next date
+	//
+	SolarMidnight() (tm time.Time, ok bool)
 }
 
 type complexZmanimCalendar struct {
@@ -68,7 +71,9 @@ type complexZmanimCalendar struct {
 }
 
 func newComplexZmanimCalendar() *complexZmanimCalendar {
-	return &complexZmanimCalendar{ateretTorahSunsetOffset: 40}
+	t := &complexZmanimCalendar{ateretTorahSunsetOffset: 40}
+	t.self = t
+	return t
 }
 
 func NewComplexZmanimCalendar(gDateTime gdt.GDateTime, geoLocation calculator.GeoLocation, astronomicalCalculator calculator.AstronomicalCalculator) ComplexZmanimCalendar {
@@ -79,6 +84,27 @@ func NewComplexZmanimCalendar(gDateTime gdt.GDateTime, geoLocation calculator.Ge
 	return t
 }
 
+/*
+NewComplexZmanimCalendar1 returns the ComplexZmanimCalendar of the calculator.Location.
+*/
+func NewComplexZmanimCalendar1(gDateTime gdt.GDateTime, location calculator.Location, astronomicalCalculator calculator.AstronomicalCalculator) ComplexZmanimCalendar {
+	return NewComplexZmanimCalendar(gDateTime, location.GeoLocation(), astronomicalCalculator)
+}
+
+/*
+calendarOf returns the ComplexZmanimCalendar of the gDate with the GeoLocation, the calculator.AstronomicalCalculator
+and the settings of this one, see zmanimCalendar.self.
+*/
+func (t *complexZmanimCalendar) calendarOf(gDate gdt.GDate) ZmanimCalendar {
+	czc := newComplexZmanimCalendar()
+	czc.initAstronomicalCalendar(gdt.NewGDateTime(gDate, gdt.NewGTime0()), t.geoLocation, t.astronomicalCalculator)
+	czc.useElevation = t.useElevation
+	czc.candleLightingOffset = t.candleLightingOffset
+	czc.jewishDayOffset = t.jewishDayOffset
+	czc.ateretTorahSunsetOffset = t.ateretTorahSunsetOffset
+	return czc
+}
+
 /*
 ShaahZmanis19Point8Degrees is the ethod to return a shaah zmanis (temporal hour) calculated using a 19.8 deg dip.
 This calculation divides the day based on the opinion
@@ -2885,7 +2911,9 @@ This method is not tied to the theoretical 15 deg timezones, but will adjust to
 The method return the time.Time representing the local chatzos
 */
 func (t *complexZmanimCalendar) FixedLocalChatzos() time.Time {
-	return timeOffset(t.dateTimeFromTimeOfDay(12.0-float64(t.GeoLocation().StandardTimeOffset())/float64(timeutil.HourMillis), true), -t.GeoLocation().LocalMeanTimeOffset())
+	standardTimeOffset := calculator.StandardTimeOffsetAt(t.GeoLocation(), t.LocalNoon())
+	localMeanTimeOffset := gdt.GMillisecond(t.GeoLocation().Longitude()*4*timeutil.MinuteMillis - float64(standardTimeOffset))
+	return timeOffset(t.dateTimeFromTimeOfDay(12.0-float64(standardTimeOffset)/float64(timeutil.HourMillis), true), -localMeanTimeOffset)
 }
 
 func isJewishDayOfMonthBetween(jewishCalendar hebrewcalendar.JewishCalendar, from jdt.JDay, to jdt.JDay) {
@@ -3402,19 +3430,19 @@ SolarMidnight that returns "solar" midnight, or the time when the sun is at its
 [nadir]: https://en.wikipedia.org/wiki/Nadir.
 Note: this method is experimental and might be removed.
 
-The method return the time.Time of Solar Midnight (chatzos layla).
+The method return the time.Time of Solar Midnight (chatzos layla), the middle of the night between the sea level sunset of
+the date and the sea level sunrise of the next date, see ZmanimCalendar.NextDay. It is after the civil midnight in most
+of the time zones.
 If the calculation can't be computed such as in the Arctic Circle where there is at least one day a year,
 where the sun does not rise, and one where it does not set, ok is false will be returned.
 See detailed explanation on top of the AstronomicalCalendar documentation.
 */
 func (t *complexZmanimCalendar) SolarMidnight() (tm time.Time, ok bool) {
-	clonedCal := *t
-	clonedCal.gDateTime.ToTime(nil).AddDate(0, 0, 1)
 	sunset, ok := t.SeaLevelSunset()
 	if !ok {
 		return time.Time{}, false
 	}
-	sunrise, ok := clonedCal.SeaLevelSunrise()
+	sunrise, ok := t.NextDay().SeaLevelSunrise()
 	if !ok {
 		return time.Time{}, false
 	}
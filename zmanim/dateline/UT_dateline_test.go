@@ -0,0 +1,170 @@
+package dateline
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/jdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"testing"
+	"time"
+)
+
+func honoluluGeoLocation() calculator.GeoLocation {
+	return calculator.NewGeoLocation2("Honolulu, HI", 21.3069, -157.8583, 5, timeutil.LoadLocationOrPanic("Pacific/Honolulu"))
+}
+
+func aucklandGeoLocation() calculator.GeoLocation {
+	return calculator.NewGeoLocation2("Auckland, New Zealand", -36.8485, 174.7633, 20, timeutil.LoadLocationOrPanic("Pacific/Auckland"))
+}
+
+func vladivostokGeoLocation() calculator.GeoLocation {
+	return calculator.NewGeoLocation2("Vladivostok, Russia", 43.1155, 131.8855, 10, timeutil.LoadLocationOrPanic("Asia/Vladivostok"))
+}
+
+func sydneyGeoLocation() calculator.GeoLocation {
+	return calculator.NewGeoLocation2("Sydney, Australia", -33.8688, 151.2093, 10, timeutil.LoadLocationOrPanic("Australia/Sydney"))
+}
+
+func TestDayOffset(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// Friday
+	friday := gdt.NewGDate(2024, 3, 15)
+
+	for _, test := range []struct {
+		geoLocation                                             calculator.GeoLocation
+		chazonIsh, chazonIshMeridian, tukachinsky, antimeridian int
+	}{
+		{calculator.JerusalemGeoLocation(), 0, 0, 0, 0},
+		{calculator.LakewoodGeoLocation(), 0, 0, 0, 0},
+		{calculator.LosAngelesGeoLocation(), 0, 0, 0, 0},
+		{calculator.TokyoGeoLocation(), -1, -1, 0, 0},
+		{calculator.SamoaGeoLocation(), -1, -1, 0, -1},
+		{honoluluGeoLocation(), 0, 0, 1, 0},
+		{aucklandGeoLocation(), -1, -1, 0, 0},
+		{vladivostokGeoLocation(), 0, -1, 0, 0},
+		{sydneyGeoLocation(), 0, -1, 0, 0},
+	} {
+		name := test.geoLocation.LocationName()
+		assert.Equal(t, tag+" "+name, 0, DayOffset(test.geoLocation, friday, OpinionCivil))
+		assert.Equal(t, tag+" "+name, test.chazonIsh, DayOffset(test.geoLocation, friday, OpinionChazonIsh))
+		assert.Equal(t, tag+" "+name, test.chazonIshMeridian, DayOffset(test.geoLocation, friday, OpinionChazonIshMeridian))
+		assert.Equal(t, tag+" "+name, test.tukachinsky, DayOffset(test.geoLocation, friday, OpinionTukachinsky))
+		assert.Equal(t, tag+" "+name, test.antimeridian, DayOffset(test.geoLocation, friday, OpinionAntimeridian))
+	}
+}
+
+func TestDayOffsetWithoutTimeZone(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	tokyo := calculator.TokyoGeoLocation()
+	tokyo.SetTimeZone(nil)
+	assert.Equal(t, tag, -1, DayOffset(tokyo, gdt.NewGDate(2024, 3, 15), OpinionChazonIsh))
+	assert.Equal(t, tag, 0, DayOffset(tokyo, gdt.NewGDate(2024, 3, 15), OpinionTukachinsky))
+}
+
+func TestOnAsianMainland(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// Seoul, Busan, Vladivostok, Petropavlovsk-Kamchatsky, Uelen (Chukotka), Brisbane, Melbourne
+	for _, location := range [][2]float64{
+		{37.5665, 126.978}, {35.1796, 129.0756}, {43.1155, 131.8855}, {53.0452, 158.6483}, {66.1597, -169.8092},
+		{-27.4698, 153.0251}, {-37.8136, 144.9631},
+	} {
+		assert.True(t, tag, onAsianMainland(location[0], location[1]))
+	}
+	// Tokyo, Sapporo, Fukuoka, Yuzhno-Sakhalinsk, Hobart, Auckland, Honolulu
+	for _, location := range [][2]float64{
+		{35.6762, 139.6503}, {43.0618, 141.3545}, {33.5902, 130.4017}, {46.9591, 142.7381}, {-42.8821, 147.3272},
+		{-36.8485, 174.7633}, {21.3069, -157.8583},
+	} {
+		assert.False(t, tag, onAsianMainland(location[0], location[1]))
+	}
+}
+
+func TestNewHalachicDay(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// Sunday March 17, 2024 in Tokyo is Shabbos, 6 Adar II 5784, according to the Chazon Ish
+	subject := NewHalachicDay(calculator.TokyoGeoLocation(), gdt.NewGDate(2024, 3, 17), OpinionChazonIsh)
+	assert.Equal(t, tag, gdt.NewGDate(2024, 3, 17), subject.CivilDate)
+	assert.Equal(t, tag, -1, subject.Offset)
+	assert.Equal(t, tag, gdt.NewGDate(2024, 3, 16), subject.GDate)
+	assert.Equal(t, tag, time.Saturday, subject.Weekday)
+	assert.True(t, tag, subject.IsShabbos())
+	assert.Equal(t, tag, jdt.NewJDate(5784, jdt.AdarII, 6), subject.JewishDate)
+
+	// Friday March 15, 2024 in Honolulu is Shabbos according to Rav Tukachinsky
+	subject = NewHalachicDay(honoluluGeoLocation(), gdt.NewGDate(2024, 3, 15), OpinionTukachinsky)
+	assert.Equal(t, tag, 1, subject.Offset)
+	assert.True(t, tag, subject.IsShabbos())
+
+	subject = NewHalachicDay(honoluluGeoLocation(), gdt.NewGDate(2024, 3, 15), OpinionCivil)
+	assert.Equal(t, tag, 0, subject.Offset)
+	assert.Equal(t, tag, time.Friday, subject.Weekday)
+}
+
+func TestCalendar(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject := NewCalendar(calculator.TokyoGeoLocation(), calculator.NewNOAACalculator(), OpinionChazonIsh)
+	assert.Equal(t, tag, OpinionChazonIsh, subject.Opinion())
+
+	// the candles are lit on the civil Saturday, the halachic erev Shabbos
+	_, ok := subject.CandleLighting(gdt.NewGDate(2024, 3, 15))
+	assert.False(t, tag, ok)
+	candleLighting, ok := subject.CandleLighting(gdt.NewGDate(2024, 3, 16))
+	assert.True(t, tag, ok)
+	sunset, _ := subject.ZmanimCalendar(gdt.NewGDate(2024, 3, 16)).Shkia()
+	assert.Equal(t, tag, sunset.Add(-18*time.Minute), candleLighting)
+
+	tokyo := calculator.TokyoGeoLocation().TimeZone()
+	assert.False(t, tag, subject.IsAssurBemlacha(time.Date(2024, 3, 16, 12, 0, 0, 0, tokyo)))
+	assert.True(t, tag, subject.IsAssurBemlacha(time.Date(2024, 3, 16, 23, 0, 0, 0, tokyo)))
+	assert.True(t, tag, subject.IsAssurBemlacha(time.Date(2024, 3, 17, 12, 0, 0, 0, tokyo)))
+	assert.False(t, tag, subject.IsAssurBemlacha(time.Date(2024, 3, 17, 21, 0, 0, 0, tokyo)))
+
+	// the zmanim calendar of the civil Sunday is of the halachic Shabbos
+	zmanimCalendar := subject.ZmanimCalendar(gdt.NewGDate(2024, 3, 17))
+	assert.Equal(t, tag, -1, zmanimCalendar.JewishDayOffset())
+	tzais, _ := zmanimCalendar.Tzais()
+	assert.True(t, tag, zmanimCalendar.IsAssurBemlacha(time.Date(2024, 3, 17, 12, 0, 0, 0, tokyo), tzais, false))
+	civilCalendar := zmanim.NewZmanimCalendar(gdt.NewGDateTime(gdt.NewGDate(2024, 3, 17), gdt.NewGTime0()), calculator.TokyoGeoLocation(), calculator.NewNOAACalculator())
+	assert.False(t, tag, civilCalendar.IsAssurBemlacha(time.Date(2024, 3, 17, 12, 0, 0, 0, tokyo), tzais, false))
+	civilCalendar.SetJewishDayOffset(subject.HalachicDay(gdt.NewGDate(2024, 3, 17)).Offset)
+	assert.True(t, tag, civilCalendar.IsAssurBemlacha(time.Date(2024, 3, 17, 12, 0, 0, 0, tokyo), tzais, false))
+
+	// Yom Kippur 5785 was on Saturday, October 12, 2024, in Tokyo on the civil Sunday according to the Chazon Ish
+	assert.Equal(t, tag, hebrewcalendar.YomKippur, subject.JewishCalendar(gdt.NewGDate(2024, 10, 13)).YomTov())
+	assert.Equal(t, tag, hebrewcalendar.YomKippur, NewCalendar(calculator.TokyoGeoLocation(), calculator.NewNOAACalculator(), OpinionCivil).JewishCalendar(gdt.NewGDate(2024, 10, 12)).YomTov())
+}
+
+func TestCalendarInIsrael(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the second day of Shavuos 5784, Thursday, June 13, 2024, is Yom Tov only outside Israel
+	subject := NewCalendar(calculator.JerusalemGeoLocation(), calculator.NewNOAACalculator(), OpinionCivil)
+	jerusalem := calculator.JerusalemGeoLocation().TimeZone()
+	assert.False(t, tag, subject.IsInIsrael())
+	assert.True(t, tag, subject.IsAssurBemlacha(time.Date(2024, 6, 13, 12, 0, 0, 0, jerusalem)))
+	_, ok := subject.CandleLighting(gdt.NewGDate(2024, 6, 12))
+	assert.True(t, tag, ok)
+
+	subject.SetInIsrael(true)
+	assert.True(t, tag, subject.IsInIsrael())
+	assert.True(t, tag, subject.JewishCalendar(gdt.NewGDate(2024, 6, 13)).IsInIsrael())
+	assert.False(t, tag, subject.IsAssurBemlacha(time.Date(2024, 6, 13, 12, 0, 0, 0, jerusalem)))
+	_, ok = subject.CandleLighting(gdt.NewGDate(2024, 6, 12))
+	assert.False(t, tag, ok)
+}
+
+func TestNewCalendarUnknownOpinion(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	defer assert.Raises(t, tag)()
+
+	NewCalendar(calculator.TokyoGeoLocation(), calculator.NewNOAACalculator(), Opinion(100))
+}
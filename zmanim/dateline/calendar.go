@@ -0,0 +1,130 @@
+package dateline
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"time"
+)
+
+/*
+Calendar the Shabbos and Yom Tov of the civil dates at a calculator.GeoLocation according to an Opinion on the date
+line. The zmanim are of the civil date, as the sun doesn't depend on the date line, while the Jewish date, and so
+Shabbos and Yom Tov, is of the HalachicDay. By default, the melacha prohibition ends at zmanim.ZmanimCalendar.Tzais.
+*/
+type Calendar interface {
+	GeoLocation() calculator.GeoLocation
+	Opinion() Opinion
+	// HalachicDay returns the HalachicDay of the civil date gDate
+	HalachicDay(gDate gdt.GDate) HalachicDay
+	// JewishCalendar returns the hebrewcalendar.JewishCalendar of the HalachicDay of the civil date gDate, for the holidays,
+	// with the IsInIsrael of this Calendar
+	JewishCalendar(gDate gdt.GDate) hebrewcalendar.JewishCalendar
+	// ZmanimCalendar returns the zmanim.ZmanimCalendar of the civil date gDate, with the zmanim.ZmanimCalendar.JewishDayOffset
+	// of the HalachicDay
+	ZmanimCalendar(gDate gdt.GDate) zmanim.ZmanimCalendar
+	// CandleLighting returns the zmanim.ZmanimCalendar.CandleLighting of the civil date gDate, ok is false if the
+	// HalachicDay has no candle lighting (see hebrewcalendar.JewishCalendar.HasCandleLighting)
+	CandleLighting(gDate gdt.GDate) (tm time.Time, ok bool)
+	// IsAssurBemlacha returns if melacha is prohibited at the currentTime, with the Israel holiday scheme if IsInIsrael,
+	// see zmanim.ZmanimCalendar.IsAssurBemlacha
+	IsAssurBemlacha(currentTime time.Time) bool
+	// IsInIsrael returns if the holidays are of the Israel holiday scheme, false by default
+	IsInIsrael() bool
+	SetInIsrael(inIsrael bool)
+	SetTzais(tzais func(zc zmanim.ZmanimCalendar) (tm time.Time, ok bool))
+}
+
+type calendar struct {
+	geoLocation            calculator.GeoLocation
+	astronomicalCalculator calculator.AstronomicalCalculator
+	opinion                Opinion
+	tzais                  func(zc zmanim.ZmanimCalendar) (tm time.Time, ok bool)
+	inIsrael               bool
+}
+
+func newCalendar() *calendar {
+	return &calendar{}
+}
+
+func (t *calendar) initCalendar(geoLocation calculator.GeoLocation, astronomicalCalculator calculator.AstronomicalCalculator, opinion Opinion) {
+	if opinion < OpinionCivil || opinion > OpinionAntimeridian {
+		panic("unknown Opinion")
+	}
+	t.geoLocation = geoLocation
+	t.astronomicalCalculator = astronomicalCalculator
+	t.opinion = opinion
+	t.tzais = zmanim.ZmanimCalendar.Tzais
+}
+
+func NewCalendar(geoLocation calculator.GeoLocation, astronomicalCalculator calculator.AstronomicalCalculator, opinion Opinion) Calendar {
+	t := newCalendar()
+
+	t.initCalendar(geoLocation, astronomicalCalculator, opinion)
+
+	return t
+}
+
+func (t *calendar) GeoLocation() calculator.GeoLocation {
+	return t.geoLocation
+}
+
+func (t *calendar) Opinion() Opinion {
+	return t.opinion
+}
+
+func (t *calendar) IsInIsrael() bool {
+	return t.inIsrael
+}
+
+func (t *calendar) SetInIsrael(inIsrael bool) {
+	t.inIsrael = inIsrael
+}
+
+func (t *calendar) SetTzais(tzais func(zc zmanim.ZmanimCalendar) (tm time.Time, ok bool)) {
+	if tzais == nil {
+		panic("tzais == nil")
+	}
+	t.tzais = tzais
+}
+
+func (t *calendar) HalachicDay(gDate gdt.GDate) HalachicDay {
+	return NewHalachicDay(t.geoLocation, gDate, t.opinion)
+}
+
+func (t *calendar) JewishCalendar(gDate gdt.GDate) hebrewcalendar.JewishCalendar {
+	jewishCalendar := hebrewcalendar.NewJewishCalendar(hebrewcalendar.NewJewishDate2(t.HalachicDay(gDate).GDate))
+	jewishCalendar.SetInIsrael(t.inIsrael)
+	return jewishCalendar
+}
+
+func (t *calendar) ZmanimCalendar(gDate gdt.GDate) zmanim.ZmanimCalendar {
+	zmanimCalendar := zmanim.NewZmanimCalendar(gdt.NewGDateTime(gDate, gdt.NewGTime0()), t.geoLocation, t.astronomicalCalculator)
+	zmanimCalendar.SetJewishDayOffset(t.HalachicDay(gDate).Offset)
+	return zmanimCalendar
+}
+
+func (t *calendar) CandleLighting(gDate gdt.GDate) (tm time.Time, ok bool) {
+	if !t.JewishCalendar(gDate).HasCandleLighting() {
+		return time.Time{}, false
+	}
+	return t.ZmanimCalendar(gDate).CandleLighting()
+}
+
+/*
+IsAssurBemlacha returns the zmanim.ZmanimCalendar.IsAssurBemlacha of the ZmanimCalendar of the civil date of the
+currentTime with its tzais and the IsInIsrael of this Calendar, melacha is prohibited from the sunset of a civil date whose HalachicDay has candle lighting,
+and until the tzais of a civil date whose HalachicDay is Shabbos or Yom Tov.
+*/
+func (t *calendar) IsAssurBemlacha(currentTime time.Time) bool {
+	zmanimCalendar := t.ZmanimCalendar(gdt.NewGDate1(currentTime.In(t.location())))
+	// without tzais, the zero time, melacha is not prohibited by the Shabbos or Yom Tov of the civil date
+	tzais, _ := t.tzais(zmanimCalendar)
+	return zmanimCalendar.IsAssurBemlacha(currentTime, tzais, t.inIsrael)
+}
+
+func (t *calendar) location() *time.Location {
+	return timeutil.TimeZoneOrGmt(t.geoLocation.TimeZone())
+}
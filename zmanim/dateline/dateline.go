@@ -0,0 +1,153 @@
+/*
+Package dateline determines the halachic day of the week and the Jewish date at a calculator.GeoLocation according to
+the opinions on the halachic international date line. At locations such as Japan, New Zealand, Hawaii and Samoa the
+halachic day may differ from the civil date by one day, so that Shabbos and Yom Tov fall on a different civil date.
+The HalachicDay.Offset is the zmanim.ZmanimCalendar.SetJewishDayOffset of the zmanim calendars of such locations, see
+Calendar.
+*/
+package dateline
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/jdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"math"
+	"time"
+)
+
+/*
+JerusalemLongitude the longitude of the Temple Mount in Jerusalem, from which the halachic date lines are measured.
+*/
+const JerusalemLongitude = 35.2354
+
+/*
+Opinion the opinion on the halachic date line.
+*/
+type Opinion int
+
+const (
+	// OpinionCivil the civil international date line, the halachic day is always the civil date
+	OpinionCivil Opinion = iota
+	// OpinionChazonIsh the line 90 deg east of Jerusalem (125.2354 E), except that the continental mainland of Asia and
+	// Australia which extends east of it (Korea, the Russian Far East, eastern Australia) remains on the Asian side
+	OpinionChazonIsh
+	// OpinionChazonIshMeridian the line 90 deg east of Jerusalem strictly along the meridian, without the mainland
+	// exception of OpinionChazonIsh
+	OpinionChazonIshMeridian
+	// OpinionTukachinsky the opinion of Rav Yechiel Michel Tukachinsky, the line 180 deg from Jerusalem (144.7646 W)
+	OpinionTukachinsky
+	// OpinionAntimeridian the 180 deg meridian, without the deviations of the civil international date line
+	OpinionAntimeridian
+)
+
+func (t Opinion) String() string {
+	switch t {
+	case OpinionCivil:
+		return "Civil"
+	case OpinionChazonIsh:
+		return "ChazonIsh"
+	case OpinionChazonIshMeridian:
+		return "ChazonIshMeridian"
+	case OpinionTukachinsky:
+		return "Tukachinsky"
+	case OpinionAntimeridian:
+		return "Antimeridian"
+	default:
+		return "Unknown"
+	}
+}
+
+/*
+lineDistance returns the distance of the line of the opinion eastward from Jerusalem in degrees.
+*/
+func (t Opinion) lineDistance() float64 {
+	switch t {
+	case OpinionChazonIsh, OpinionChazonIshMeridian:
+		return 90
+	case OpinionTukachinsky:
+		return 180
+	case OpinionAntimeridian:
+		return 180 - JerusalemLongitude
+	default:
+		panic("unknown Opinion")
+	}
+}
+
+/*
+HalachicDay the halachic day at a location on a civil date.
+*/
+type HalachicDay struct {
+	// CivilDate the civil date at the location
+	CivilDate gdt.GDate
+	// Offset the days the halachic day is ahead (1) or behind (-1) of the civil date, 0 if they are the same
+	Offset int
+	// GDate the Gregorian date of the halachic day, the CivilDate moved by the Offset days
+	GDate gdt.GDate
+	// Weekday the halachic day of the week
+	Weekday time.Weekday
+	// JewishDate the halachic Jewish date
+	JewishDate jdt.JDate
+}
+
+/*
+IsShabbos is the halachic day Shabbos.
+*/
+func (t HalachicDay) IsShabbos() bool {
+	return t.Weekday == time.Saturday
+}
+
+/*
+NewHalachicDay returns the HalachicDay at the geoLocation on the civil date gDate according to the opinion.
+*/
+func NewHalachicDay(geoLocation calculator.GeoLocation, gDate gdt.GDate, opinion Opinion) HalachicDay {
+	return newHalachicDay(gDate, DayOffset(geoLocation, gDate, opinion))
+}
+
+func newHalachicDay(gDate gdt.GDate, offset int) HalachicDay {
+	halachicDate := gdt.NewGDate2(gDate.ToAbsDate() + gdt.GDay(offset))
+	jewishDate := hebrewcalendar.NewJewishDate2(halachicDate)
+
+	return HalachicDay{
+		CivilDate:  gDate,
+		Offset:     offset,
+		GDate:      halachicDate,
+		Weekday:    halachicDate.ToTime(nil).Weekday(),
+		JewishDate: jewishDate.JDate(),
+	}
+}
+
+/*
+DayOffset returns the days the halachic day at the geoLocation is ahead (1) or behind (-1) of the civil date gDate
+according to the opinion, 0 if they are the same.
+The halachic day begins in Jerusalem, and each location east of it up to the line of the opinion reckons the same date
+as Jerusalem, while the locations beyond the line reckon the date of the locations west of Jerusalem. The offset is the
+difference between this date and the civil one, which follows the time zone of the geoLocation at noon. If the time
+zone is nil, the civil date is reckoned by the nearest whole hour of the mean solar time of the longitude.
+*/
+func DayOffset(geoLocation calculator.GeoLocation, gDate gdt.GDate, opinion Opinion) int {
+	if opinion == OpinionCivil {
+		return 0
+	}
+	latitude, longitude := geoLocation.Latitude(), geoLocation.Longitude()
+
+	// the longitude counted eastward from Jerusalem up to the line of the opinion and westward beyond it
+	east := math.Mod(math.Mod(longitude-JerusalemLongitude, 360)+360, 360)
+	halachicLongitude := JerusalemLongitude + east
+	if east >= opinion.lineDistance() && !(opinion == OpinionChazonIsh && onAsianMainland(latitude, longitude)) {
+		halachicLongitude -= 360
+	}
+
+	return int(math.Round((halachicLongitude/15 - civilOffsetHours(longitude, geoLocation.TimeZone(), gDate)) / 24))
+}
+
+/*
+civilOffsetHours returns the offset from UTC of the timeZone at noon of the gDate in hours.
+*/
+func civilOffsetHours(longitude float64, timeZone *time.Location, gDate gdt.GDate) float64 {
+	if timeZone == nil {
+		return math.Round(longitude / 15)
+	}
+	_, offset := time.Date(int(gDate.Year), gDate.Month, int(gDate.Day), 12, 0, 0, 0, timeZone).Zone()
+	return float64(offset) / 3600
+}
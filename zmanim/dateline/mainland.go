@@ -0,0 +1,62 @@
+package dateline
+
+/*
+asianMainland the coarse outlines of the continental mainland east of the line of OpinionChazonIsh, as the
+(longitude, latitude) vertices of the polygons, the longitudes west of the 180 deg meridian are continued past 180.
+The outlines run slightly offshore, so that the coastal cities are inside, and leave out the islands: Japan,
+Sakhalin, Tasmania, New Zealand.
+*/
+var asianMainland = [][][2]float64{
+	// Korea and the Russian Far East, Kamchatka and Chukotka
+	{
+		{125.0, 73.5}, {125.0, 37.5}, {125.0, 34.2}, {126.5, 34.0}, {128.0, 34.5}, {129.3, 34.9}, {129.7, 36.0},
+		{129.6, 37.2}, {128.8, 38.6}, {128.2, 39.5}, {129.0, 40.2}, {130.0, 40.9}, {130.4, 41.8}, {131.0, 42.3},
+		{132.0, 42.6}, {133.2, 42.5}, {135.3, 43.5}, {137.0, 44.8}, {138.5, 46.6}, {139.5, 47.8}, {140.6, 49.0},
+		{140.8, 50.5}, {141.1, 51.5}, {141.5, 52.2}, {141.2, 53.0}, {140.0, 53.6}, {138.0, 54.0}, {138.5, 56.6},
+		{141.0, 58.5}, {143.5, 59.2}, {148.0, 59.2}, {151.0, 59.4}, {154.5, 59.0}, {156.5, 61.5}, {158.5, 61.3},
+		{156.0, 57.8}, {155.5, 55.0}, {156.5, 50.7}, {158.8, 52.6}, {160.2, 54.5}, {162.3, 56.0}, {163.5, 58.0},
+		{166.0, 60.0}, {170.5, 59.8}, {174.0, 61.7}, {177.3, 62.4}, {179.3, 62.6}, {178.5, 64.0}, {181.5, 65.0},
+		{184.0, 64.7}, {186.7, 64.2}, {188.5, 65.5}, {190.4, 66.1}, {187.0, 67.2}, {183.0, 68.5}, {180.0, 69.0},
+		{175.0, 69.9}, {170.0, 70.1}, {160.0, 69.8}, {150.0, 71.5}, {140.0, 72.9}, {130.0, 71.8},
+	},
+	// Australia
+	{
+		{125.0, -14.2}, {127.5, -13.8}, {129.5, -14.7}, {130.0, -12.8}, {131.0, -12.0}, {132.6, -11.2},
+		{134.0, -11.8}, {136.9, -11.9}, {136.5, -13.5}, {135.5, -15.0}, {137.5, -16.2}, {139.3, -17.3},
+		{140.8, -17.4}, {141.5, -15.0}, {141.5, -12.5}, {142.5, -10.6}, {143.5, -12.5}, {144.5, -14.2},
+		{145.5, -15.0}, {145.7, -16.8}, {146.4, -18.8}, {148.8, -20.2}, {150.8, -22.5}, {152.9, -25.3},
+		{153.7, -28.6}, {153.2, -30.5}, {152.6, -32.3}, {151.5, -33.8}, {150.3, -35.8}, {150.1, -37.5},
+		{148.2, -37.9}, {146.4, -39.2}, {144.8, -38.4}, {142.0, -38.5}, {140.3, -38.1}, {139.3, -36.5},
+		{138.0, -35.8}, {135.5, -35.0}, {134.0, -33.0}, {131.5, -31.5}, {128.5, -32.0}, {125.0, -32.5},
+	},
+}
+
+/*
+onAsianMainland is the location inside one of the outlines of asianMainland.
+*/
+func onAsianMainland(latitude float64, longitude float64) bool {
+	if longitude < 0 {
+		longitude += 360
+	}
+	for _, polygon := range asianMainland {
+		if insidePolygon(polygon, longitude, latitude) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+insidePolygon is the point (x, y) inside the polygon, by the even-odd count of the edges crossed by the ray from the
+point eastward.
+*/
+func insidePolygon(polygon [][2]float64, x float64, y float64) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		xi, yi, xj, yj := polygon[i][0], polygon[i][1], polygon[j][0], polygon[j][1]
+		if (yi > y) != (yj > y) && x < xi+(y-yi)*(xj-xi)/(yj-yi) {
+			inside = !inside
+		}
+	}
+	return inside
+}
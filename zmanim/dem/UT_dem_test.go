@@ -0,0 +1,217 @@
+package dem
+
+import (
+	"encoding/binary"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+/*
+writeHgtFile writes the .hgt tile N31E035.hgt of 121 x 121 samples (30 arc seconds) of the elevation function to the
+directory.
+*/
+func writeHgtFile(t *testing.T, dir string, elevation func(latitude float64, longitude float64) float64) {
+	const size = 121
+	data := make([]byte, size*size*2)
+	for row := 0; row < size; row++ {
+		for column := 0; column < size; column++ {
+			value := elevation(32-float64(row)/(size-1), 35+float64(column)/(size-1))
+			binary.BigEndian.PutUint16(data[2*(row*size+column):], uint16(int16(math.Round(value))))
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "N31E035.hgt"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHgtName(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	assert.Equal(t, tag, "N31E035.hgt", hgtName(31.77, 35.23))
+	assert.Equal(t, tag, "S14W172.hgt", hgtName(-13.86, -171.8))
+	assert.Equal(t, tag, "N00W001.hgt", hgtName(0.5, -0.5))
+
+	south, west, err := parseHgtName("s14w172.hgt")
+	assert.True(t, tag, err == nil)
+	assert.Equal(t, tag, -14, south)
+	assert.Equal(t, tag, -172, west)
+
+	_, _, err = parseHgtName("dead_sea.asc")
+	assert.False(t, tag, err == nil)
+}
+
+func TestHgtElevation(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	dir := t.TempDir()
+	// a plane, which the bilinear interpolation reproduces exactly, with a void
+	writeHgtFile(t, dir, func(latitude float64, longitude float64) float64 {
+		if latitude == 32 && longitude == 36 {
+			return hgtNoData
+		}
+		return 100 + 1200*(longitude-35) + 600*(latitude-31)
+	})
+	source, err := OpenDirectory(dir)
+	assert.True(t, tag, err == nil)
+
+	elevation, ok, err := source.Elevation(31.5, 35.25)
+	assert.True(t, tag, ok && err == nil)
+	assert.True(t, tag, math.Abs(float64(elevation)-700) < 0.5)
+	elevation, ok, err = source.Elevation(31.00625, 35.00625)
+	assert.True(t, tag, ok)
+	assert.True(t, tag, math.Abs(float64(elevation)-111.25) < 0.5)
+
+	// the void is skipped
+	elevation, ok, err = source.Elevation(31.999, 35.999)
+	assert.True(t, tag, ok)
+	_, ok, err = source.Elevation(32, 36)
+	assert.False(t, tag, ok)
+
+	// no tile
+	_, ok, err = source.Elevation(40, 35.5)
+	assert.False(t, tag, ok)
+	assert.True(t, tag, err == nil)
+}
+
+func TestConcurrentElevation(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	dir := t.TempDir()
+	writeHgtFile(t, dir, func(latitude float64, longitude float64) float64 { return 754 })
+	source, _ := OpenDirectory(dir)
+
+	// the tiles are loaded by the first of the goroutines, go test -race reports a race
+	var wg sync.WaitGroup
+	results := make([]bool, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			elevation, ok, err := source.Elevation(31.5, 35.25)
+			results[i] = ok && err == nil && math.Abs(float64(elevation)-754) < 1e-6
+		}(i)
+	}
+	wg.Wait()
+	for _, result := range results {
+		assert.True(t, tag, result)
+	}
+}
+
+func TestCorruptHgtTile(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "N31E035.hgt"), []byte{0, 1, 2}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	source, _ := OpenDirectory(dir)
+
+	// the tile is reported, not taken for a missing one
+	for i := 0; i < 2; i++ {
+		_, ok, err := source.Elevation(31.5, 35.25)
+		assert.False(t, tag, ok)
+		assert.False(t, tag, err == nil)
+	}
+
+	ok, err := PopulateElevation(source, calculator.NewGeoLocation1("", 31.5, 35.25, nil))
+	assert.False(t, tag, ok || err == nil)
+	_, err = NewHorizonProfile(source, calculator.NewGeoLocation1("", 31.5, 35.25, nil), 90, 1)
+	assert.False(t, tag, err == nil)
+}
+
+func TestAsciiGridElevation(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	source, err := OpenDirectory("testdata")
+	assert.True(t, tag, err == nil)
+
+	elevation, ok, err := source.Elevation(31.7, 35.65)
+	assert.True(t, tag, ok && err == nil)
+	assert.True(t, tag, math.Abs(float64(elevation)+325) < 1e-6)
+	elevation, ok, err = source.Elevation(31.6, 35.55)
+	assert.True(t, tag, ok)
+	assert.True(t, tag, math.Abs(float64(elevation)+400) < 1e-6)
+
+	// the void sample
+	_, ok, err = source.Elevation(31.6, 35.6)
+	assert.False(t, tag, ok)
+	// out of the grid
+	_, ok, err = source.Elevation(31.45, 35.55)
+	assert.False(t, tag, ok)
+
+	_, err = OpenDirectory("testdata/dead_sea.asc")
+	assert.False(t, tag, err == nil)
+}
+
+func TestPopulateElevation(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	dir := t.TempDir()
+	writeHgtFile(t, dir, func(latitude float64, longitude float64) float64 { return 754 })
+	source, _ := OpenDirectory(dir)
+
+	geoLocation := calculator.NewGeoLocation1("Jerusalem, Israel", 31.7781161, 35.233804, timeutil.LoadLocationOrPanic("Asia/Jerusalem"))
+	ok, err := PopulateElevation(source, geoLocation)
+	assert.True(t, tag, ok && err == nil)
+	assert.True(t, tag, math.Abs(float64(geoLocation.Elevation())-754) < 1e-6)
+
+	// below the sea level, the elevation is not changed
+	source, _ = OpenDirectory("testdata")
+	geoLocation.SetLatitude1(31.6)
+	geoLocation.SetLongitude1(35.55)
+	ok, err = PopulateElevation(source, geoLocation)
+	assert.False(t, tag, ok || err != nil)
+	assert.True(t, tag, math.Abs(float64(geoLocation.Elevation())-754) < 1e-6)
+
+	geoLocation.SetLatitude1(40)
+	ok, err = PopulateElevation(source, geoLocation)
+	assert.False(t, tag, ok || err != nil)
+}
+
+func TestNewHorizonProfile(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	dir := t.TempDir()
+	// a ridge 1000 m high from the longitude 35.6 eastward
+	writeHgtFile(t, dir, func(latitude float64, longitude float64) float64 {
+		if longitude >= 35.6-1e-9 {
+			return 1000
+		}
+		return 0
+	})
+	source, _ := OpenDirectory(dir)
+
+	geoLocation := calculator.NewGeoLocation2("Ridge", 31.5, 35.5, 0, timeutil.LoadLocationOrPanic("Asia/Jerusalem"))
+	subject, err := NewHorizonProfile(source, geoLocation, 1, 20)
+	assert.True(t, tag, err == nil)
+	assert.Equal(t, tag, 360, len(subject.Points()))
+
+	// about 9.5 km to the ridge
+	east := subject.Altitude(90)
+	assert.True(t, tag, east > 5.9 && east < 6.05)
+	assert.True(t, tag, subject.Altitude(45) < east)
+	assert.Equal(t, tag, dimension.Degrees(0), subject.Altitude(270))
+
+	// the sea-level horizon of an observer 100 m high
+	geoLocation.SetElevation(100)
+	subject, err = NewHorizonProfile(source, geoLocation, 10, 5)
+	assert.True(t, tag, err == nil)
+	assert.True(t, tag, math.Abs(float64(subject.Altitude(270))+0.30) < 0.01)
+}
+
+func TestNewHorizonProfilePanics(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	defer assert.Raises(t, tag)()
+
+	source, _ := OpenDirectory("testdata")
+	_, _ = NewHorizonProfile(source, calculator.NewGeoLocation(), 0, 50)
+}
@@ -0,0 +1,216 @@
+/*
+Package dem reads the ground elevation from local digital elevation model (DEM) tiles, the SRTM .hgt tiles and the
+ESRI ASCII grid (.asc) tiles, fully offline. It can populate the elevation of a calculator.GeoLocation and derive its
+calculator.HorizonProfile for the visible sunrise and sunset by ray-marching the DEM.
+*/
+package dem
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+/*
+Source the elevation above sea level of the ground at a location.
+*/
+type Source interface {
+	/*
+		Elevation returns the elevation of the ground at the latitude and longitude, bilinearly interpolated between the
+		samples of the DEM. ok is false if there is no tile for the location or its samples are voids, err is the error
+		of a tile that can't be read.
+	*/
+	Elevation(latitude float64, longitude float64) (elevation dimension.Meters, ok bool, err error)
+}
+
+/*
+tile a grid of elevation samples, the row 0 is the northernmost and the column 0 the westernmost.
+*/
+type tile struct {
+	// north and west the latitude and the longitude of the sample of the row 0 and the column 0
+	north float64
+	west  float64
+	// cellSize the distance between two samples in degrees
+	cellSize float64
+	rows     int
+	columns  int
+	samples  []float64
+	// noData the value of the voids
+	noData float64
+}
+
+func (t *tile) contains(latitude float64, longitude float64) bool {
+	row := snapToSample((t.north - latitude) / t.cellSize)
+	column := snapToSample((longitude - t.west) / t.cellSize)
+	return row >= 0 && row <= float64(t.rows-1) && column >= 0 && column <= float64(t.columns-1)
+}
+
+/*
+elevation returns the elevation bilinearly interpolated between the 4 samples around the location, the voids are
+skipped.
+*/
+func (t *tile) elevation(latitude float64, longitude float64) (elevation dimension.Meters, ok bool) {
+	row := snapToSample((t.north - latitude) / t.cellSize)
+	column := snapToSample((longitude - t.west) / t.cellSize)
+	row0 := int(math.Min(math.Floor(row), float64(t.rows-2)))
+	column0 := int(math.Min(math.Floor(column), float64(t.columns-2)))
+	fRow, fColumn := row-float64(row0), column-float64(column0)
+
+	var sum, weights float64
+	for _, corner := range []struct {
+		row, column int
+		weight      float64
+	}{
+		{row0, column0, (1 - fRow) * (1 - fColumn)},
+		{row0, column0 + 1, (1 - fRow) * fColumn},
+		{row0 + 1, column0, fRow * (1 - fColumn)},
+		{row0 + 1, column0 + 1, fRow * fColumn},
+	} {
+		sample := t.samples[corner.row*t.columns+corner.column]
+		if sample == t.noData || corner.weight == 0 {
+			continue
+		}
+		sum += sample * corner.weight
+		weights += corner.weight
+	}
+
+	if weights == 0 {
+		return 0, false
+	}
+	return dimension.Meters(sum / weights), true
+}
+
+/*
+snapToSample returns the row or the column passed in rounded to the sample it is on within the floating point error,
+so that a location on a void sample is not interpolated from its neighbours.
+*/
+func snapToSample(index float64) float64 {
+	if rounded := math.Round(index); math.Abs(index-rounded) < 1e-9 {
+		return rounded
+	}
+	return index
+}
+
+/*
+directory the Source of the tiles of a directory, loaded on demand and kept in memory.
+*/
+type directory struct {
+	path string
+	// mutex guards the hgt and the asc tiles
+	mutex sync.Mutex
+	// hgt the loaded .hgt tiles by name, nil if the tile is missing, the tiles that can't be read are not kept
+	hgt map[string]*tile
+	// asc the .asc tiles, loaded on the first use that reads them all
+	asc []*tile
+}
+
+/*
+OpenDirectory returns the Source of the tiles of the directory: the SRTM .hgt tiles, named after their southwest
+corner such as N31E035.hgt, of 1201 x 1201 (3 arc seconds) or 3601 x 3601 (1 arc second) samples, and the ESRI ASCII
+grid .asc tiles of any extent. The .hgt tiles are preferred where the tiles overlap.
+The Source is safe for concurrent use.
+*/
+func OpenDirectory(path string) (Source, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", path)
+	}
+	return &directory{path: path, hgt: map[string]*tile{}}, nil
+}
+
+func (t *directory) Elevation(latitude float64, longitude float64) (elevation dimension.Meters, ok bool, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	hgt, err := t.hgtTile(latitude, longitude)
+	if err != nil {
+		return 0, false, err
+	}
+	if hgt != nil && hgt.contains(latitude, longitude) {
+		if elevation, ok = hgt.elevation(latitude, longitude); ok {
+			return elevation, true, nil
+		}
+	}
+
+	ascTiles, err := t.ascTiles()
+	if err != nil {
+		return 0, false, err
+	}
+	for _, asc := range ascTiles {
+		if asc.contains(latitude, longitude) {
+			if elevation, ok = asc.elevation(latitude, longitude); ok {
+				return elevation, true, nil
+			}
+		}
+	}
+
+	return 0, false, nil
+}
+
+/*
+hgtTile returns the .hgt tile of the location, nil if there is no such file.
+*/
+func (t *directory) hgtTile(latitude float64, longitude float64) (*tile, error) {
+	name := hgtName(latitude, longitude)
+	if hgt, loaded := t.hgt[name]; loaded {
+		return hgt, nil
+	}
+
+	hgt, err := readHgtFile(filepath.Join(t.path, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		hgt = nil
+	} else if err != nil {
+		return nil, err
+	}
+	t.hgt[name] = hgt
+	return hgt, nil
+}
+
+func (t *directory) ascTiles() ([]*tile, error) {
+	if t.asc != nil {
+		return t.asc, nil
+	}
+
+	entries, err := os.ReadDir(t.path)
+	if err != nil {
+		return nil, err
+	}
+	asc := []*tile{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".asc") {
+			continue
+		}
+		grid, err := readAsciiGridFile(filepath.Join(t.path, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		asc = append(asc, grid)
+	}
+	t.asc = asc
+	return t.asc, nil
+}
+
+/*
+PopulateElevation sets the elevation of the geoLocation from the source, ok is false if the source has no elevation
+for it or it is below sea level, as calculator.GeoLocation doesn't allow a negative elevation. The elevation of the
+geoLocation is not changed if ok is false, the elevation below sea level is returned by the Elevation of the source.
+err is the error of the Elevation of the source.
+*/
+func PopulateElevation(source Source, geoLocation calculator.GeoLocation) (ok bool, err error) {
+	elevation, ok, err := source.Elevation(geoLocation.Latitude(), geoLocation.Longitude())
+	if err != nil || !ok || elevation < 0 {
+		return false, err
+	}
+	geoLocation.SetElevation(elevation)
+	return true, nil
+}
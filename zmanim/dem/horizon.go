@@ -0,0 +1,67 @@
+package dem
+
+import (
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+)
+
+/*
+TerrestrialRefractionCoefficient the coefficient of the refraction of the line of sight near the ground, the ratio of
+the radius of the earth to the radius of the curvature of the ray, which lets the observer see farther than the
+geometric horizon.
+*/
+const TerrestrialRefractionCoefficient = 0.13
+
+/*
+meanEarthRadius the mean radius of the earth.
+*/
+const meanEarthRadius = dimension.Meters(6371008.8)
+
+/*
+NewHorizonProfile derives the calculator.HorizonProfile of the geoLocation from the source by ray-marching it every
+azimuthStep (such as 1 deg) up to the maxDistance (such as 50 dimension.KM). The observer is at the elevation of the
+geoLocation (see PopulateElevation), the altitude of the terrain is lowered for the curvature of the earth and the
+TerrestrialRefractionCoefficient, and the horizon is not lower than the dip of the sea-level horizon for the elevation.
+The points of the rays are the calculator.VincentyDirect destinations. It returns the first error of the Elevation of
+the source or of the calculator.VincentyDirect.
+A panic will be if the azimuthStep is not between 0 and 90 deg or the maxDistance is not positive.
+*/
+func NewHorizonProfile(source Source, geoLocation calculator.GeoLocation, azimuthStep dimension.Degrees, maxDistance dimension.KM) (calculator.HorizonProfile, error) {
+	if azimuthStep <= 0 || azimuthStep > 90 {
+		panic("azimuthStep is not between 0 and 90")
+	}
+	if maxDistance <= 0 {
+		panic("maxDistance <= 0")
+	}
+
+	// the curvature of the ray is accounted for by an earth of a larger radius
+	radius := float64(meanEarthRadius) / (1 - TerrestrialRefractionCoefficient)
+	observer := float64(geoLocation.Elevation())
+	dip := -dimension.ACos(radius / (radius + observer))
+
+	var points []calculator.HorizonPoint
+	for azimuth := dimension.Degrees(0); azimuth < 360; azimuth += azimuthStep {
+		horizon := dip
+		for distance := 30.0; distance <= float64(maxDistance.ToMeters()); distance += math.Max(30, distance/100) {
+			latitude, longitude, _, err := calculator.VincentyDirect(geoLocation.Latitude(), geoLocation.Longitude(), float64(azimuth), distance)
+			if err != nil {
+				return nil, err
+			}
+			elevation, ok, err := source.Elevation(latitude, longitude)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			drop := distance * distance / (2 * radius)
+			if altitude := dimension.Radians(math.Atan2(float64(elevation)-observer-drop, distance)).ToDegrees(); altitude > horizon {
+				horizon = altitude
+			}
+		}
+		points = append(points, calculator.HorizonPoint{Azimuth: azimuth, Altitude: horizon})
+	}
+
+	return calculator.NewHorizonProfile(points), nil
+}
@@ -0,0 +1,165 @@
+package dem
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+/*
+hgtNoData the value of the voids of the SRTM .hgt tiles.
+*/
+const hgtNoData = -32768
+
+/*
+hgtName returns the name of the SRTM .hgt tile of the location, after its southwest corner such as N31E035.hgt.
+*/
+func hgtName(latitude float64, longitude float64) string {
+	south, west := int(math.Floor(latitude)), int(math.Floor(longitude))
+
+	ns, ew := 'N', 'E'
+	if south < 0 {
+		ns, south = 'S', -south
+	}
+	if west < 0 {
+		ew, west = 'W', -west
+	}
+	return fmt.Sprintf("%c%02d%c%03d.hgt", ns, south, ew, west)
+}
+
+/*
+parseHgtName returns the southwest corner of the SRTM .hgt tile of the name passed in.
+*/
+func parseHgtName(name string) (south int, west int, err error) {
+	var ns, ew rune
+	if _, err = fmt.Sscanf(strings.ToUpper(name), "%c%02d%c%03d.HGT", &ns, &south, &ew, &west); err != nil {
+		return 0, 0, fmt.Errorf("%s is not a .hgt tile name: %v", name, err)
+	}
+	switch {
+	case ns == 'S':
+		south = -south
+	case ns != 'N':
+		return 0, 0, fmt.Errorf("%s is not a .hgt tile name", name)
+	}
+	switch {
+	case ew == 'W':
+		west = -west
+	case ew != 'E':
+		return 0, 0, fmt.Errorf("%s is not a .hgt tile name", name)
+	}
+	return south, west, nil
+}
+
+/*
+readHgtFile reads the SRTM .hgt tile, the square grid of the big-endian signed 16-bit elevations in meters of the
+1 x 1 deg tile, the row 0 is the northern edge.
+*/
+func readHgtFile(path string) (*tile, error) {
+	south, west, err := parseHgtName(filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	size := int(math.Round(math.Sqrt(float64(len(data) / 2))))
+	if size < 2 || size*size*2 != len(data) {
+		return nil, fmt.Errorf("%s is not a square grid of 16-bit samples", path)
+	}
+
+	samples := make([]float64, size*size)
+	for i := range samples {
+		samples[i] = float64(int16(binary.BigEndian.Uint16(data[2*i:])))
+	}
+
+	return &tile{
+		north:    float64(south + 1),
+		west:     float64(west),
+		cellSize: 1 / float64(size-1),
+		rows:     size,
+		columns:  size,
+		samples:  samples,
+		noData:   hgtNoData,
+	}, nil
+}
+
+/*
+readAsciiGridFile reads the ESRI ASCII grid tile: the header of ncols, nrows, xllcorner or xllcenter, yllcorner or
+yllcenter, cellsize and the optional NODATA_value (-9999 by default), followed by the rows of the elevations in meters
+from the north.
+*/
+func readAsciiGridFile(path string) (*tile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	scanner.Split(bufio.ScanWords)
+
+	header := map[string]float64{"nodata_value": -9999}
+	var samples []float64
+	for scanner.Scan() {
+		word := scanner.Text()
+		value, err := strconv.ParseFloat(word, 64)
+		if err == nil {
+			samples = append(samples, value)
+			continue
+		}
+		if samples != nil || !scanner.Scan() {
+			return nil, fmt.Errorf("%s: unexpected %q", path, word)
+		}
+		if header[strings.ToLower(word)], err = strconv.ParseFloat(scanner.Text(), 64); err != nil {
+			return nil, fmt.Errorf("%s: %s is not a number", path, word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range []string{"ncols", "nrows", "cellsize"} {
+		if header[key] <= 0 {
+			return nil, fmt.Errorf("%s: %s is missing", path, key)
+		}
+	}
+	for _, keys := range [][2]string{{"xllcorner", "xllcenter"}, {"yllcorner", "yllcenter"}} {
+		_, corner := header[keys[0]]
+		_, center := header[keys[1]]
+		if !corner && !center {
+			return nil, fmt.Errorf("%s: %s is missing", path, keys[0])
+		}
+	}
+	columns, rows, cellSize := int(header["ncols"]), int(header["nrows"]), header["cellsize"]
+	if columns < 2 || rows < 2 || len(samples) != rows*columns {
+		return nil, fmt.Errorf("%s: %d samples for %d x %d", path, len(samples), rows, columns)
+	}
+
+	// the samples are at the centers of the cells
+	west, westOk := header["xllcenter"]
+	if _, ok := header["xllcorner"]; ok && !westOk {
+		west = header["xllcorner"] + cellSize/2
+	}
+	south, southOk := header["yllcenter"]
+	if _, ok := header["yllcorner"]; ok && !southOk {
+		south = header["yllcorner"] + cellSize/2
+	}
+
+	return &tile{
+		north:    south + float64(rows-1)*cellSize,
+		west:     west,
+		cellSize: cellSize,
+		rows:     rows,
+		columns:  columns,
+		samples:  samples,
+		noData:   header["nodata_value"],
+	}, nil
+}
@@ -81,7 +81,15 @@ func (am ArcMinutes) ToDegrees() Degrees {
 }
 
 /*
-ArcSeconds minutes of a degree
+InDegrees converts the minutes of arc to Degrees, 60 minutes to a degree. Unlike ToDegrees, which returns the value as
+it is for the values already divided by 60.
+*/
+func (am ArcMinutes) InDegrees() Degrees {
+	return Degrees(am / 60)
+}
+
+/*
+ArcSeconds seconds of a degree
 seconds [seconds of arc]: https://en.wikipedia.org/wiki/Minute_of_arc#Cartography
 */
 type ArcSeconds float64
@@ -89,3 +97,21 @@ type ArcSeconds float64
 func (am ArcSeconds) ToDegrees() Degrees {
 	return Degrees(am)
 }
+
+/*
+InDegrees converts the seconds of arc to Degrees, 3600 seconds to a degree. Unlike ToDegrees, which returns the value
+as it is for the values already divided by 3600.
+*/
+func (am ArcSeconds) InDegrees() Degrees {
+	return Degrees(am / 3600)
+}
+
+/*
+Millibars atmospheric pressure in millibars (hectopascals)
+*/
+type Millibars float64
+
+/*
+Celsius temperature in degrees Celsius
+*/
+type Celsius float64
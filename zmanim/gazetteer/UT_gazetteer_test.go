@@ -0,0 +1,237 @@
+package gazetteer
+
+import (
+	"bytes"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultGazetteerCities(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	for _, city := range DefaultGazetteer().Cities() {
+		_, err := time.LoadLocation(city.TimeZone)
+		assert.Equal(t, tag+" "+city.Name, nil, err)
+		assert.Equal(t, tag+" "+city.Name, 2, len(city.Country))
+	}
+
+	curated, err := ReadGazetteer(bytes.NewReader(citiesCsv))
+	assert.Equal(t, tag, nil, err)
+	cities := curated.Cities()
+	assert.True(t, tag, len(cities) > 500)
+
+	names := map[string]string{}
+	for _, city := range cities {
+		assert.True(t, tag+" "+city.Name, city.HebrewName != "")
+
+		// every name finds its own city
+		for _, name := range append([]string{city.Name, city.HebrewName}, city.Aliases...) {
+			normalized := normalizeName(name)
+			if other, ok := names[normalized]; ok && other != city.Name {
+				t.Errorf("%s: the name %q is of both %s and %s", tag, name, other, city.Name)
+			}
+			names[normalized] = city.Name
+		}
+	}
+}
+
+func TestDefaultGazetteerCandleLightingOffsets(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the candle lighting offsets recorded in cities.csv, as listed in the package doc
+	offsets := map[string]gdt.GMinuteF64{}
+	for _, city := range DefaultGazetteer().Cities() {
+		if city.CandleLightingOffset != 0 {
+			offsets[city.Name] = city.CandleLightingOffset
+		}
+	}
+	assert.Equal(t, tag, map[string]gdt.GMinuteF64{"Jerusalem": 40, "Haifa": 30, "Petah Tikva": 22, "Zikhron Ya'akov": 30}, offsets)
+}
+
+func TestLookupCuratedFirst(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the generated cities follow the curated ones, as in DefaultGazetteer
+	g, err := ReadGazetteer(io.MultiReader(
+		strings.NewReader("Alpha,אלפא,IL,31.5,35,100,Asia/Jerusalem,40,\n"),
+		strings.NewReader("# generated\nAlpha,,IL,31.6,35.1,0,Asia/Jerusalem,,\n"),
+	))
+	assert.Equal(t, tag, nil, err)
+	city, ok := g.Lookup("alpha")
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, gdt.GMinuteF64(40), city.CandleLightingOffset)
+	assert.Equal(t, tag, 2, len(g.Cities()))
+}
+
+func TestLookup(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	g := DefaultGazetteer()
+	for _, test := range []struct {
+		name, want string
+	}{
+		{"Jerusalem", "Jerusalem"},
+		{"jerusalem", "Jerusalem"},
+		{"ירושלים", "Jerusalem"},
+		{"Yerushalayim", "Jerusalem"},
+		{"בני־ברק", "Bnei Brak"},
+		{"Petach Tikva", "Petah Tikva"},
+		{"Sao Paulo", "Sao Paulo"},
+		{"São Paulo", "Sao Paulo"},
+		{"Kraków", "Krakow"},
+		{"Łódź", "Lodz"},
+		{"Boro Park", "Borough Park"},
+		{"Zichron Yaakov", "Zikhron Ya'akov"},
+	} {
+		city, ok := g.Lookup(test.name)
+		assert.True(t, tag+" "+test.name, ok)
+		assert.Equal(t, tag+" "+test.name, test.want, city.Name)
+	}
+
+	_, ok := g.Lookup("Atlantis")
+	assert.False(t, tag, ok)
+	_, ok = g.Lookup("")
+	assert.False(t, tag, ok)
+}
+
+func TestSearch(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	g := DefaultGazetteer()
+	for _, test := range []struct {
+		query, want string
+	}{
+		{"Jerusalm", "Jerusalem"},
+		{"Yerushalaim", "Jerusalem"},
+		{"Lakwood", "Lakewood"},
+		{"Monsy", "Monsey"},
+		{"Antwerpen", "Antwerp"},
+		{"Melborne", "Melbourne"},
+		{"Petah", "Petah Tikva"},
+		{"ירושלם", "Jerusalem"},
+		{"פֶּתַח תִּקְוָה", "Petah Tikva"},
+		{"Kiryas Yoel", "Kiryas Joel"},
+	} {
+		matches := g.Search(test.query, 3)
+		assert.True(t, tag+" "+test.query, len(matches) > 0)
+		assert.Equal(t, tag+" "+test.query, test.want, matches[0].Name)
+	}
+
+	matches := g.Search("Jerusalem", 0)
+	assert.Equal(t, tag, 0, matches[0].Score)
+	assert.Equal(t, tag, 0, len(g.Search("Xyzzyplugh", 0)))
+	assert.Equal(t, tag, 0, len(g.Search("", 0)))
+
+	// the prefix of many names
+	assert.Equal(t, tag, 5, len(g.Search("Kiryat", 5)))
+}
+
+func TestNearest(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	g := DefaultGazetteer()
+
+	// the Kosel
+	neighbors := g.Nearest(31.7767, 35.2345, 3)
+	assert.Equal(t, tag, 3, len(neighbors))
+	assert.Equal(t, tag, "Jerusalem", neighbors[0].Name)
+	assert.True(t, tag, neighbors[0].Distance < 1000)
+	assert.True(t, tag, neighbors[0].Distance <= neighbors[1].Distance && neighbors[1].Distance <= neighbors[2].Distance)
+
+	// Lakewood, NJ
+	assert.Equal(t, tag, "Lakewood", g.Nearest(40.0960, -74.2176, 1)[0].Name)
+
+	// across the 180 deg meridian, Suva at 178.45 E is nearer than Apia at 171.77 W to a point at 179.9 W
+	assert.Equal(t, tag, "Suva", g.Nearest(-17, -179.9, 1)[0].Name)
+
+	// Jerusalem to Tel Aviv is about 54 km
+	tlv, _ := g.Lookup("Tel Aviv")
+	distance := g.Nearest(tlv.Latitude, tlv.Longitude, len(g.Cities()))
+	for _, neighbor := range distance {
+		if neighbor.Name == "Jerusalem" {
+			assert.True(t, tag, neighbor.Distance > 50000 && neighbor.Distance < 60000)
+		}
+	}
+
+	defer assert.Raises(t, tag)()
+	g.Nearest(0, 0, 0)
+}
+
+func TestCityGeoLocation(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	city, _ := DefaultGazetteer().Lookup("Jerusalem")
+	geoLocation := city.GeoLocation()
+	assert.Equal(t, tag, "Jerusalem", geoLocation.LocationName())
+	assert.Equal(t, tag, city.Latitude, geoLocation.Latitude())
+	assert.Equal(t, tag, city.Longitude, geoLocation.Longitude())
+	assert.Equal(t, tag, city.Elevation, geoLocation.Elevation())
+	assert.Equal(t, tag, "Asia/Jerusalem", geoLocation.TimeZone().String())
+}
+
+func TestCityCandleLighting(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	g := DefaultGazetteer()
+	gDate := gdt.NewGDate(2024, 3, 15)
+	for _, test := range []struct {
+		name   string
+		offset gdt.GMinuteF64
+	}{
+		{"Jerusalem", 40},
+		{"Haifa", 30},
+		{"Petah Tikva", 22},
+		{"Lakewood", 18},
+	} {
+		city, _ := g.Lookup(test.name)
+		assert.Equal(t, tag+" "+test.name, test.offset, city.CandleLightingOffsetOrDefault())
+
+		zc := city.ZmanimCalendar(gDate, calculator.NewNOAACalculator())
+		assert.Equal(t, tag+" "+test.name, test.offset, zc.CandleLightingOffset())
+		sunset, _ := zc.SeaLevelSunset()
+		candleLighting, _ := zc.CandleLighting()
+		assert.Equal(t, tag+" "+test.name, time.Duration(test.offset)*time.Minute, sunset.Sub(candleLighting).Round(time.Second))
+	}
+}
+
+func TestReadGazetteer(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	g, err := ReadGazetteer(strings.NewReader("# a comment\nAlpha,אלפא,IL,31.5,35,100,Asia/Jerusalem,25,A1|A2\nBeta,,US,40,-74,0,America/New_York,,\n"))
+	assert.Equal(t, tag, nil, err)
+	assert.Equal(t, tag, 2, len(g.Cities()))
+	alpha, ok := g.Lookup("A2")
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, gdt.GMinuteF64(25), alpha.CandleLightingOffset)
+	assert.Equal(t, tag, []string{"A1", "A2"}, alpha.Aliases)
+	beta, _ := g.Lookup("beta")
+	assert.Equal(t, tag, gdt.GMinuteF64(0), beta.CandleLightingOffset)
+
+	for _, csv := range []string{
+		"",
+		"Alpha,,IL,91,35,0,Asia/Jerusalem,,\n",
+		"Alpha,,IL,31,east,0,Asia/Jerusalem,,\n",
+		"Alpha,,IL,31,35,0,,,\n",
+		"Alpha,,IL,31,35,0,Asia/Jerusalem,-5,\n",
+		"Alpha,,IL,31,35\n",
+	} {
+		_, err := ReadGazetteer(strings.NewReader(csv))
+		assert.True(t, tag+" "+csv, err != nil)
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	assert.Equal(t, tag, "zikhron yaakov", normalizeName("Zikhron Ya'akov"))
+	assert.Equal(t, tag, "st louis", normalizeName("St. Louis"))
+	assert.Equal(t, tag, "tel aviv yafo", normalizeName("  Tel Aviv-Yafo "))
+	assert.Equal(t, tag, "כפר חבד", normalizeName(`כפר חב"ד`))
+	assert.Equal(t, tag, "ירושלימ", normalizeName("יְרוּשָׁלַיִם"))
+}
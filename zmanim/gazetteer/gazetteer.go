@@ -0,0 +1,401 @@
+/*
+Package gazetteer is an embedded, offline gazetteer of about 600 curated cities and neighborhoods with Jewish
+communities. It looks up a City by its English or Hebrew name, also fuzzily to forgive typos and transliterations, or by
+the nearest coordinates, and returns it as a ready calculator.GeoLocation. The candle lighting offset of the local custom
+is recorded only for Jerusalem (40 minutes), Haifa (30), Petah Tikva (22) and Zikhron Ya'akov (30), the other cities
+have the default of zmanim.ZmanimCalendar (18 minutes). The places of GeoNames are added by gen_cities.go (see
+DefaultGazetteer).
+*/
+package gazetteer
+
+//go:generate go run gen_cities.go
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/*
+citiesCsv the embedded curated cities in the format of ReadGazetteer.
+*/
+//go:embed cities.csv
+var citiesCsv []byte
+
+/*
+geonamesCsv the embedded cities of GeoNames in the format of ReadGazetteer generated by gen_cities.go, only the header
+in the repository.
+*/
+//go:embed geonames.csv
+var geonamesCsv []byte
+
+const (
+	// meanEarthRadius the mean radius of the earth in meters of the haversine distance of Nearest
+	meanEarthRadius = 6371008.8
+	// defaultCandleLightingOffset the candle lighting offset of zmanim.ZmanimCalendar in minutes
+	defaultCandleLightingOffset gdt.GMinuteF64 = 18
+)
+
+/*
+City a city or a neighborhood of the Gazetteer.
+*/
+type City struct {
+	// Name the English name
+	Name string
+	// HebrewName the Hebrew name
+	HebrewName string
+	// Aliases the other names and transliterations, such as Yerushalayim, or the neighborhoods included in the city
+	Aliases []string
+	// Country the ISO 3166-1 alpha-2 code of the country, such as IL or US
+	Country   string
+	Latitude  float64
+	Longitude float64
+	Elevation dimension.Meters
+	// TimeZone the IANA time zone name, such as Asia/Jerusalem
+	TimeZone string
+	// CandleLightingOffset the minutes before sunset of the candle lighting of the local custom, such as 40 in Jerusalem,
+	// 0 if it is not known and the default of zmanim.ZmanimCalendar (18 minutes) applies
+	CandleLightingOffset gdt.GMinuteF64
+}
+
+/*
+GeoLocation returns the City as a calculator.GeoLocation.
+A panic will be if the TimeZone can't be loaded, on the systems without the IANA time zone database import time/tzdata.
+*/
+func (c City) GeoLocation() calculator.GeoLocation {
+	return calculator.NewGeoLocation2(c.Name, c.Latitude, c.Longitude, c.Elevation, timeutil.LoadLocationOrPanic(c.TimeZone))
+}
+
+/*
+ZmanimCalendar returns the zmanim.ZmanimCalendar of the City on the gDate with its CandleLightingOffset, if it is known.
+*/
+func (c City) ZmanimCalendar(gDate gdt.GDate, astronomicalCalculator calculator.AstronomicalCalculator) zmanim.ZmanimCalendar {
+	zc := zmanim.NewZmanimCalendar(gdt.NewGDateTime(gDate, gdt.NewGTime0()), c.GeoLocation(), astronomicalCalculator)
+	if c.CandleLightingOffset > 0 {
+		zc.SetCandleLightingOffset(c.CandleLightingOffset)
+	}
+	return zc
+}
+
+/*
+CandleLightingOffsetOrDefault returns the CandleLightingOffset, or the 18 minutes of zmanim.ZmanimCalendar if it is not
+known.
+*/
+func (c City) CandleLightingOffsetOrDefault() gdt.GMinuteF64 {
+	if c.CandleLightingOffset > 0 {
+		return c.CandleLightingOffset
+	}
+	return defaultCandleLightingOffset
+}
+
+/*
+Match a City found by Gazetteer.Search. The Score is the edit distance of the query to the nearest name of the City, 0
+for the exact match and 1 more for the match of the beginning of a name only.
+*/
+type Match struct {
+	City
+	Score int
+}
+
+/*
+Neighbor a City found by Gazetteer.Nearest at the Distance in meters.
+*/
+type Neighbor struct {
+	City
+	Distance float64
+}
+
+/*
+Gazetteer the cities to look up by name or by coordinates. The names are compared case-, diacritics- and
+punctuation-insensitively, and the Hebrew ones niqqud- and final letters-insensitively.
+*/
+type Gazetteer interface {
+	// Cities returns all the cities in the order they are read
+	Cities() []City
+	// Lookup returns the first City whose name, Hebrew name or alias is the name, ok is false if there is none
+	Lookup(name string) (city City, ok bool)
+	// Search returns up to limit (all if limit <= 0) cities whose names are near the query, see Match, the best first
+	Search(query string, limit int) []Match
+	// Nearest returns the n cities nearest to the latitude and the longitude, the nearest first.
+	// A panic will be if n < 1.
+	Nearest(latitude float64, longitude float64, n int) []Neighbor
+}
+
+type gazetteer struct {
+	cities []City
+	// names the normalized names of the cities by the index of the city
+	names [][]string
+}
+
+func newGazetteer() *gazetteer {
+	return &gazetteer{}
+}
+
+func (t *gazetteer) initGazetteer(cities []City) {
+	t.cities = append([]City(nil), cities...)
+	t.names = make([][]string, len(cities))
+	for i, city := range cities {
+		for _, name := range append([]string{city.Name, city.HebrewName}, city.Aliases...) {
+			if normalized := normalizeName(name); normalized != "" {
+				t.names[i] = append(t.names[i], normalized)
+			}
+		}
+	}
+}
+
+func NewGazetteer(cities []City) Gazetteer {
+	t := newGazetteer()
+
+	t.initGazetteer(cities)
+
+	return t
+}
+
+var (
+	defaultGazetteer     Gazetteer
+	defaultGazetteerOnce sync.Once
+)
+
+/*
+DefaultGazetteer returns the embedded Gazetteer of the curated cities and neighborhoods with Jewish communities in
+Israel and the diaspora of cities.csv, followed by the cities of GeoNames of geonames.csv. The repository ships
+geonames.csv with the header only, so that the DefaultGazetteer has the curated cities alone until geonames.csv is
+generated before the build with go generate ./zmanim/gazetteer, which runs gen_cities.go: it downloads the cities1000
+of GeoNames and takes the places of Israel and the cities of at least 100000 inhabitants of the countries of cities.csv,
+without a candle lighting offset. Lookup prefers the curated cities.
+*/
+func DefaultGazetteer() Gazetteer {
+	defaultGazetteerOnce.Do(func() {
+		t, err := ReadGazetteer(io.MultiReader(bytes.NewReader(citiesCsv), bytes.NewReader(geonamesCsv)))
+		if err != nil {
+			panic(err.Error())
+		}
+		defaultGazetteer = t
+	})
+	return defaultGazetteer
+}
+
+/*
+ReadGazetteer reads a Gazetteer in the CSV format of the columns name, hebrew_name, country, latitude, longitude,
+elevation, time_zone, candle_lighting_offset and aliases (separated by |). The candle_lighting_offset and the aliases
+may be empty. The lines starting with # are comments.
+*/
+func ReadGazetteer(r io.Reader) (Gazetteer, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 9
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var cities []City
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		city, err := parseCity(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		cities = append(cities, city)
+	}
+	if len(cities) == 0 {
+		return nil, fmt.Errorf("the gazetteer has no cities")
+	}
+
+	return NewGazetteer(cities), nil
+}
+
+/*
+LoadGazetteer loads a Gazetteer from the CSV file, see ReadGazetteer.
+*/
+func LoadGazetteer(path string) (Gazetteer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadGazetteer(file)
+}
+
+func parseCity(record []string) (City, error) {
+	city := City{Name: record[0], HebrewName: record[1], Country: record[2], TimeZone: record[6]}
+	if city.Name == "" {
+		return City{}, fmt.Errorf("the name is empty")
+	}
+
+	var err error
+	if city.Latitude, err = strconv.ParseFloat(record[3], 64); err != nil || city.Latitude < -90 || city.Latitude > 90 {
+		return City{}, fmt.Errorf("%q is not a latitude", record[3])
+	}
+	if city.Longitude, err = strconv.ParseFloat(record[4], 64); err != nil || city.Longitude < -180 || city.Longitude > 180 {
+		return City{}, fmt.Errorf("%q is not a longitude", record[4])
+	}
+	elevation, err := strconv.ParseFloat(record[5], 64)
+	if err != nil {
+		return City{}, fmt.Errorf("%q is not an elevation", record[5])
+	}
+	city.Elevation = dimension.Meters(elevation)
+	if city.TimeZone == "" {
+		return City{}, fmt.Errorf("the time zone is empty")
+	}
+	if record[7] != "" {
+		offset, err := strconv.ParseFloat(record[7], 64)
+		if err != nil || offset < 0 {
+			return City{}, fmt.Errorf("%q is not a candle lighting offset", record[7])
+		}
+		city.CandleLightingOffset = gdt.GMinuteF64(offset)
+	}
+	for _, alias := range strings.Split(record[8], "|") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			city.Aliases = append(city.Aliases, alias)
+		}
+	}
+
+	return city, nil
+}
+
+func (t *gazetteer) Cities() []City {
+	return append([]City(nil), t.cities...)
+}
+
+func (t *gazetteer) Lookup(name string) (city City, ok bool) {
+	normalized := normalizeName(name)
+	if normalized == "" {
+		return City{}, false
+	}
+	for i, names := range t.names {
+		for _, n := range names {
+			if n == normalized {
+				return t.cities[i], true
+			}
+		}
+	}
+	return City{}, false
+}
+
+/*
+Search accepts the matches whose Score is at most 1 more than a quarter of the length of the query, so that the longer
+the query, the more typos are forgiven.
+*/
+func (t *gazetteer) Search(query string, limit int) []Match {
+	q := []rune(normalizeName(query))
+	if len(q) == 0 {
+		return nil
+	}
+	maxScore := 1 + len(q)/4
+
+	var matches []Match
+	for i, names := range t.names {
+		best := math.MaxInt
+		for _, n := range names {
+			if score := matchScore(q, []rune(n)); score < best {
+				best = score
+			}
+		}
+		if best <= maxScore {
+			matches = append(matches, Match{City: t.cities[i], Score: best})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score < matches[j].Score
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+func (t *gazetteer) Nearest(latitude float64, longitude float64, n int) []Neighbor {
+	if n < 1 {
+		panic(fmt.Sprintf("n = %d < 1", n))
+	}
+
+	neighbors := make([]Neighbor, len(t.cities))
+	for i, city := range t.cities {
+		neighbors[i] = Neighbor{City: city, Distance: haversineDistance(latitude, longitude, city.Latitude, city.Longitude)}
+	}
+	sort.SliceStable(neighbors, func(i, j int) bool {
+		return neighbors[i].Distance < neighbors[j].Distance
+	})
+	if len(neighbors) > n {
+		neighbors = neighbors[:n]
+	}
+	return neighbors
+}
+
+/*
+matchScore returns the edit distance of the query to the name, or to the beginning of the name of the length of the
+query plus 1, whichever is less.
+*/
+func matchScore(query []rune, name []rune) int {
+	score := levenshteinDistance(query, name)
+	if len(name) > len(query) {
+		if prefixScore := levenshteinDistance(query, name[:len(query)]) + 1; prefixScore < score {
+			score = prefixScore
+		}
+	}
+	return score
+}
+
+func levenshteinDistance(a []rune, b []rune) int {
+	previous, current := make([]int, len(b)+1), make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			substitution := previous[j-1]
+			if a[i-1] != b[j-1] {
+				substitution++
+			}
+			current[j] = minInt(substitution, previous[j]+1, current[j-1]+1)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+/*
+haversineDistance returns the great-circle distance in meters on the sphere of the mean radius of the earth, accurate
+to about 0.5% which is enough to rank the cities.
+*/
+func haversineDistance(latitude1 float64, longitude1 float64, latitude2 float64, longitude2 float64) float64 {
+	phi1, phi2 := float64(dimension.Degrees(latitude1).ToRadians()), float64(dimension.Degrees(latitude2).ToRadians())
+	deltaPhi := phi2 - phi1
+	deltaLambda := float64(dimension.Degrees(longitude2 - longitude1).ToRadians())
+	a := math.Sin(deltaPhi/2)*math.Sin(deltaPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(deltaLambda/2)*math.Sin(deltaLambda/2)
+	return 2 * meanEarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
@@ -0,0 +1,295 @@
+//go:build ignore
+
+/*
+gen_cities generates geonames.csv, the embedded cities of DefaultGazetteer after the curated cities.csv, from the
+cities1000 of GeoNames: https://download.geonames.org/export/dump/ (CC BY 4.0). It takes the populated places of Israel
+and the cities of at least the population in the countries of cities.csv, without the ones within 5 km of a city of
+cities.csv. The Hebrew name is the first alternate name in the Hebrew script.
+
+	go generate ./zmanim/gazetteer
+	go run gen_cities.go -population 100000
+	go run gen_cities.go -geonames cities1000.zip
+*/
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// duplicateDistance the distance in meters within which a GeoNames city is taken for a city of cities.csv
+	duplicateDistance = 5000
+	meanEarthRadius   = 6371008.8
+)
+
+/*
+The columns of the GeoNames geoname table.
+*/
+const (
+	columnName = 1 + iota
+	columnAsciiName
+	columnAlternateNames
+	columnLatitude
+	columnLongitude
+	columnFeatureClass
+	columnFeatureCode
+	columnCountry
+	_
+	_
+	_
+	_
+	_
+	columnPopulation
+	columnElevation
+	columnDem
+	columnTimeZone
+	columnCount = columnTimeZone + 2
+)
+
+type city struct {
+	name, hebrewName, country, timeZone string
+	aliases                             []string
+	latitude, longitude, elevation      float64
+	population                          int
+}
+
+func main() {
+	geonames := flag.String("geonames", "", "the local cities1000.zip or cities1000.txt instead of the download")
+	population := flag.Int("population", 100000, "the minimal population of the cities outside Israel")
+	israelPopulation := flag.Int("israel-population", 0, "the minimal population of the places of Israel")
+	curatedPath := flag.String("cities", "cities.csv", "the curated cities")
+	output := flag.String("o", "geonames.csv", "the output file")
+	flag.Parse()
+
+	curated, err := readCurated(*curatedPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	countries := map[string]bool{}
+	for _, c := range curated {
+		countries[c.country] = true
+	}
+
+	data, err := readGeoNames(*geonames)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var cities []city
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < columnCount {
+			continue
+		}
+		c, err := parseGeoName(fields)
+		if err != nil {
+			log.Fatalf("%s: %v", fields[0], err)
+		}
+		if fields[columnFeatureClass] != "P" || !countries[c.country] {
+			continue
+		}
+		if c.country == "IL" && c.population < *israelPopulation || c.country != "IL" && c.population < *population {
+			continue
+		}
+		if nearAny(c, curated) {
+			continue
+		}
+		cities = append(cities, c)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	sort.Slice(cities, func(i, j int) bool {
+		if cities[i].country != cities[j].country {
+			return cities[i].country < cities[j].country
+		}
+		return cities[i].population > cities[j].population
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by gen_cities.go from GeoNames (https://www.geonames.org, CC BY 4.0), do not edit.\n")
+	buf.WriteString("# name,hebrew_name,country,latitude,longitude,elevation,time_zone,candle_lighting_offset,aliases\n")
+	w := csv.NewWriter(&buf)
+	for _, c := range cities {
+		if err := w.Write([]string{
+			c.name,
+			c.hebrewName,
+			c.country,
+			strconv.FormatFloat(c.latitude, 'f', 4, 64),
+			strconv.FormatFloat(c.longitude, 'f', 4, 64),
+			strconv.FormatFloat(c.elevation, 'f', 0, 64),
+			c.timeZone,
+			"",
+			strings.Join(c.aliases, "|"),
+		}); err != nil {
+			log.Fatal(err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%d cities", len(cities))
+}
+
+func parseGeoName(fields []string) (c city, err error) {
+	c = city{name: fields[columnName], country: fields[columnCountry], timeZone: fields[columnTimeZone]}
+	if c.latitude, err = strconv.ParseFloat(fields[columnLatitude], 64); err != nil {
+		return city{}, err
+	}
+	if c.longitude, err = strconv.ParseFloat(fields[columnLongitude], 64); err != nil {
+		return city{}, err
+	}
+	if fields[columnPopulation] != "" {
+		if c.population, err = strconv.Atoi(fields[columnPopulation]); err != nil {
+			return city{}, err
+		}
+	}
+	// the elevation, or the elevation of the digital elevation model, -9999 for the sea
+	for _, field := range []string{fields[columnElevation], fields[columnDem]} {
+		if elevation, err := strconv.ParseFloat(field, 64); err == nil && elevation != -9999 {
+			c.elevation = elevation
+			break
+		}
+	}
+	if c.timeZone == "" {
+		return city{}, fmt.Errorf("%s has no time zone", c.name)
+	}
+
+	if fields[columnAsciiName] != "" && fields[columnAsciiName] != c.name {
+		c.aliases = append(c.aliases, fields[columnAsciiName])
+	}
+	for _, name := range strings.Split(fields[columnAlternateNames], ",") {
+		if isHebrew(name) {
+			c.hebrewName = name
+			break
+		}
+	}
+	return c, nil
+}
+
+func isHebrew(name string) bool {
+	for _, r := range name {
+		if unicode.Is(unicode.Hebrew, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func readCurated(path string) ([]city, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 9
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	cities := make([]city, len(records))
+	for i, record := range records {
+		cities[i].country = record[2]
+		if cities[i].latitude, err = strconv.ParseFloat(record[3], 64); err != nil {
+			return nil, err
+		}
+		if cities[i].longitude, err = strconv.ParseFloat(record[4], 64); err != nil {
+			return nil, err
+		}
+	}
+	return cities, nil
+}
+
+/*
+readGeoNames returns the cities1000.txt of GeoNames, of the path if it is not empty.
+*/
+func readGeoNames(path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path != "" {
+		data, err = os.ReadFile(path)
+	} else {
+		data, err = download("https://download.geonames.org/export/dump/cities1000.zip")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte("PK")) {
+		return data, nil
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range archive.File {
+		if !strings.HasSuffix(file.Name, ".txt") {
+			continue
+		}
+		r, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	}
+	return nil, fmt.Errorf("no .txt in the zip")
+}
+
+func download(url string) ([]byte, error) {
+	response, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, response.Status)
+	}
+	return io.ReadAll(response.Body)
+}
+
+func nearAny(c city, cities []city) bool {
+	for _, other := range cities {
+		if other.country == c.country && distance(c, other) < duplicateDistance {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+distance returns the haversine distance in meters.
+*/
+func distance(a city, b city) float64 {
+	phi1, phi2 := a.latitude*math.Pi/180, b.latitude*math.Pi/180
+	dPhi, dLambda := phi2-phi1, (b.longitude-a.longitude)*math.Pi/180
+	h := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * meanEarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
@@ -0,0 +1,64 @@
+package gazetteer
+
+import (
+	"strings"
+	"unicode"
+)
+
+/*
+latinFoldings the letters with diacritics of the transliterated names folded to the plain Latin letters.
+*/
+var latinFoldings = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ą': "a", 'ă': "a",
+	'ç': "c", 'ć': "c", 'č': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ę': "e", 'ė': "e", 'ə': "e",
+	'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ı': "i",
+	'ł': "l",
+	'ñ': "n", 'ń': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o",
+	'ř': "r",
+	'ś': "s", 'š': "s", 'ș': "s", 'ş': "s",
+	'ț': "t", 'ţ': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+	'ß': "ss", 'æ': "ae", 'œ': "oe",
+}
+
+/*
+hebrewFinalLetters the final forms of the Hebrew letters folded to the regular ones.
+*/
+var hebrewFinalLetters = map[rune]rune{'ך': 'כ', 'ם': 'מ', 'ן': 'נ', 'ף': 'פ', 'ץ': 'צ'}
+
+/*
+normalizeName returns the name in lower case, with the Latin diacritics, the Hebrew niqqud and cantillation marks and
+final letters, and the apostrophes, the gereshim and the dots folded, and the other punctuation as single spaces.
+*/
+func normalizeName(name string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 0x0591 && r <= 0x05C7 && r != 0x05BE: // the niqqud and the cantillation marks, but the maqaf
+			continue
+		case r == '\'' || r == '"' || r == '׳' || r == '״' || r == '’' || r == '.':
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && sb.Len() > 0 {
+				sb.WriteByte(' ')
+			}
+			space = false
+			if folded, ok := latinFoldings[r]; ok {
+				sb.WriteString(folded)
+			} else if regular, ok := hebrewFinalLetters[r]; ok {
+				sb.WriteRune(regular)
+			} else {
+				sb.WriteRune(r)
+			}
+		default:
+			space = true
+		}
+	}
+	return sb.String()
+}
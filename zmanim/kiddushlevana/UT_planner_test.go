@@ -0,0 +1,134 @@
+package kiddushlevana
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/jdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"testing"
+	"time"
+)
+
+func jerusalemPlanner() Planner {
+	return NewPlanner(calculator.JerusalemGeoLocation(), calculator.NewNOAACalculator())
+}
+
+func TestNightsOfAv(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject := jerusalemPlanner()
+	av := hebrewcalendar.NewJewishDate1(jdt.NewJDate(5784, 5, 1))
+	start := hebrewcalendar.NewJewishCalendar(av).TchilasZmanKidushLevana3Days()
+	end := hebrewcalendar.NewJewishCalendar(av).SofZmanKidushLevanaBetweenMoldos()
+
+	nights := subject.Nights(av)
+	assert.Equal(t, tag, 13, len(nights))
+
+	// the tchilas zman is after midnight, the first night starts on the evening before
+	first := nights[0]
+	assert.Equal(t, tag, gdt.NewGDate(2024, 8, 7), first.GDate)
+	assert.Equal(t, tag, jdt.NewJDate(5784, 5, 4), first.JewishDate)
+	assert.Equal(t, tag, start, first.Start)
+
+	last := nights[len(nights)-1]
+	assert.Equal(t, tag, end, last.End)
+
+	for _, night := range nights {
+		assert.True(t, tag, night.Start.Before(night.End))
+		assert.Equal(t, tag, night.GDate == gdt.NewGDate(2024, 8, 10) || night.GDate == gdt.NewGDate(2024, 8, 17), night.MotzeiShabbos)
+		// Tisha B'Av 5784 was on Tuesday, August 13
+		assert.Equal(t, tag, night.GDate.Day < 13, night.BeforeTishaBav)
+		assert.Equal(t, tag, night.GDate.Day == 13, night.MotzeiTishaBav)
+		assert.False(t, tag, night.BeforeYomKippur)
+		assert.Equal(t, tag, night.GDate.Day >= 13 && !night.MoonBelowHorizon, night.IsValid())
+	}
+
+	// the moon of the 4th of Av sets before the tchilas zman
+	assert.True(t, tag, first.MoonBelowHorizon)
+}
+
+func TestNightsOfTishrei(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	nights := jerusalemPlanner().Nights(hebrewcalendar.NewJewishDate1(jdt.NewJDate(5784, 7, 1)))
+
+	for _, night := range nights {
+		// Yom Kippur 5784 was on Monday, September 25, 2023
+		assert.Equal(t, tag, night.GDate.Day < 25, night.BeforeYomKippur)
+		assert.Equal(t, tag, night.GDate.Day == 25, night.MotzeiYomKippur)
+	}
+}
+
+func TestStartAndEndOpinions(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject := jerusalemPlanner()
+	shevat := hebrewcalendar.NewJewishDate1(jdt.NewJDate(5784, 11, 1))
+	jewishCalendar := hebrewcalendar.NewJewishCalendar(shevat)
+
+	assert.Equal(t, tag, Start3Days, subject.Start())
+	assert.Equal(t, tag, EndBetweenMoldos, subject.End())
+
+	subject.SetStart(Start7Days)
+	subject.SetEnd(End15Days)
+
+	nights := subject.Nights(shevat)
+	assert.True(t, tag, !nights[0].Start.Before(jewishCalendar.TchilasZmanKidushLevana7Days()))
+	assert.True(t, tag, nights[0].Start.Before(jewishCalendar.TchilasZmanKidushLevana7Days().Add(24*time.Hour)))
+	assert.True(t, tag, !nights[len(nights)-1].End.After(jewishCalendar.SofZmanKidushLevana15Days()))
+
+	// limit the night to tzais 72 and alos 72
+	subject.SetTzais(zmanim.ZmanimCalendar.Tzais72)
+	subject.SetAlos(zmanim.ZmanimCalendar.Alos72)
+	for _, night := range subject.Nights(shevat)[1:] {
+		zc := zmanim.NewZmanimCalendar(gdt.NewGDateTime(night.GDate, gdt.NewGTime0()), calculator.JerusalemGeoLocation(), calculator.NewNOAACalculator())
+		tzais72, _ := zc.Tzais72()
+		assert.Equal(t, tag, tzais72, night.Start)
+	}
+}
+
+func TestNextValidNight(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject := jerusalemPlanner()
+
+	// during the window, the night of the instant
+	night, ok := subject.NextValidNight(time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC))
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, gdt.NewGDate(2024, 1, 20), night.GDate)
+	assert.True(t, tag, night.MotzeiShabbos)
+
+	// after the window, the first night of the next month
+	night, ok = subject.NextValidNight(time.Date(2024, 1, 30, 12, 0, 0, 0, time.UTC))
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, jdt.NewJDate(5784, 12, 5), night.JewishDate)
+
+	// in Av, the first night after Tisha B'Av
+	night, ok = subject.NextValidNight(time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC))
+	assert.True(t, tag, ok)
+	assert.True(t, tag, night.MotzeiTishaBav)
+}
+
+func TestPlannerSettersPanic(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	func() {
+		defer assert.Raises(t, tag)()
+		jerusalemPlanner().SetStart(Start(2))
+	}()
+	func() {
+		defer assert.Raises(t, tag)()
+		jerusalemPlanner().SetEnd(End(2))
+	}()
+	func() {
+		defer assert.Raises(t, tag)()
+		jerusalemPlanner().SetTzais(nil)
+	}()
+	func() {
+		defer assert.Raises(t, tag)()
+		jerusalemPlanner().SetAlos(nil)
+	}()
+}
@@ -0,0 +1,293 @@
+/*
+Package kiddushlevana plans the nights on which Kiddush Levana can be said in a month at a calculator.GeoLocation.
+Unlike the molad based zmanim of zmanim.ComplexZmanimCalendar, which only return a value on the exact day of the zman,
+the Planner lists every night of the window between the tchilas and the sof zman Kiddush Levana.
+*/
+package kiddushlevana
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/jdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/moon"
+	"time"
+)
+
+/*
+Start the opinion of the tchilas zman Kiddush Levana.
+*/
+type Start int
+
+const (
+	// Start3Days 3 days after the molad, see hebrewcalendar.JewishCalendar.TchilasZmanKidushLevana3Days
+	Start3Days Start = iota
+	// Start7Days 7 days after the molad, see hebrewcalendar.JewishCalendar.TchilasZmanKidushLevana7Days
+	Start7Days
+)
+
+/*
+End the opinion of the sof zman Kiddush Levana.
+*/
+type End int
+
+const (
+	// EndBetweenMoldos halfway between molad and molad, see hebrewcalendar.JewishCalendar.SofZmanKidushLevanaBetweenMoldos
+	EndBetweenMoldos End = iota
+	// End15Days 15 days after the molad, see hebrewcalendar.JewishCalendar.SofZmanKidushLevana15Days
+	End15Days
+)
+
+/*
+Night a night of the Kiddush Levana window.
+*/
+type Night struct {
+	// GDate the civil date of the evening the night starts
+	GDate gdt.GDate
+	// JewishDate the Jewish date of the night
+	JewishDate jdt.JDate
+	// Start the beginning of the usable span, tzais clipped by the tchilas zman Kiddush Levana
+	Start time.Time
+	// End the end of the usable span, alos of the next morning clipped by the sof zman Kiddush Levana
+	End time.Time
+	// MotzeiShabbos is the night after Shabbos, when Kiddush Levana is customarily said
+	MotzeiShabbos bool
+	// MoonBelowHorizon is the moon below the horizon for the whole span
+	MoonBelowHorizon bool
+	// BeforeTishaBav is it a night of Av before Tisha B'Av, when the custom is to wait until after the fast
+	BeforeTishaBav bool
+	// MotzeiTishaBav is the night after Tisha B'Av
+	MotzeiTishaBav bool
+	// BeforeYomKippur is it a night of Tishrei before Yom Kippur, when the custom is to wait until after Yom Kippur
+	BeforeYomKippur bool
+	// MotzeiYomKippur is the night after Yom Kippur
+	MotzeiYomKippur bool
+}
+
+/*
+IsValid returns if Kiddush Levana can be said on the Night: the moon is above the horizon at some time of the span and
+the night is not deferred by the Tisha B'Av or Yom Kippur conventions.
+*/
+func (t Night) IsValid() bool {
+	return !t.MoonBelowHorizon && !t.BeforeTishaBav && !t.BeforeYomKippur
+}
+
+/*
+Planner plans the nights of Kiddush Levana at a calculator.GeoLocation. By default, the window is from
+Start3Days to EndBetweenMoldos and the night is from zmanim.ZmanimCalendar.Tzais to zmanim.ZmanimCalendar.Alos.
+The nights on which tzais or alos can't be computed (see zmanim.AstronomicalCalendar) are not listed.
+*/
+type Planner interface {
+	// Nights returns the nights of the window of the month of the jewishDate passed in
+	Nights(jewishDate hebrewcalendar.JewishDate) []Night
+	// NextValidNight returns the first Night.IsValid night whose span ends after the instant tm
+	NextValidNight(tm time.Time) (night Night, ok bool)
+	GeoLocation() calculator.GeoLocation
+	Start() Start
+	SetStart(start Start)
+	End() End
+	SetEnd(end End)
+	SetTzais(tzais func(zc zmanim.ZmanimCalendar) (tm time.Time, ok bool))
+	SetAlos(alos func(zc zmanim.ZmanimCalendar) (tm time.Time, ok bool))
+}
+
+type planner struct {
+	geoLocation            calculator.GeoLocation
+	astronomicalCalculator calculator.AstronomicalCalculator
+	start                  Start
+	end                    End
+	tzais                  func(zc zmanim.ZmanimCalendar) (tm time.Time, ok bool)
+	alos                   func(zc zmanim.ZmanimCalendar) (tm time.Time, ok bool)
+}
+
+func newPlanner() *planner {
+	return &planner{}
+}
+
+func (t *planner) initPlanner(geoLocation calculator.GeoLocation, astronomicalCalculator calculator.AstronomicalCalculator) {
+	t.geoLocation = geoLocation
+	t.astronomicalCalculator = astronomicalCalculator
+	t.start = Start3Days
+	t.end = EndBetweenMoldos
+	t.tzais = zmanim.ZmanimCalendar.Tzais
+	t.alos = zmanim.ZmanimCalendar.Alos
+}
+
+func NewPlanner(geoLocation calculator.GeoLocation, astronomicalCalculator calculator.AstronomicalCalculator) Planner {
+	t := newPlanner()
+
+	t.initPlanner(geoLocation, astronomicalCalculator)
+
+	return t
+}
+
+func (t *planner) GeoLocation() calculator.GeoLocation {
+	return t.geoLocation
+}
+
+func (t *planner) Start() Start {
+	return t.start
+}
+
+func (t *planner) SetStart(start Start) {
+	if start != Start3Days && start != Start7Days {
+		panic("unknown Start")
+	}
+	t.start = start
+}
+
+func (t *planner) End() End {
+	return t.end
+}
+
+func (t *planner) SetEnd(end End) {
+	if end != EndBetweenMoldos && end != End15Days {
+		panic("unknown End")
+	}
+	t.end = end
+}
+
+func (t *planner) SetTzais(tzais func(zc zmanim.ZmanimCalendar) (tm time.Time, ok bool)) {
+	if tzais == nil {
+		panic("tzais == nil")
+	}
+	t.tzais = tzais
+}
+
+func (t *planner) SetAlos(alos func(zc zmanim.ZmanimCalendar) (tm time.Time, ok bool)) {
+	if alos == nil {
+		panic("alos == nil")
+	}
+	t.alos = alos
+}
+
+/*
+window returns the tchilas and the sof zman Kiddush Levana of the month of the jewishDate.
+*/
+func (t *planner) window(jewishDate hebrewcalendar.JewishDate) (start time.Time, end time.Time) {
+	jewishCalendar := hebrewcalendar.NewJewishCalendar(jewishDate)
+
+	switch t.start {
+	case Start7Days:
+		start = jewishCalendar.TchilasZmanKidushLevana7Days()
+	default:
+		start = jewishCalendar.TchilasZmanKidushLevana3Days()
+	}
+
+	switch t.end {
+	case End15Days:
+		end = jewishCalendar.SofZmanKidushLevana15Days()
+	default:
+		end = jewishCalendar.SofZmanKidushLevanaBetweenMoldos()
+	}
+
+	return start, end
+}
+
+func (t *planner) Nights(jewishDate hebrewcalendar.JewishDate) []Night {
+	start, end := t.window(jewishDate)
+
+	var nights []Night
+	// the night of the tchilas zman may have started on the evening before
+	for date := start.In(t.location()).AddDate(0, 0, -1); !date.After(end.In(t.location())); date = date.AddDate(0, 0, 1) {
+		if night, ok := t.night(gdt.NewGDate1(date), start, end); ok {
+			nights = append(nights, night)
+		}
+	}
+
+	return nights
+}
+
+func (t *planner) NextValidNight(tm time.Time) (night Night, ok bool) {
+	jewishDate := hebrewcalendar.NewJewishDate2(gdt.NewGDate1(tm.In(t.location())))
+
+	// the window of the month may be over, or all its nights may be invalid
+	for i := 0; i < 3; i++ {
+		for _, night := range t.Nights(jewishDate) {
+			if night.End.After(tm) && night.IsValid() {
+				return night, true
+			}
+		}
+		jewishDate.ForwardJMonth(1)
+	}
+
+	return Night{}, false
+}
+
+/*
+night returns the Night starting on the evening of the gDate clipped by the window from start to end, ok is false if
+tzais or alos can't be computed or the span is empty.
+*/
+func (t *planner) night(gDate gdt.GDate, start time.Time, end time.Time) (night Night, ok bool) {
+	evening := zmanim.NewZmanimCalendar(gdt.NewGDateTime(gDate, gdt.NewGTime0()), t.geoLocation, t.astronomicalCalculator)
+	nextDate := gdt.NewGDate1(gDate.ToTime(t.location()).AddDate(0, 0, 1))
+	morning := zmanim.NewZmanimCalendar(gdt.NewGDateTime(nextDate, gdt.NewGTime0()), t.geoLocation, t.astronomicalCalculator)
+
+	tzais, ok := t.tzais(evening)
+	if !ok {
+		return night, false
+	}
+	alos, ok := t.alos(morning)
+	if !ok {
+		return night, false
+	}
+
+	if tzais.Before(start) {
+		tzais = start
+	}
+	if alos.After(end) {
+		alos = end
+	}
+	if !tzais.Before(alos) {
+		return night, false
+	}
+
+	// the night belongs to the Jewish date of the next civil day
+	jewishDate := hebrewcalendar.NewJewishDate2(nextDate)
+	jewishCalendar := hebrewcalendar.NewJewishCalendar(jewishDate)
+	previousDay := hebrewcalendar.NewJewishCalendar(hebrewcalendar.NewJewishDate2(gDate)).YomTov()
+
+	night = Night{
+		GDate:            gDate,
+		JewishDate:       jewishDate.JDate(),
+		Start:            tzais,
+		End:              alos,
+		MotzeiShabbos:    gDate.ToTime(t.location()).Weekday() == time.Saturday,
+		MoonBelowHorizon: !t.isMoonUp(gDate, nextDate, tzais, alos),
+		MotzeiTishaBav:   previousDay == hebrewcalendar.TishaBeav,
+		MotzeiYomKippur:  previousDay == hebrewcalendar.YomKippur,
+	}
+
+	// Tisha B'Av may be postponed to the 10th of Av, when the 9th is on Shabbos
+	night.BeforeTishaBav = jewishDate.JMonth() == jdt.Av && !night.MotzeiTishaBav &&
+		(jewishDate.JDay() < 10 || (jewishDate.JDay() == 10 && jewishCalendar.YomTov() == hebrewcalendar.TishaBeav))
+	night.BeforeYomKippur = jewishDate.JMonth() == jdt.TISHREI && jewishDate.JDay() <= 10 && !night.MotzeiYomKippur
+
+	return night, true
+}
+
+/*
+isMoonUp returns if the moon is above the horizon at some time from start to end, which are on the evening of the
+gDate or the morning of the nextDate.
+*/
+func (t *planner) isMoonUp(gDate gdt.GDate, nextDate gdt.GDate, start time.Time, end time.Time) bool {
+	mc := moon.NewMoonCalendar(gDate, t.geoLocation)
+	if mc.IsMoonUp(start) || mc.IsMoonUp(end) {
+		return true
+	}
+
+	// the moon may rise and set within the span
+	for _, date := range []gdt.GDate{gDate, nextDate} {
+		if moonrise, ok := moon.NewMoonCalendar(date, t.geoLocation).Moonrise(); ok && moonrise.After(start) && moonrise.Before(end) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *planner) location() *time.Location {
+	return timeutil.TimeZoneOrGmt(t.geoLocation.TimeZone())
+}
@@ -0,0 +1,71 @@
+package zmanim
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"time"
+)
+
+/*
+LocalNoon returns 12:00 of the civil date of the calendar in the time zone of the GeoLocation, the anchor of the zmanim
+of the date. It is the same on the days of the daylight saving time transitions, which are not at noon.
+*/
+func (t *astronomicalCalendar) LocalNoon() time.Time {
+	d := t.gDateTime.D
+	tm, _ := timeutil.NewLocalTime(int(d.Year), d.Month, int(d.Day), 12, 0, 0, 0, t.timeZone())
+	return tm
+}
+
+/*
+ZoneTransition returns the transition of the offset of the time zone of the GeoLocation on the civil date of the
+calendar, such as the daylight saving time transition, and the change of the offset, positive when the clocks are set
+forward. ok is false if there is no transition on the date.
+*/
+func (t *astronomicalCalendar) ZoneTransition() (tm time.Time, change time.Duration, ok bool) {
+	d := t.gDateTime.D
+	start, _ := timeutil.NewLocalTime(int(d.Year), d.Month, int(d.Day), 0, 0, 0, 0, t.timeZone())
+	next := addDays(d, 1)
+	end, _ := timeutil.NewLocalTime(int(next.Year), next.Month, int(next.Day), 0, 0, 0, 0, t.timeZone())
+	return timeutil.ZoneTransition(start, end)
+}
+
+/*
+LocalTimeKind returns the timeutil.LocalTimeKind of the wall clock time of the tm, such as of a zman, in the time zone
+of the GeoLocation. It is timeutil.LocalTimeRepeated if the tm is in the hour repeated when the clocks are set back,
+then the wall clock time alone doesn't tell which of the two it is.
+*/
+func (t *astronomicalCalendar) LocalTimeKind(tm time.Time) timeutil.LocalTimeKind {
+	return timeutil.LocalTimeKindOf(tm.In(t.timeZone()))
+}
+
+/*
+ZmanWithKind returns the time of the zman, such as of the method value cal.Tzais72, with the timeutil.LocalTimeKind of
+its wall clock time in the time zone of the GeoLocation, so that the zmanim in the hour repeated when the clocks are set
+back are flagged timeutil.LocalTimeRepeated. ok is false if the zman can't be calculated.
+*/
+func (t *astronomicalCalendar) ZmanWithKind(zman func() (time.Time, bool)) (tm time.Time, kind timeutil.LocalTimeKind, ok bool) {
+	tm, ok = zman()
+	if !ok {
+		return time.Time{}, timeutil.LocalTimeUnique, false
+	}
+	return tm, t.LocalTimeKind(tm), true
+}
+
+/*
+meanSolarNoon returns the UTC time of the mean solar noon, 12:00 of the local mean time of the longitude, nearest to the
+LocalNoon. It is also of the civil date of the time zones across the antimeridian, such as Samoa.
+*/
+func (t *astronomicalCalendar) meanSolarNoon() time.Time {
+	localNoon := t.LocalNoon().UTC()
+	noon := time.Date(localNoon.Year(), localNoon.Month(), localNoon.Day(), 12, 0, 0, 0, time.UTC).
+		Add(-time.Duration(t.GeoLocation().Longitude() / 15 * float64(time.Hour)))
+	if noon.Sub(localNoon) > 12*time.Hour {
+		noon = noon.AddDate(0, 0, -1)
+	} else if localNoon.Sub(noon) > 12*time.Hour {
+		noon = noon.AddDate(0, 0, 1)
+	}
+	return noon
+}
+
+func (t *astronomicalCalendar) timeZone() *time.Location {
+	return timeutil.TimeZoneOrGmt(t.geoLocation.TimeZone())
+}
@@ -0,0 +1,153 @@
+package mizrach
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCompassPoint(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	assert.Equal(t, tag, "N", CompassPoint(0))
+	assert.Equal(t, tag, "N", CompassPoint(359))
+	assert.Equal(t, tag, "NNE", CompassPoint(20))
+	assert.Equal(t, tag, "E", CompassPoint(90))
+	assert.Equal(t, tag, "ESE", CompassPoint(-247.5))
+	assert.Equal(t, tag, "SW", CompassPoint(225))
+	assert.Equal(t, tag, "NNW", CompassPoint(340))
+}
+
+func TestRhumbLineBearing(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	west := calculator.NewGeoLocation1("west", 31.778, 30, nil)
+	assert.True(t, tag, math.Abs(RhumbLineBearing(west, HarHabayisGeoLocation())-90) < 1e-9)
+	assert.True(t, tag, math.Abs(RhumbLineBearing(HarHabayisGeoLocation(), west)-270) < 1e-9)
+
+	// the shorter rhumb line from Samoa to Tokyo crosses the 180 deg meridian westward
+	assert.True(t, tag, RhumbLineBearing(calculator.SamoaGeoLocation(), calculator.TokyoGeoLocation()) > 270)
+}
+
+func TestGreatCircleBearing(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// from Lakewood the great circle to Jerusalem starts to the north-east, while the rhumb line is slightly south of east
+	lakewood := calculator.LakewoodGeoLocation()
+	assert.True(t, tag, math.Abs(GreatCircleBearing(lakewood, HarHabayisGeoLocation())-53.8) < 0.1)
+	assert.True(t, tag, math.Abs(RhumbLineBearing(lakewood, HarHabayisGeoLocation())-95.4) < 0.1)
+
+	// nearly antipodal locations
+	antipode := calculator.NewGeoLocation1("antipode", -31.778, 35.2354-180+0.001, nil)
+	bearing := GreatCircleBearing(antipode, HarHabayisGeoLocation())
+	assert.False(t, tag, math.IsNaN(bearing))
+	assert.True(t, tag, bearing >= 0 && bearing < 360)
+}
+
+func TestDeclination(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject := DefaultMagneticModel()
+	assert.Equal(t, tag, "WMM-2025", subject.Name())
+	assert.Equal(t, tag, 2025.0, subject.Epoch())
+	assert.False(t, tag, subject.IsValid(2024.5))
+	assert.True(t, tag, subject.IsValid(2026))
+	assert.True(t, tag, subject.IsValid(2030))
+	assert.False(t, tag, subject.IsValid(2031))
+
+	// the test value of the WMM2025 report
+	assert.True(t, tag, math.Abs(float64(subject.Declination(80, 0, 0, 2025))-1.28) < 0.01)
+
+	// the declinations published for mid 2026
+	for _, test := range []struct {
+		geoLocation calculator.GeoLocation
+		declination float64
+	}{
+		{calculator.JerusalemGeoLocation(), 5.0},
+		{calculator.LakewoodGeoLocation(), -12.3},
+		{calculator.LosAngelesGeoLocation(), 11.4},
+		{calculator.TokyoGeoLocation(), -7.9},
+	} {
+		declination := subject.Declination(test.geoLocation.Latitude(), test.geoLocation.Longitude(), test.geoLocation.Elevation(), DecimalYear(gdt.NewGDate(2026, 7, 2)))
+		assert.True(t, tag+" "+test.geoLocation.LocationName(), math.Abs(float64(declination)-test.declination) < 0.5)
+	}
+}
+
+func TestReadMagneticModel(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject, err := ReadMagneticModel(strings.NewReader("2025.0 TEST-2025 11/13/2024\n  1  0  -30000.0  0.0  0.0  0.0\n  1  1  0.0  0.0  0.0  0.0\n"))
+	assert.True(t, tag, err == nil)
+	// the axial dipole points to the true north
+	assert.True(t, tag, math.Abs(float64(subject.Declination(45, 100, 0, 2025))) < 1e-9)
+
+	for _, content := range []string{
+		"",
+		"2025.0\n",
+		"x TEST\n 1 0 1 0 0 0\n",
+		"2025.0 TEST\n",
+		"2025.0 TEST\n 1 0 1 0 0\n",
+		"2025.0 TEST\n 1 2 1 0 0 0\n",
+		"2025.0 TEST\n 1 0 x 0 0 0\n",
+	} {
+		_, err = ReadMagneticModel(strings.NewReader(content))
+		assert.True(t, tag, err != nil)
+	}
+
+	_, err = LoadMagneticModel("testdata/missing.COF")
+	assert.True(t, tag, err != nil)
+}
+
+func TestDecimalYear(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	assert.True(t, tag, math.Abs(DecimalYear(gdt.NewGDate(2024, 1, 1))-2024.0014) < 1e-4)
+	assert.True(t, tag, math.Abs(DecimalYear(gdt.NewGDate(2024, 7, 2))-2024.5) < 0.002)
+}
+
+func TestDirection(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	subject := NewCompass()
+	direction := subject.Direction(calculator.LakewoodGeoLocation(), gdt.NewGDate(2026, 7, 2))
+	assert.Equal(t, tag, "NE", direction.CompassPoint)
+	assert.True(t, tag, math.Abs(direction.MagneticGreatCircleBearing-(direction.GreatCircleBearing-float64(direction.MagneticDeclination))) < 1e-9)
+	assert.True(t, tag, math.Abs(direction.MagneticRhumbLineBearing-(direction.RhumbLineBearing-float64(direction.MagneticDeclination))) < 1e-9)
+	assert.True(t, tag, math.Abs(direction.Distance-9.247e6) < 1e4)
+
+	subject.SetTarget(KoselGeoLocation())
+	kosel := subject.Direction(calculator.LakewoodGeoLocation(), gdt.NewGDate(2026, 7, 2))
+	assert.True(t, tag, math.Abs(kosel.GreatCircleBearing-direction.GreatCircleBearing) < 0.01)
+	assert.True(t, tag, math.Abs(kosel.Distance-direction.Distance) < 200)
+
+	// the magnetic bearing wraps around the north
+	direction = subject.Direction(calculator.NewGeoLocation1("north", 60, 35.2345, nil), gdt.NewGDate(2026, 7, 2))
+	assert.Equal(t, tag, "S", direction.CompassPoint)
+	assert.True(t, tag, direction.MagneticGreatCircleBearing >= 0 && direction.MagneticGreatCircleBearing < 360)
+}
+
+func TestDirectionOutOfMagneticModel(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// out of the years of the WMM2025 the declination is extrapolated, the true bearings don't depend on the date
+	subject := NewCompass()
+	valid := subject.Direction(calculator.LakewoodGeoLocation(), gdt.NewGDate(2026, 7, 2))
+	for _, gDate := range []gdt.GDate{gdt.NewGDate(2020, 7, 2), gdt.NewGDate(2035, 7, 2)} {
+		assert.False(t, tag, subject.MagneticModel().IsValid(DecimalYear(gDate)))
+		direction := subject.Direction(calculator.LakewoodGeoLocation(), gDate)
+		assert.Equal(t, tag, valid.GreatCircleBearing, direction.GreatCircleBearing)
+		assert.False(t, tag, math.IsNaN(float64(direction.MagneticDeclination)))
+		assert.True(t, tag, direction.MagneticDeclination != valid.MagneticDeclination)
+	}
+}
+
+func TestSetTargetNil(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	defer assert.Raises(t, tag)()
+
+	NewCompass().SetTarget(nil)
+}
@@ -0,0 +1,250 @@
+package mizrach
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/*
+wmmCoefficients the coefficient file of the World Magnetic Model WMM2025 of NOAA NCEI and the British Geological Survey,
+in the WMM.COF format.
+*/
+//go:embed WMM.COF
+var wmmCoefficients []byte
+
+const (
+	// wmmReferenceRadius the geomagnetic reference radius of the WMM in km
+	wmmReferenceRadius = 6371.2
+	// wgs84SemiMajorAxis the semi-major axis of the WGS-84 ellipsoid in km
+	wgs84SemiMajorAxis = 6378.137
+	// wgs84Flattening the flattening of the WGS-84 ellipsoid
+	wgs84Flattening = 1 / 298.257223563
+	// wmmValidYears the years after the epoch the model is valid
+	wmmValidYears = 5
+)
+
+/*
+MagneticModel a spherical harmonic model of the main geomagnetic field, such as the World Magnetic Model, for the
+magnetic declination (the angle of the magnetic north east of the true north) that a compass has to be corrected by.
+*/
+type MagneticModel interface {
+	// Name returns the name of the model, such as WMM-2025
+	Name() string
+	// Epoch returns the decimal year the coefficients are of
+	Epoch() float64
+	// IsValid returns if the decimalYear is in the 5 years from the Epoch the model is issued for. Out of them the
+	// declination is extrapolated by the secular variation and loses accuracy.
+	IsValid(decimalYear float64) bool
+	// Declination returns the magnetic declination at the location, positive if the magnetic north is east of the true
+	// north, in the decimalYear (see DecimalYear)
+	Declination(latitude float64, longitude float64, elevation dimension.Meters, decimalYear float64) dimension.Degrees
+}
+
+type magneticModel struct {
+	name  string
+	epoch float64
+	// degree the maximal degree n of the coefficients
+	degree int
+	// g, h and the secular variations gDot, hDot the Schmidt semi-normalized Gauss coefficients by [n][m] in nT and
+	// nT per year
+	g, h, gDot, hDot [][]float64
+}
+
+/*
+DefaultMagneticModel returns the embedded World Magnetic Model WMM2025, valid from 2025.0 to 2030.0. For the later
+years load the current WMM.COF, published by NOAA NCEI, with LoadMagneticModel.
+*/
+func DefaultMagneticModel() MagneticModel {
+	t, err := ReadMagneticModel(bytes.NewReader(wmmCoefficients))
+	if err != nil {
+		panic(err.Error())
+	}
+	return t
+}
+
+/*
+ReadMagneticModel reads a MagneticModel in the WMM.COF format: the header line of the epoch and the name, followed by
+the lines n m g h gDot hDot, ended by a line of 9s or the end of the file.
+*/
+func ReadMagneticModel(r io.Reader) (MagneticModel, error) {
+	scanner := bufio.NewScanner(r)
+
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("the magnetic model has no header")
+	}
+	header := strings.Fields(scanner.Text())
+	if len(header) < 2 {
+		return nil, fmt.Errorf("%q is not a magnetic model header", scanner.Text())
+	}
+	epoch, err := strconv.ParseFloat(header[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a magnetic model epoch", header[0])
+	}
+
+	// the row of the degree 0 has no coefficients
+	t := &magneticModel{name: header[1], epoch: epoch, g: [][]float64{{0}}, h: [][]float64{{0}}, gDot: [][]float64{{0}}, hDot: [][]float64{{0}}}
+	for line := 2; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if strings.HasPrefix(fields[0], "9999") {
+			break
+		}
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("line %d: %q is not n m g h gDot hDot", line, scanner.Text())
+		}
+
+		var values [6]float64
+		for i, field := range fields {
+			if values[i], err = strconv.ParseFloat(field, 64); err != nil {
+				return nil, fmt.Errorf("line %d: %s is not a number", line, field)
+			}
+		}
+		n, m := int(values[0]), int(values[1])
+		if n < 1 || m < 0 || m > n || n > 100 {
+			return nil, fmt.Errorf("line %d: n = %d, m = %d are out of range", line, n, m)
+		}
+		t.grow(n)
+		t.g[n][m], t.h[n][m], t.gDot[n][m], t.hDot[n][m] = values[2], values[3], values[4], values[5]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if t.degree == 0 {
+		return nil, fmt.Errorf("the magnetic model has no coefficients")
+	}
+
+	return t, nil
+}
+
+/*
+LoadMagneticModel loads a MagneticModel from the WMM.COF file, see ReadMagneticModel.
+*/
+func LoadMagneticModel(path string) (MagneticModel, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadMagneticModel(file)
+}
+
+/*
+grow makes room for the coefficients up to the degree n.
+*/
+func (t *magneticModel) grow(n int) {
+	for t.degree < n {
+		t.degree++
+		t.g = append(t.g, make([]float64, t.degree+1))
+		t.h = append(t.h, make([]float64, t.degree+1))
+		t.gDot = append(t.gDot, make([]float64, t.degree+1))
+		t.hDot = append(t.hDot, make([]float64, t.degree+1))
+	}
+}
+
+func (t *magneticModel) Name() string {
+	return t.name
+}
+
+func (t *magneticModel) Epoch() float64 {
+	return t.epoch
+}
+
+func (t *magneticModel) IsValid(decimalYear float64) bool {
+	return decimalYear >= t.epoch && decimalYear <= t.epoch+wmmValidYears
+}
+
+/*
+Declination sums the spherical harmonic expansion of the field at the geocentric location, as in the WMM technical
+report, and rotates it to the geodetic north and east.
+*/
+func (t *magneticModel) Declination(latitude float64, longitude float64, elevation dimension.Meters, decimalYear float64) dimension.Degrees {
+	dt := decimalYear - t.epoch
+
+	// the geodetic to the geocentric coordinates
+	phi := float64(dimension.Degrees(latitude).ToRadians())
+	lambda := float64(dimension.Degrees(longitude).ToRadians())
+	height := float64(elevation) / 1000
+	e2 := wgs84Flattening * (2 - wgs84Flattening)
+	rc := wgs84SemiMajorAxis / math.Sqrt(1-e2*math.Sin(phi)*math.Sin(phi))
+	p := (rc + height) * math.Cos(phi)
+	z := (rc*(1-e2) + height) * math.Sin(phi)
+	r := math.Hypot(p, z)
+	phiC := math.Asin(z / r)
+
+	// the Gauss normalized associated Legendre functions of the geocentric colatitude and their derivatives
+	cosTheta, sinTheta := math.Sin(phiC), math.Cos(phiC)
+	legendre, dLegendre := make([][]float64, t.degree+1), make([][]float64, t.degree+1)
+	schmidt := make([][]float64, t.degree+1)
+	for n := 0; n <= t.degree; n++ {
+		legendre[n], dLegendre[n], schmidt[n] = make([]float64, n+1), make([]float64, n+1), make([]float64, n+1)
+	}
+	legendre[0][0], schmidt[0][0] = 1, 1
+	for n := 1; n <= t.degree; n++ {
+		schmidt[n][0] = schmidt[n-1][0] * float64(2*n-1) / float64(n)
+		for m := 0; m <= n; m++ {
+			switch {
+			case m == n:
+				legendre[n][m] = sinTheta * legendre[n-1][m-1]
+				dLegendre[n][m] = sinTheta*dLegendre[n-1][m-1] + cosTheta*legendre[n-1][m-1]
+			case n == 1 || m == n-1:
+				legendre[n][m] = cosTheta * legendre[n-1][m]
+				dLegendre[n][m] = cosTheta*dLegendre[n-1][m] - sinTheta*legendre[n-1][m]
+			default:
+				k := float64((n-1)*(n-1)-m*m) / float64((2*n-1)*(2*n-3))
+				legendre[n][m] = cosTheta*legendre[n-1][m] - k*legendre[n-2][m]
+				dLegendre[n][m] = cosTheta*dLegendre[n-1][m] - sinTheta*legendre[n-1][m] - k*dLegendre[n-2][m]
+			}
+			if m > 0 {
+				delta := 1.0
+				if m == 1 {
+					delta = 2
+				}
+				schmidt[n][m] = schmidt[n][m-1] * math.Sqrt(float64(n-m+1)*delta/float64(n+m))
+			}
+		}
+	}
+
+	// the field in the geocentric radial (outward), theta (southward) and east components
+	var bRadial, bTheta, bEast float64
+	for n := 1; n <= t.degree; n++ {
+		ratio := math.Pow(wmmReferenceRadius/r, float64(n+2))
+		for m := 0; m <= n; m++ {
+			g := (t.g[n][m] + dt*t.gDot[n][m]) * schmidt[n][m]
+			h := (t.h[n][m] + dt*t.hDot[n][m]) * schmidt[n][m]
+			cosM, sinM := math.Cos(float64(m)*lambda), math.Sin(float64(m)*lambda)
+
+			bRadial += ratio * float64(n+1) * (g*cosM + h*sinM) * legendre[n][m]
+			bTheta -= ratio * (g*cosM + h*sinM) * dLegendre[n][m]
+			bEast += ratio * float64(m) * (g*sinM - h*cosM) * legendre[n][m]
+		}
+	}
+	if sinTheta > 1e-10 {
+		bEast /= sinTheta
+	}
+
+	// the geocentric to the geodetic north
+	psi := phi - phiC
+	bNorth := -bTheta*math.Cos(psi) - bRadial*math.Sin(psi)
+
+	return dimension.Radians(math.Atan2(bEast, bNorth)).ToDegrees()
+}
+
+/*
+DecimalYear returns the gDate as the decimal year of the MagneticModel, about 2024.5 for July 2, 2024.
+*/
+func DecimalYear(gDate gdt.GDate) float64 {
+	dayOfYear := gDate.ToAbsDate() - gdt.NewGDate(gDate.Year, 1, 1).ToAbsDate()
+	return float64(gDate.Year) + (float64(dayOfYear)+0.5)/float64(gDate.Year.DaysInGYear())
+}
@@ -0,0 +1,175 @@
+/*
+Package mizrach finds the direction to face when davening, toward Jerusalem, from a calculator.GeoLocation: the
+great-circle and the rhumb-line bearings to Har HaBayis (or another target), their compass point, and the bearings
+corrected by the magnetic declination of a MagneticModel, so that a phone compass can be pointed correctly.
+*/
+package mizrach
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+)
+
+/*
+HarHabayisGeoLocation returns the location of the Kodesh HaKodashim on Har HaBayis, the default target of the Compass.
+*/
+func HarHabayisGeoLocation() calculator.GeoLocation {
+	return calculator.NewGeoLocation2("Har HaBayis, Jerusalem", 31.778, 35.2354, 740, timeutil.LoadLocationOrPanic("Asia/Jerusalem"))
+}
+
+/*
+KoselGeoLocation returns the location of the Kosel HaMaaravi (the Western Wall) plaza.
+*/
+func KoselGeoLocation() calculator.GeoLocation {
+	return calculator.NewGeoLocation2("Kosel HaMaaravi, Jerusalem", 31.7767, 35.2345, 720, timeutil.LoadLocationOrPanic("Asia/Jerusalem"))
+}
+
+/*
+compassPoints the 16 points of the compass rose clockwise from the north.
+*/
+var compassPoints = [...]string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+/*
+CompassPoint returns the nearest of the 16 points of the compass rose, such as N, NNE or ESE, to the bearing from the
+north clockwise in degrees.
+*/
+func CompassPoint(bearing float64) string {
+	return compassPoints[int(math.Round(normalizeBearing(bearing)/22.5))%len(compassPoints)]
+}
+
+/*
+Direction the direction from a location to the target of the Compass. The bearings are clockwise in degrees from 0 to
+360 exclusive, the true ones from the true north and the magnetic ones from the magnetic north a compass points to.
+*/
+type Direction struct {
+	// GreatCircleBearing the initial bearing of the shortest path (the geodesic on the WGS-84 ellipsoid) to the target
+	GreatCircleBearing float64
+	// RhumbLineBearing the constant bearing of the rhumb line to the target, the direction of the target on a Mercator map
+	RhumbLineBearing float64
+	// CompassPoint the compass point of the GreatCircleBearing, see CompassPoint
+	CompassPoint string
+	// MagneticDeclination the angle of the magnetic north east (positive) or west (negative) of the true north
+	MagneticDeclination dimension.Degrees
+	// MagneticGreatCircleBearing the GreatCircleBearing from the magnetic north
+	MagneticGreatCircleBearing float64
+	// MagneticRhumbLineBearing the RhumbLineBearing from the magnetic north
+	MagneticRhumbLineBearing float64
+	// Distance the geodesic distance to the target in meters
+	Distance float64
+}
+
+/*
+Compass the Direction to the target, by default HarHabayisGeoLocation, corrected by the DefaultMagneticModel.
+*/
+type Compass interface {
+	/*
+		Direction returns the Direction from the geoLocation to the target: the initial great-circle bearing to the target
+		(by default Har HaBayis), and the rhumb-line bearing, corrected by the magnetic declination of the MagneticModel
+		(by default the WMM2025) at the geoLocation on the date gDate. The WMM2025 is valid from 2025.0 to 2030.0. Out of
+		them Direction neither fails nor panics: the declination is extrapolated linearly by the secular variation of the
+		model and loses accuracy, check MagneticModel.IsValid of the DecimalYear of the gDate.
+	*/
+	Direction(geoLocation calculator.GeoLocation, gDate gdt.GDate) Direction
+	Target() calculator.GeoLocation
+	SetTarget(target calculator.GeoLocation)
+	MagneticModel() MagneticModel
+	SetMagneticModel(magneticModel MagneticModel)
+}
+
+type compass struct {
+	target        calculator.GeoLocation
+	magneticModel MagneticModel
+}
+
+func newCompass() *compass {
+	return &compass{}
+}
+
+func (t *compass) initCompass() {
+	t.target = HarHabayisGeoLocation()
+	t.magneticModel = DefaultMagneticModel()
+}
+
+func NewCompass() Compass {
+	t := newCompass()
+
+	t.initCompass()
+
+	return t
+}
+
+func (t *compass) Target() calculator.GeoLocation {
+	return t.target
+}
+
+func (t *compass) SetTarget(target calculator.GeoLocation) {
+	if target == nil {
+		panic("target == nil")
+	}
+	t.target = target
+}
+
+func (t *compass) MagneticModel() MagneticModel {
+	return t.magneticModel
+}
+
+func (t *compass) SetMagneticModel(magneticModel MagneticModel) {
+	if magneticModel == nil {
+		panic("magneticModel == nil")
+	}
+	t.magneticModel = magneticModel
+}
+
+func (t *compass) Direction(geoLocation calculator.GeoLocation, gDate gdt.GDate) Direction {
+	greatCircle := GreatCircleBearing(geoLocation, t.target)
+	rhumbLine := RhumbLineBearing(geoLocation, t.target)
+	declination := t.magneticModel.Declination(geoLocation.Latitude(), geoLocation.Longitude(), geoLocation.Elevation(), DecimalYear(gDate))
+
+	return Direction{
+		GreatCircleBearing:         greatCircle,
+		RhumbLineBearing:           rhumbLine,
+		CompassPoint:               CompassPoint(greatCircle),
+		MagneticDeclination:        declination,
+		MagneticGreatCircleBearing: normalizeBearing(greatCircle - float64(declination)),
+		MagneticRhumbLineBearing:   normalizeBearing(rhumbLine - float64(declination)),
+		Distance:                   geoLocation.GeodesicDistance(t.target),
+	}
+}
+
+/*
+GreatCircleBearing returns the initial bearing of the geodesic from the location to the target, see
+calculator.GeoLocation.GeodesicInitialBearing, from 0 to 360 deg exclusive. For the nearly antipodal locations, where
+Vincenty's formula doesn't converge, the bearing is of the great circle on the sphere.
+*/
+func GreatCircleBearing(location calculator.GeoLocation, target calculator.GeoLocation) float64 {
+	if bearing := location.GeodesicInitialBearing(target); !math.IsNaN(bearing) {
+		return normalizeBearing(bearing)
+	}
+
+	phi1, phi2 := dimension.Degrees(location.Latitude()).ToRadians(), dimension.Degrees(target.Latitude()).ToRadians()
+	deltaLambda := float64(dimension.Degrees(target.Longitude() - location.Longitude()).ToRadians())
+	y := math.Sin(deltaLambda) * math.Cos(float64(phi2))
+	x := math.Cos(float64(phi1))*math.Sin(float64(phi2)) - math.Sin(float64(phi1))*math.Cos(float64(phi2))*math.Cos(deltaLambda)
+	return normalizeBearing(float64(dimension.Radians(math.Atan2(y, x)).ToDegrees()))
+}
+
+/*
+RhumbLineBearing returns the constant bearing of the [rhumb line]: https://en.wikipedia.org/wiki/Rhumb_line from the
+location to the target, the shorter one across the 180 deg meridian, from 0 to 360 deg exclusive.
+*/
+func RhumbLineBearing(location calculator.GeoLocation, target calculator.GeoLocation) float64 {
+	phi1, phi2 := float64(dimension.Degrees(location.Latitude()).ToRadians()), float64(dimension.Degrees(target.Latitude()).ToRadians())
+	deltaLambda := float64(dimension.Degrees(target.Longitude() - location.Longitude()).ToRadians())
+	if math.Abs(deltaLambda) > math.Pi {
+		deltaLambda -= math.Copysign(2*math.Pi, deltaLambda)
+	}
+	deltaPsi := math.Log(math.Tan(math.Pi/4+phi2/2) / math.Tan(math.Pi/4+phi1/2))
+	return normalizeBearing(float64(dimension.Radians(math.Atan2(deltaLambda, deltaPsi)).ToDegrees()))
+}
+
+func normalizeBearing(bearing float64) float64 {
+	return math.Mod(math.Mod(bearing, 360)+360, 360)
+}
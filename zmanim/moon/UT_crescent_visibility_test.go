@@ -0,0 +1,82 @@
+package moon
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/jdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"testing"
+	"time"
+)
+
+func TestConjunctionOfJewishMonth(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// Shevat 5784, the new moon was on January 11, 2024 at 11:57 UT
+	conjunction := ConjunctionOfJewishMonth(hebrewcalendar.NewJewishDate1(jdt.NewJDate(5784, 11, 1)))
+
+	assert.Equal(t, tag, time.Date(2024, 1, 11, 6, 24, 17, 0, time.UTC), conjunction.Molad.UTC().Round(time.Second))
+	assert.Equal(t, tag, time.Date(2024, 1, 11, 11, 57, 0, 0, time.UTC), conjunction.NewMoon.Round(time.Minute))
+	assert.Equal(t, tag, conjunction.NewMoon.Sub(conjunction.Molad), conjunction.Offset)
+	assert.Equal(t, tag, 5*time.Hour+33*time.Minute, conjunction.Offset.Round(time.Minute))
+}
+
+func TestCrescentVisibilityOn(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	geoLocation := calculator.JerusalemGeoLocation()
+
+	// the new moon of Iyar 5784 was on May 8, 2024 at 03:22 UT
+	visibility, ok := CrescentVisibilityOn(gdt.NewGDate(2024, 5, 8), geoLocation)
+	assert.True(t, tag, ok)
+	assert.True(t, tag, visibility.Moonset.After(visibility.Sunset))
+	assert.Equal(t, tag, visibility.Moonset.Sub(visibility.Sunset), visibility.Lag)
+	assert.Equal(t, tag, 13*time.Hour+24*time.Minute, visibility.Age.Round(time.Minute))
+	assert.Equal(t, tag, YallopNotVisibleWithTelescope, visibility.Yallop)
+	assert.Equal(t, tag, OdehVisibleByOpticalAidOnly, visibility.Odeh)
+	assert.False(t, tag, visibility.IsVisible(YallopCriterion))
+	assert.False(t, tag, visibility.IsVisible(OdehCriterion))
+
+	visibility, ok = CrescentVisibilityOn(gdt.NewGDate(2024, 5, 9), geoLocation)
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, YallopEasilyVisible, visibility.Yallop)
+	assert.Equal(t, tag, OdehVisibleByNakedEye, visibility.Odeh)
+
+	// on the evening before the new moon of Shevat 5784 the moon sets before the sun
+	_, ok = CrescentVisibilityOn(gdt.NewGDate(2024, 1, 11), geoLocation)
+	assert.False(t, tag, ok)
+}
+
+func TestEarliestCrescentVisibility(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	geoLocation := calculator.JerusalemGeoLocation()
+
+	newMoon := NewMoonNear(time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC))
+
+	for _, criterion := range []VisibilityCriterion{YallopCriterion, OdehCriterion} {
+		visibility, ok := EarliestCrescentVisibility(newMoon, geoLocation, criterion)
+		assert.True(t, tag, ok)
+		assert.Equal(t, tag, gdt.NewGDate(2024, 5, 9), visibility.Date)
+		assert.True(t, tag, visibility.IsVisible(criterion))
+	}
+}
+
+func TestVisibilityCategories(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	assert.Equal(t, tag, YallopEasilyVisible, yallopCategory(0.3))
+	assert.Equal(t, tag, YallopVisibleInPerfectConditions, yallopCategory(0))
+	assert.Equal(t, tag, YallopOpticalAidToFind, yallopCategory(-0.1))
+	assert.Equal(t, tag, YallopOpticalAidOnly, yallopCategory(-0.2))
+	assert.Equal(t, tag, YallopNotVisibleWithTelescope, yallopCategory(-0.25))
+	assert.Equal(t, tag, YallopNotVisible, yallopCategory(-0.3))
+
+	assert.Equal(t, tag, OdehVisibleByNakedEye, odehZone(5.65))
+	assert.Equal(t, tag, OdehVisibleByOpticalAid, odehZone(2))
+	assert.Equal(t, tag, OdehVisibleByOpticalAidOnly, odehZone(0))
+	assert.Equal(t, tag, OdehNotVisible, odehZone(-1))
+
+	defer assert.Raises(t, tag)()
+	CrescentVisibility{}.IsVisible(VisibilityCriterion(2))
+}
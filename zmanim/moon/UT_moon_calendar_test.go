@@ -0,0 +1,85 @@
+package moon
+
+import (
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMoonriseMoonsetTransit(t *testing.T) {
+	var noMoonrise, noMoonset, noTransit []gdt.GDay
+
+	for day := gdt.GDay(1); day <= 31; day++ {
+		tag := fmt.Sprintf("%s[%d]", helper.CurrentFuncName(), day)
+		mc := NewMoonCalendar(gdt.NewGDate(2024, 1, day), calculator.JerusalemGeoLocation())
+
+		if moonrise, ok := mc.Moonrise(); ok {
+			assert.Equal(t, tag, int(day), moonrise.Day())
+			assert.False(t, tag, mc.IsMoonUp(moonrise.Add(-time.Minute)))
+			assert.True(t, tag, mc.IsMoonUp(moonrise.Add(time.Minute)))
+		} else {
+			noMoonrise = append(noMoonrise, day)
+		}
+
+		if moonset, ok := mc.Moonset(); ok {
+			assert.Equal(t, tag, int(day), moonset.Day())
+			assert.True(t, tag, mc.IsMoonUp(moonset.Add(-time.Minute)))
+			assert.False(t, tag, mc.IsMoonUp(moonset.Add(time.Minute)))
+		} else {
+			noMoonset = append(noMoonset, day)
+		}
+
+		if transit, ok := mc.MoonTransit(); ok {
+			assert.True(t, tag, math.Abs(float64(PositionAt(transit, mc.GeoLocation()).HourAngle)) < 0.01)
+		} else {
+			noTransit = append(noTransit, day)
+		}
+	}
+
+	// the moon rises, sets and transits about 50 minutes later every day, so each is missing on one day of the month
+	tag := helper.CurrentFuncName()
+	assert.Equal(t, tag, []gdt.GDay{4}, noMoonrise)
+	assert.Equal(t, tag, []gdt.GDay{18}, noMoonset)
+	assert.Equal(t, tag, []gdt.GDay{25}, noTransit)
+}
+
+func TestMoonriseNearNewAndFullMoon(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	jerusalem := calculator.JerusalemGeoLocation()
+
+	// on the day of the new moon of January 11, 2024 the moon rises with the sun (6:38)
+	moonrise, ok := NewMoonCalendar(gdt.NewGDate(2024, 1, 11), jerusalem).Moonrise()
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, "06:42", moonrise.Format("15:04"))
+
+	// on the day of the full moon of January 25, 2024 the moon rises at sunset (16:59)
+	moonrise, ok = NewMoonCalendar(gdt.NewGDate(2024, 1, 25), jerusalem).Moonrise()
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, "16:41", moonrise.Format("15:04"))
+}
+
+func TestMoonAlwaysUpAndDown(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	daneborg := calculator.DaneborgGeoLocation()
+
+	// in Daneborg, Greenland the moon does not set around the full moon of January 2024 ...
+	mc := NewMoonCalendar(gdt.NewGDate(2024, 1, 22), daneborg)
+	_, ok := mc.Moonrise()
+	assert.False(t, tag, ok)
+	_, ok = mc.Moonset()
+	assert.False(t, tag, ok)
+	assert.True(t, tag, mc.IsMoonUp(time.Date(2024, 1, 22, 12, 0, 0, 0, daneborg.TimeZone())))
+
+	// ... and does not rise around the new moon
+	mc = NewMoonCalendar(gdt.NewGDate(2024, 1, 9), daneborg)
+	_, ok = mc.Moonrise()
+	assert.False(t, tag, ok)
+	_, ok = mc.Moonset()
+	assert.False(t, tag, ok)
+	assert.False(t, tag, mc.IsMoonUp(time.Date(2024, 1, 9, 12, 0, 0, 0, daneborg.TimeZone())))
+}
@@ -0,0 +1,95 @@
+package moon
+
+import (
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"math"
+	"testing"
+	"time"
+)
+
+func roundFloat(val float64, precision uint) float64 {
+	ratio := math.Pow(10, float64(precision))
+	return math.Round(val*ratio) / ratio
+}
+
+/*
+meeusExample47aJDE 1992 April 12, 0h TD of the Example 47.a and 48.a of Astronomical Algorithms by Jean Meeus.
+*/
+const meeusExample47aJDE = 2448724.5
+
+func TestGeocentricPosition(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	g := geocentricPosition(meeusExample47aJDE)
+
+	assert.Equal(t, tag, 133.16726, roundFloat(float64(g.Longitude), 5))
+	assert.Equal(t, tag, -3.229126, roundFloat(float64(g.Latitude), 6))
+	assert.Equal(t, tag, 368409.7, roundFloat(float64(g.Distance), 1))
+	assert.Equal(t, tag, 0.99199, roundFloat(float64(horizontalParallax(g.Distance)), 5))
+
+	alpha, delta := calculator.EclipticToEquatorial(g.Longitude, g.Latitude, calculator.TrueObliquityOfEcliptic(meeusExample47aJDE))
+	assert.Equal(t, tag, 134.6885, roundFloat(float64(alpha), 4))
+	assert.Equal(t, tag, 13.7684, roundFloat(float64(delta), 4))
+}
+
+func TestPhase(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	phase := phaseAtJDE(meeusExample47aJDE)
+
+	assert.Equal(t, tag, 69.0756, roundFloat(float64(phase.PhaseAngle), 4))
+	assert.Equal(t, tag, 0.6786, roundFloat(phase.IlluminatedFraction, 4))
+	assert.True(t, tag, phase.Waxing)
+}
+
+func TestPhaseAtAge(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	tm := time.Date(1992, 4, 12, 0, 0, 0, 0, time.UTC)
+	phase := PhaseAt(tm)
+
+	// the new moon of 1992 April 3, 5:01 UT
+	assert.Equal(t, tag, time.Date(1992, 4, 3, 5, 1, 0, 0, time.UTC), phase.NewMoon.Round(time.Minute))
+	assert.Equal(t, tag, tm.Sub(phase.NewMoon), phase.Age)
+
+	// just before the next new moon the moon is waning and the age almost a synodic month
+	phase = PhaseAt(time.Date(1992, 5, 2, 12, 0, 0, 0, time.UTC))
+	assert.False(t, tag, phase.Waxing)
+	assert.Equal(t, tag, time.Date(1992, 4, 3, 5, 1, 0, 0, time.UTC), phase.NewMoon.Round(time.Minute))
+	assert.True(t, tag, phase.IlluminatedFraction < 0.01)
+}
+
+func TestNewMoonNear(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	// Astronomical Algorithms Example 49.a, the new moon of 1977 February 18, 3h37m42s TD, 3h36m54s UT
+	want := time.Date(1977, 2, 18, 3, 36, 54, 0, time.UTC)
+	for _, tm := range []time.Time{time.Date(1977, 2, 10, 0, 0, 0, 0, time.UTC), time.Date(1977, 3, 1, 0, 0, 0, 0, time.UTC)} {
+		newMoon := NewMoonNear(tm)
+		assert.True(t, tag, math.Abs(newMoon.Sub(want).Seconds()) < 10)
+	}
+}
+
+func TestPositionAtSubLunarPoint(t *testing.T) {
+	tag := helper.CurrentFuncName()
+	tm := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	position := PositionAt(tm, calculator.JerusalemGeoLocation())
+
+	jde := calculator.JulianEphemerisDay(tm)
+	deltaPsi, _ := calculator.Nutation(jde)
+	siderealTime := calculator.ApparentSiderealTime(calculator.JulianDay(tm), deltaPsi, calculator.TrueObliquityOfEcliptic(jde))
+	longitude := float64(position.RightAscension - siderealTime)
+	for longitude > 180 {
+		longitude -= 360
+	}
+	for longitude < -180 {
+		longitude += 360
+	}
+
+	// the moon is in the zenith of the sub-lunar point
+	subLunar := calculator.NewGeoLocation2("sub-lunar point", float64(position.Declination), longitude, 0, time.UTC)
+	assert.True(t, tag, PositionAt(tm, subLunar).Altitude > 89.8)
+
+	// the parallax lowers the moon below the geometric horizon, 90 deg away from the sub-lunar point
+	horizon := calculator.NewGeoLocation2("horizon", float64(position.Declination)-90, longitude, 0, time.UTC)
+	altitude := PositionAt(tm, horizon).Altitude
+	assert.True(t, tag, altitude < -0.8 && altitude > -position.HorizontalParallax-0.01)
+}
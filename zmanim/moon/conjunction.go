@@ -0,0 +1,32 @@
+package moon
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar"
+	"time"
+)
+
+/*
+Conjunction compares the calculated (mean) molad of a Jewish month with the true astronomical new moon.
+*/
+type Conjunction struct {
+	// Molad the mean molad of the month (see hebrewcalendar.JewishCalendar.MoladAsDate)
+	Molad time.Time
+	// NewMoon the true new moon (the conjunction in longitude of the moon and the sun) nearest to the Molad
+	NewMoon time.Time
+	// Offset the time from the Molad to the NewMoon, negative if the NewMoon is before the Molad
+	Offset time.Duration
+}
+
+/*
+ConjunctionOfJewishMonth returns the Conjunction of the month of the jewishDate passed in.
+*/
+func ConjunctionOfJewishMonth(jewishDate hebrewcalendar.JewishDate) Conjunction {
+	molad := hebrewcalendar.NewJewishCalendar(jewishDate).MoladAsDate()
+	newMoon := NewMoonNear(molad)
+
+	return Conjunction{
+		Molad:   molad,
+		NewMoon: newMoon,
+		Offset:  newMoon.Sub(molad),
+	}
+}
@@ -0,0 +1,261 @@
+package moon
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"time"
+)
+
+/*
+YallopCategory the visibility category of the crescent by the criterion of B. D. Yallop (NAO Technical Note 69, 1997).
+*/
+type YallopCategory string
+
+const (
+	// YallopEasilyVisible (A) the crescent is easily visible to the naked eye
+	YallopEasilyVisible YallopCategory = "A"
+	// YallopVisibleInPerfectConditions (B) the crescent is visible to the naked eye under perfect conditions
+	YallopVisibleInPerfectConditions YallopCategory = "B"
+	// YallopOpticalAidToFind (C) an optical aid may be needed to find the crescent
+	YallopOpticalAidToFind YallopCategory = "C"
+	// YallopOpticalAidOnly (D) the crescent is visible only with an optical aid
+	YallopOpticalAidOnly YallopCategory = "D"
+	// YallopNotVisibleWithTelescope (E) the crescent is not visible with a telescope
+	YallopNotVisibleWithTelescope YallopCategory = "E"
+	// YallopNotVisible (F) the crescent is not visible, below the Danjon limit
+	YallopNotVisible YallopCategory = "F"
+)
+
+/*
+OdehZone the visibility zone of the crescent by the criterion of M. Odeh (Experimental Astronomy 18, 2004).
+*/
+type OdehZone string
+
+const (
+	// OdehVisibleByNakedEye (A) the crescent is visible by the naked eye
+	OdehVisibleByNakedEye OdehZone = "A"
+	// OdehVisibleByOpticalAid (B) the crescent is visible by an optical aid, and may be seen by the naked eye
+	OdehVisibleByOpticalAid OdehZone = "B"
+	// OdehVisibleByOpticalAidOnly (C) the crescent is visible by an optical aid only
+	OdehVisibleByOpticalAidOnly OdehZone = "C"
+	// OdehNotVisible (D) the crescent is not visible even by an optical aid
+	OdehNotVisible OdehZone = "D"
+)
+
+/*
+VisibilityCriterion the criterion used to decide if the crescent is visible to the naked eye.
+*/
+type VisibilityCriterion int
+
+const (
+	// YallopCriterion the crescent is visible in the YallopCategory A or B
+	YallopCriterion VisibilityCriterion = iota
+	// OdehCriterion the crescent is visible in the OdehZone A
+	OdehCriterion
+)
+
+/*
+CrescentVisibility the estimate of the visibility of the new crescent on the evening of a date at a
+calculator.GeoLocation. The values are calculated at the BestTime after the sunset.
+*/
+type CrescentVisibility struct {
+	// Date the local date of the evening
+	Date gdt.GDate
+	// Sunset the sunset of the upper limb of the sun
+	Sunset time.Time
+	// Moonset the first moonset after the Sunset
+	Moonset time.Time
+	// Lag the time from the Sunset to the Moonset
+	Lag time.Duration
+	// BestTime the best time to see the crescent, the Sunset + 4/9 of the Lag
+	BestTime time.Time
+	// Age the time from the true new moon to the BestTime, negative if the BestTime is before the new moon
+	Age time.Duration
+	// ARCL the geocentric elongation of the moon from the sun (arc of light)
+	ARCL dimension.Degrees
+	// ARCV the difference of the geocentric airless altitudes of the moon and the sun (arc of vision)
+	ARCV dimension.Degrees
+	// DAZ the difference of the azimuths of the sun and the moon
+	DAZ dimension.Degrees
+	// Width the topocentric width of the crescent calculated with the geocentric ARCL
+	Width dimension.ArcMinutes
+	// YallopQ the q test value of the Yallop criterion
+	YallopQ float64
+	// Yallop the YallopCategory of the YallopQ
+	Yallop YallopCategory
+	// TopocentricARCL the topocentric elongation of the moon from the sun
+	TopocentricARCL dimension.Degrees
+	// TopocentricARCV the difference of the topocentric airless altitudes of the moon and the sun
+	TopocentricARCV dimension.Degrees
+	// TopocentricWidth the topocentric width of the crescent
+	TopocentricWidth dimension.ArcMinutes
+	// OdehV the V test value of the Odeh criterion
+	OdehV float64
+	// Odeh the OdehZone of the OdehV
+	Odeh OdehZone
+}
+
+/*
+IsVisible returns if the crescent is visible to the naked eye by the criterion passed in.
+*/
+func (t CrescentVisibility) IsVisible(criterion VisibilityCriterion) bool {
+	switch criterion {
+	case YallopCriterion:
+		return t.Yallop == YallopEasilyVisible || t.Yallop == YallopVisibleInPerfectConditions
+	case OdehCriterion:
+		return t.Odeh == OdehVisibleByNakedEye
+	default:
+		panic("unknown VisibilityCriterion")
+	}
+}
+
+/*
+CrescentVisibilityOn returns the CrescentVisibility on the evening of the gDate at the geoLocation. If the sun does not
+set on the date, the moon sets before the sun or does not set within 12 hours after the sunset, there is no crescent
+to see and ok is false will be returned.
+*/
+func CrescentVisibilityOn(gDate gdt.GDate, geoLocation calculator.GeoLocation) (visibility CrescentVisibility, ok bool) {
+	c := newMoonCalendar()
+	c.initMoonCalendar(gDate, geoLocation)
+
+	sunset, ok := c.findCrossing(c.sunUpperLimbAltitude, false)
+	if !ok {
+		return visibility, false
+	}
+	if !c.IsMoonUp(sunset) {
+		return visibility, false
+	}
+	moonset, ok := findCrossingBetween(c.upperLimbAltitude, sunset, sunset.Add(12*time.Hour), false)
+	if !ok {
+		return visibility, false
+	}
+
+	lag := moonset.Sub(sunset)
+	bestTime := sunset.Add(lag * 4 / 9).Round(time.Second)
+
+	visibility = CrescentVisibility{
+		Date:     gDate,
+		Sunset:   sunset,
+		Moonset:  moonset,
+		Lag:      lag,
+		BestTime: bestTime,
+		Age:      bestTime.Sub(NewMoonNear(bestTime)),
+	}
+	visibility.calculate(geoLocation)
+
+	return visibility, true
+}
+
+/*
+EarliestCrescentVisibility returns the CrescentVisibility of the first evening, starting with the local date of the
+newMoon, on which the crescent is visible at the geoLocation by the criterion passed in. The search stops after 5
+evenings, then ok is false will be returned.
+*/
+func EarliestCrescentVisibility(newMoon time.Time, geoLocation calculator.GeoLocation, criterion VisibilityCriterion) (visibility CrescentVisibility, ok bool) {
+	date := newMoon.In(timeutil.TimeZoneOrGmt(geoLocation.TimeZone()))
+
+	for i := 0; i < 5; i++ {
+		visibility, ok = CrescentVisibilityOn(gdt.NewGDate1(date.AddDate(0, 0, i)), geoLocation)
+		if ok && visibility.Sunset.After(newMoon) && visibility.IsVisible(criterion) {
+			return visibility, true
+		}
+	}
+
+	return CrescentVisibility{}, false
+}
+
+/*
+calculate calculates the arcs, the widths and the criteria at the BestTime.
+*/
+func (t *CrescentVisibility) calculate(geoLocation calculator.GeoLocation) {
+	jde := calculator.JulianEphemerisDay(t.BestTime)
+	deltaPsi, _ := calculator.Nutation(jde)
+	epsilon := calculator.TrueObliquityOfEcliptic(jde)
+	siderealTime := calculator.ApparentSiderealTime(calculator.JulianDay(t.BestTime), deltaPsi, epsilon)
+
+	moon := PositionAt(t.BestTime, geoLocation)
+	moonGeocentricAltitude, _ := topocentricHorizontal(moon.RightAscension, moon.Declination, 0, siderealTime, geoLocation)
+
+	sun := calculator.ApparentSunPosition(jde)
+	sunRightAscension, sunDeclination := calculator.EclipticToEquatorial(sun.Longitude, sun.Latitude, epsilon)
+	sunAltitude, sunAzimuth := topocentricHorizontal(sunRightAscension, sunDeclination, 0, siderealTime, geoLocation)
+
+	// the topocentric semi-diameter of the moon
+	semiDiameter := dimension.ArcMinutes(0.27245 * float64(moon.HorizontalParallax) * 60 * (1 + moon.Altitude.Sin()*moon.HorizontalParallax.Sin()))
+
+	t.ARCL = phaseAtJDE(jde).Elongation
+	t.ARCV = moonGeocentricAltitude - sunAltitude
+	t.DAZ = sunAzimuth - moon.Azimuth
+	t.Width = semiDiameter * dimension.ArcMinutes(1-t.ARCL.Cos())
+	t.YallopQ = (float64(t.ARCV) - crescentPolynomial(11.8371, t.Width)) / 10
+	t.Yallop = yallopCategory(t.YallopQ)
+
+	t.TopocentricARCL = dimension.ACos(moon.Altitude.Sin()*sunAltitude.Sin() + moon.Altitude.Cos()*sunAltitude.Cos()*t.DAZ.Cos())
+	t.TopocentricARCV = moon.Altitude - sunAltitude
+	t.TopocentricWidth = semiDiameter * dimension.ArcMinutes(1-t.TopocentricARCL.Cos())
+	t.OdehV = float64(t.TopocentricARCV) - crescentPolynomial(7.1651, t.TopocentricWidth)
+	t.Odeh = odehZone(t.OdehV)
+}
+
+/*
+crescentPolynomial returns the minimal ARCV for the crescent width w of the Yallop and Odeh criteria, which differ
+only by the constant a0.
+*/
+func crescentPolynomial(a0 float64, width dimension.ArcMinutes) float64 {
+	w := float64(width)
+	return a0 - 6.3226*w + 0.7319*w*w - 0.1018*w*w*w
+}
+
+/*
+yallopCategory returns the YallopCategory of the q test value.
+*/
+func yallopCategory(q float64) YallopCategory {
+	switch {
+	case q > 0.216:
+		return YallopEasilyVisible
+	case q > -0.014:
+		return YallopVisibleInPerfectConditions
+	case q > -0.160:
+		return YallopOpticalAidToFind
+	case q > -0.232:
+		return YallopOpticalAidOnly
+	case q > -0.293:
+		return YallopNotVisibleWithTelescope
+	default:
+		return YallopNotVisible
+	}
+}
+
+/*
+odehZone returns the OdehZone of the V test value.
+*/
+func odehZone(v float64) OdehZone {
+	switch {
+	case v >= 5.65:
+		return OdehVisibleByNakedEye
+	case v >= 2:
+		return OdehVisibleByOpticalAid
+	case v >= -0.96:
+		return OdehVisibleByOpticalAidOnly
+	default:
+		return OdehNotVisible
+	}
+}
+
+/*
+sunUpperLimbAltitude returns the apparent altitude of the upper limb of the sun at the time tm above the horizon,
+depressed for the elevation of the calculator.GeoLocation (see upperLimbAltitude).
+*/
+func (t *moonCalendar) sunUpperLimbAltitude(tm time.Time) float64 {
+	jde := calculator.JulianEphemerisDay(tm)
+	deltaPsi, _ := calculator.Nutation(jde)
+	epsilon := calculator.TrueObliquityOfEcliptic(jde)
+	siderealTime := calculator.ApparentSiderealTime(calculator.JulianDay(tm), deltaPsi, epsilon)
+
+	sun := calculator.ApparentSunPosition(jde)
+	rightAscension, declination := calculator.EclipticToEquatorial(sun.Longitude, sun.Latitude, epsilon)
+	altitude, _ := topocentricHorizontal(rightAscension, declination, 0, siderealTime, t.geoLocation)
+	return apparentUpperLimbAltitude(altitude, dimension.ArcMinutes(16).InDegrees(), t.geoLocation.Elevation())
+}
@@ -0,0 +1,165 @@
+package moon
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"time"
+)
+
+/*
+MoonCalendar calculates the moonrise, the moonset and the transit of the moon for a date at a calculator.GeoLocation,
+analogous to the sunrise and sunset of zmanim.AstronomicalCalendar.
+
+The date is the local date in the calculator.GeoLocation TimeZone, from midnight to midnight. Since the moon rises on
+average 50 minutes later every day, there is a day every month without a moonrise and one without a moonset, and in
+the polar regions there are days when the moon is always above or always below the horizon. When the event does not
+happen on the date, ok = false will be returned.
+*/
+type MoonCalendar interface {
+	Moonrise() (tm time.Time, ok bool)
+	Moonset() (tm time.Time, ok bool)
+	MoonTransit() (tm time.Time, ok bool)
+	IsMoonUp(tm time.Time) bool
+	GDate() gdt.GDate
+	GeoLocation() calculator.GeoLocation
+}
+
+type moonCalendar struct {
+	gDate       gdt.GDate
+	geoLocation calculator.GeoLocation
+}
+
+func newMoonCalendar() *moonCalendar {
+	return &moonCalendar{}
+}
+
+func (t *moonCalendar) initMoonCalendar(gDate gdt.GDate, geoLocation calculator.GeoLocation) {
+	t.gDate = gDate
+	t.geoLocation = geoLocation
+}
+
+func NewMoonCalendar(gDate gdt.GDate, geoLocation calculator.GeoLocation) MoonCalendar {
+	t := newMoonCalendar()
+
+	t.initMoonCalendar(gDate, geoLocation)
+
+	return t
+}
+
+func (t *moonCalendar) GDate() gdt.GDate {
+	return t.gDate
+}
+
+func (t *moonCalendar) GeoLocation() calculator.GeoLocation {
+	return t.geoLocation
+}
+
+/*
+Moonrise returns the first time on the date the upper limb of the moon rises above the horizon, taking into account
+the refraction of 34 dimension.ArcMinutes, the parallax and the elevation of the calculator.GeoLocation.
+If the moon does not rise on the date, ok is false will be returned.
+*/
+func (t *moonCalendar) Moonrise() (tm time.Time, ok bool) {
+	return t.findCrossing(t.upperLimbAltitude, true)
+}
+
+/*
+Moonset returns the first time on the date the upper limb of the moon sets below the horizon (see Moonrise).
+If the moon does not set on the date, ok is false will be returned.
+*/
+func (t *moonCalendar) Moonset() (tm time.Time, ok bool) {
+	return t.findCrossing(t.upperLimbAltitude, false)
+}
+
+/*
+MoonTransit returns the time on the date the moon crosses the meridian above the pole (its upper transit), whether
+the moon is above the horizon or not. If there is no upper transit on the date, ok is false will be returned.
+*/
+func (t *moonCalendar) MoonTransit() (tm time.Time, ok bool) {
+	return t.findCrossing(func(tm time.Time) float64 {
+		return float64(PositionAt(tm, t.geoLocation).HourAngle)
+	}, true)
+}
+
+/*
+IsMoonUp returns if the upper limb of the moon is above the horizon at the time tm (see Moonrise).
+*/
+func (t *moonCalendar) IsMoonUp(tm time.Time) bool {
+	return t.upperLimbAltitude(tm) > 0
+}
+
+/*
+horizonRefraction the refraction at the horizon used for moonrise and moonset.
+*/
+const horizonRefraction = dimension.ArcMinutes(34)
+
+/*
+upperLimbAltitude returns the apparent topocentric altitude of the upper limb of the moon at the time tm above the
+horizon, depressed for the elevation of the calculator.GeoLocation.
+*/
+func (t *moonCalendar) upperLimbAltitude(tm time.Time) float64 {
+	position := PositionAt(tm, t.geoLocation)
+	// the radius of the moon is 0.2725 of the equatorial radius of the earth
+	semiDiameter := dimension.ASin(0.2725 * position.HorizontalParallax.Sin())
+	return apparentUpperLimbAltitude(position.Altitude, semiDiameter, t.geoLocation.Elevation())
+}
+
+/*
+apparentUpperLimbAltitude returns the altitude of the upper limb of the body of the semiDiameter whose center is at the
+altitude, raised by the horizonRefraction and by the dip of the horizon for the elevation.
+*/
+func apparentUpperLimbAltitude(altitude dimension.Degrees, semiDiameter dimension.Degrees, elevation dimension.Meters) float64 {
+	const earthRadius = 6356900.0
+	dip := dimension.ACos(earthRadius / (earthRadius + float64(elevation)))
+	return float64(altitude + semiDiameter + horizonRefraction.InDegrees() + dip)
+}
+
+/*
+findCrossing returns the first time on the date the function f crosses zero upwards (or downwards if not rising),
+see findCrossingBetween.
+*/
+func (t *moonCalendar) findCrossing(f func(tm time.Time) float64, rising bool) (tm time.Time, ok bool) {
+	start := t.gDate.ToTime(t.location())
+	return findCrossingBetween(f, start, start.AddDate(0, 0, 1), rising)
+}
+
+func (t *moonCalendar) location() *time.Location {
+	return timeutil.TimeZoneOrGmt(t.geoLocation.TimeZone())
+}
+
+/*
+findCrossingBetween returns the first time between start and end the function f crosses zero upwards (or downwards
+if not rising). f is sampled every hour, the crossing is refined by bisection to a millisecond. Crossings where f
+jumps by more than 180 (the wraparound of an angle) are ignored.
+*/
+func findCrossingBetween(f func(tm time.Time) float64, start time.Time, end time.Time, rising bool) (tm time.Time, ok bool) {
+	previous, previousValue := start, f(start)
+	for previous.Before(end) {
+		next := previous.Add(time.Hour)
+		if next.After(end) {
+			next = end
+		}
+		nextValue := f(next)
+
+		if math.Abs(nextValue-previousValue) < 180 &&
+			((rising && previousValue < 0 && nextValue >= 0) || (!rising && previousValue >= 0 && nextValue < 0)) {
+			lo, hi := previous, next
+			for hi.Sub(lo) > time.Millisecond {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if (f(mid) < 0) == rising {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			return hi.Round(time.Second).In(start.Location()), true
+		}
+
+		previous, previousValue = next, nextValue
+	}
+
+	return time.Time{}, false
+}
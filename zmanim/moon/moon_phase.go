@@ -0,0 +1,121 @@
+package moon
+
+import (
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"time"
+)
+
+/*
+SynodicMonth the mean length of the synodic month, from a new moon to the next.
+*/
+const SynodicMonth = time.Duration(synodicMonthDays * 24 * float64(time.Hour))
+
+const synodicMonthDays = 29.530588861
+
+/*
+Phase the phase of the moon at an instant.
+*/
+type Phase struct {
+	// PhaseAngle the selenocentric elongation of the earth from the sun, 180 deg at the new moon, 0 deg at the full moon
+	PhaseAngle dimension.Degrees
+	// IlluminatedFraction the illuminated fraction of the disk of the moon, between 0 and 1
+	IlluminatedFraction float64
+	// Elongation the geocentric elongation of the moon from the sun
+	Elongation dimension.Degrees
+	// Waxing is the moon waxing (between the new moon and the full moon)
+	Waxing bool
+	// NewMoon the instant of the last true new moon (conjunction in longitude) before the instant of the Phase
+	NewMoon time.Time
+	// Age the time since the NewMoon
+	Age time.Duration
+}
+
+/*
+PhaseAt returns the Phase of the moon at the instant tm (Astronomical Algorithms by Jean Meeus, chapter 48).
+*/
+func PhaseAt(tm time.Time) Phase {
+	jde := calculator.JulianEphemerisDay(tm)
+	phase := phaseAtJDE(jde)
+
+	phase.NewMoon = calculator.TimeFromJulianEphemerisDay(conjunctionNear(jde - float64(longitudeElongation(jde))/meanElongationRate))
+	if phase.NewMoon.After(tm) {
+		phase.NewMoon = calculator.TimeFromJulianEphemerisDay(conjunctionNear(calculator.JulianEphemerisDay(phase.NewMoon) - synodicMonthDays))
+	}
+	phase.Age = tm.Sub(phase.NewMoon)
+
+	return phase
+}
+
+/*
+phaseAtJDE returns the Phase (without the NewMoon and the Age) for the Julian Ephemeris Day jde.
+*/
+func phaseAtJDE(jde float64) Phase {
+	moon := geocentricPosition(jde)
+	sun := calculator.ApparentSunPosition(jde)
+	epsilon := calculator.TrueObliquityOfEcliptic(jde)
+
+	alpha, delta := calculator.EclipticToEquatorial(moon.Longitude, moon.Latitude, epsilon)
+	alpha0, delta0 := calculator.EclipticToEquatorial(sun.Longitude, sun.Latitude, epsilon)
+
+	// geocentric elongation (48.2)
+	psi := dimension.ACos(delta0.Sin()*delta.Sin() + delta0.Cos()*delta.Cos()*(alpha0-alpha).Cos())
+	// phase angle (48.3)
+	r := float64(calculator.AstronomicalUnit) * sun.Distance
+	i := dimension.Radians(math.Atan2(r*psi.Sin(), float64(moon.Distance)-r*psi.Cos())).ToDegrees()
+
+	return Phase{
+		PhaseAngle: i,
+		// (48.1)
+		IlluminatedFraction: (1 + i.Cos()) / 2,
+		Elongation:          psi,
+		Waxing:              longitudeElongation(jde) < 180,
+	}
+}
+
+/*
+meanElongationRate the mean daily motion of the moon in longitude relative to the sun in Degrees.
+*/
+const meanElongationRate = 360 / synodicMonthDays
+
+/*
+longitudeElongation returns the difference of the apparent longitudes of the moon and the sun in the range 0 - 360 deg
+for the Julian Ephemeris Day jde.
+*/
+func longitudeElongation(jde float64) dimension.Degrees {
+	return normalizeDegrees(geocentricPosition(jde).Longitude - calculator.ApparentSunPosition(jde).Longitude)
+}
+
+/*
+conjunctionNear returns the Julian Ephemeris Day of the conjunction in longitude of the moon and the sun nearest to
+the Julian Ephemeris Day jde, which must be within a few days of the conjunction.
+*/
+func conjunctionNear(jde float64) float64 {
+	for i := 0; i < 20; i++ {
+		elongation := float64(longitudeElongation(jde))
+		if elongation > 180 {
+			elongation -= 360
+		}
+		rate := float64(normalizeDegrees(longitudeElongation(jde+0.01)-longitudeElongation(jde-0.01))) / 0.02
+		correction := elongation / rate
+		jde -= correction
+		if math.Abs(correction) < 1e-7 {
+			break
+		}
+	}
+	return jde
+}
+
+/*
+NewMoonNear returns the instant of the true new moon (the conjunction in longitude of the moon and the sun) nearest
+to the instant tm.
+*/
+func NewMoonNear(tm time.Time) time.Time {
+	jde := calculator.JulianEphemerisDay(tm)
+	elongation := float64(longitudeElongation(jde))
+	if elongation > 180 {
+		elongation -= 360
+	}
+	return calculator.TimeFromJulianEphemerisDay(conjunctionNear(jde - elongation/meanElongationRate))
+}
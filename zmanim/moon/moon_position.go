@@ -0,0 +1,180 @@
+/*
+Package moon calculates the position, the phase, the rise and the set of the moon, following the lunar theory of
+chapter 47 of [Astronomical Algorithms]: http://www.willbell.com/math/mc1.htm by Jean Meeus (a truncated ELP-2000/82),
+which gives the longitude of the moon with an accuracy of about 10" and the latitude of about 4".
+*/
+package moon
+
+import (
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"time"
+)
+
+/*
+Position the position of the moon at an instant seen from a calculator.GeoLocation.
+*/
+type Position struct {
+	// Longitude the apparent geocentric ecliptic longitude
+	Longitude dimension.Degrees
+	// Latitude the geocentric ecliptic latitude
+	Latitude dimension.Degrees
+	// Distance the distance between the centers of the earth and the moon
+	Distance dimension.KM
+	// RightAscension the apparent geocentric right ascension
+	RightAscension dimension.Degrees
+	// Declination the apparent geocentric declination
+	Declination dimension.Degrees
+	// HorizontalParallax the equatorial horizontal parallax
+	HorizontalParallax dimension.Degrees
+	// HourAngle the geocentric local hour angle in the range -180 - 180 deg, negative east of the meridian
+	HourAngle dimension.Degrees
+	// Altitude the topocentric altitude of the center of the moon above the horizon, not corrected for refraction
+	Altitude dimension.Degrees
+	// Azimuth the topocentric azimuth, measured eastward from the north
+	Azimuth dimension.Degrees
+}
+
+/*
+EarthEquatorialRadius the equatorial radius of the earth used for the parallax (IAU 1976).
+*/
+const EarthEquatorialRadius = dimension.KM(6378.14)
+
+/*
+PositionAt returns the Position of the moon at the instant tm seen from the geoLocation.
+*/
+func PositionAt(tm time.Time, geoLocation calculator.GeoLocation) Position {
+	jde := calculator.JulianEphemerisDay(tm)
+	g := geocentricPosition(jde)
+
+	position := Position{
+		Longitude:          g.Longitude,
+		Latitude:           g.Latitude,
+		Distance:           g.Distance,
+		HorizontalParallax: horizontalParallax(g.Distance),
+	}
+
+	deltaPsi, _ := calculator.Nutation(jde)
+	epsilon := calculator.TrueObliquityOfEcliptic(jde)
+	position.RightAscension, position.Declination = calculator.EclipticToEquatorial(g.Longitude, g.Latitude, epsilon)
+
+	siderealTime := calculator.ApparentSiderealTime(calculator.JulianDay(tm), deltaPsi, epsilon)
+	position.HourAngle = normalizeDegrees(siderealTime+dimension.Degrees(geoLocation.Longitude())-position.RightAscension+180) - 180
+	position.Altitude, position.Azimuth = topocentricHorizontal(position.RightAscension, position.Declination, position.HorizontalParallax, siderealTime, geoLocation)
+
+	return position
+}
+
+/*
+eclipticPosition the apparent geocentric ecliptic position of the moon, as the calculator.EclipticPosition of the sun,
+but of the Distance in dimension.KM rather than in the Astronomical Units.
+*/
+type eclipticPosition struct {
+	Longitude dimension.Degrees
+	Latitude  dimension.Degrees
+	Distance  dimension.KM
+}
+
+/*
+geocentricPosition returns the apparent geocentric ecliptic position of the moon for the Julian Ephemeris Day jde.
+*/
+func geocentricPosition(jde float64) eclipticPosition {
+	t := (jde - calculator.JulianDayJan12000) / calculator.JulianDaysPerCentury
+
+	// mean longitude (47.1)
+	lPrime := 218.3164477 + t*(481267.88123421+t*(-0.0015786+t*(1.0/538841-t/65194000)))
+	// mean elongation (47.2)
+	d := 297.8501921 + t*(445267.1114034+t*(-0.0018819+t*(1.0/545868-t/113065000)))
+	// sun's mean anomaly (47.3)
+	m := 357.5291092 + t*(35999.0502909+t*(-0.0001536+t/24490000))
+	// moon's mean anomaly (47.4)
+	mPrime := 134.9633964 + t*(477198.8675055+t*(0.0087414+t*(1.0/69699-t/14712000)))
+	// moon's argument of latitude (47.5)
+	f := 93.2720950 + t*(483202.0175233+t*(-0.0036539+t*(-1.0/3526000+t/863310000)))
+
+	a1 := 119.75 + 131.849*t
+	a2 := 53.09 + 479264.290*t
+	a3 := 313.45 + 481266.484*t
+
+	// eccentricity of the earth's orbit (47.6)
+	e := 1 - t*(0.002516+t*0.0000074)
+	eccentricity := func(m int) float64 {
+		switch m {
+		case 1, -1:
+			return e
+		case 2, -2:
+			return e * e
+		default:
+			return 1
+		}
+	}
+
+	var sumL, sumR, sumB float64
+	for _, term := range longitudeDistanceTerms {
+		argument := dimension.Degrees(float64(term.d)*d + float64(term.m)*m + float64(term.mPrime)*mPrime + float64(term.f)*f)
+		sumL += term.l * eccentricity(term.m) * argument.Sin()
+		sumR += term.r * eccentricity(term.m) * argument.Cos()
+	}
+	for _, term := range latitudeTerms {
+		argument := dimension.Degrees(float64(term.d)*d + float64(term.m)*m + float64(term.mPrime)*mPrime + float64(term.f)*f)
+		sumB += term.b * eccentricity(term.m) * argument.Sin()
+	}
+
+	// additive terms of the action of Venus, Jupiter and the flattening of the earth
+	sumL += 3958*dimension.Degrees(a1).Sin() + 1962*dimension.Degrees(lPrime-f).Sin() + 318*dimension.Degrees(a2).Sin()
+	sumB += -2235*dimension.Degrees(lPrime).Sin() + 382*dimension.Degrees(a3).Sin() + 175*dimension.Degrees(a1-f).Sin() +
+		175*dimension.Degrees(a1+f).Sin() + 127*dimension.Degrees(lPrime-mPrime).Sin() - 115*dimension.Degrees(lPrime+mPrime).Sin()
+
+	deltaPsi, _ := calculator.Nutation(jde)
+
+	return eclipticPosition{
+		Longitude: normalizeDegrees(dimension.Degrees(lPrime+sumL/1000000) + deltaPsi),
+		Latitude:  dimension.Degrees(sumB / 1000000),
+		Distance:  dimension.KM(385000.56 + sumR/1000),
+	}
+}
+
+/*
+horizontalParallax returns the equatorial horizontal parallax of the moon at the distance passed in.
+*/
+func horizontalParallax(distance dimension.KM) dimension.Degrees {
+	return dimension.ASin(float64(EarthEquatorialRadius / distance))
+}
+
+/*
+topocentricHorizontal returns the topocentric altitude (not corrected for refraction) and azimuth of a body at the
+geocentric rightAscension and declination with the horizontalParallax, for the Greenwich apparent siderealTime
+(Astronomical Algorithms by Jean Meeus, chapters 13 and 40).
+*/
+func topocentricHorizontal(rightAscension dimension.Degrees, declination dimension.Degrees, parallax dimension.Degrees, siderealTime dimension.Degrees, geoLocation calculator.GeoLocation) (altitude dimension.Degrees, azimuth dimension.Degrees) {
+	latitude := dimension.Degrees(geoLocation.Latitude())
+	hourAngle := siderealTime + dimension.Degrees(geoLocation.Longitude()) - rightAscension
+
+	u := dimension.ATan(0.99664719 * latitude.Tan())
+	rhoSinPhi := 0.99664719*u.Sin() + float64(geoLocation.Elevation())/6378140*latitude.Sin()
+	rhoCosPhi := u.Cos() + float64(geoLocation.Elevation())/6378140*latitude.Cos()
+
+	deltaAlpha := dimension.Radians(math.Atan2(-rhoCosPhi*parallax.Sin()*hourAngle.Sin(),
+		declination.Cos()-rhoCosPhi*parallax.Sin()*hourAngle.Cos())).ToDegrees()
+	topocentricDeclination := dimension.Radians(math.Atan2((declination.Sin()-rhoSinPhi*parallax.Sin())*deltaAlpha.Cos(),
+		declination.Cos()-rhoCosPhi*parallax.Sin()*hourAngle.Cos())).ToDegrees()
+	topocentricHourAngle := hourAngle - deltaAlpha
+
+	altitude = dimension.ASin(latitude.Sin()*topocentricDeclination.Sin() + latitude.Cos()*topocentricDeclination.Cos()*topocentricHourAngle.Cos())
+	azimuth = normalizeDegrees(dimension.Radians(math.Atan2(topocentricHourAngle.Sin(),
+		topocentricHourAngle.Cos()*latitude.Sin()-topocentricDeclination.Tan()*latitude.Cos())).ToDegrees() + 180)
+
+	return altitude, azimuth
+}
+
+/*
+normalizeDegrees limits the angle passed in to the range 0 - 360 deg.
+*/
+func normalizeDegrees(degrees dimension.Degrees) dimension.Degrees {
+	degrees = dimension.Degrees(math.Mod(float64(degrees), 360))
+	if degrees < 0 {
+		degrees += 360
+	}
+	return degrees
+}
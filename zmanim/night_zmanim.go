@@ -0,0 +1,129 @@
+package zmanim
+
+import (
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"time"
+)
+
+/*
+NightDefinition the start and the end of the night of the night shaos zmaniyos (temporal hours). The night of a date is
+the one that follows its day, the Start is of the evening of the date and the End is of the morning of the next civil
+date.
+*/
+type NightDefinition struct {
+	Name string
+	// Start the start of the night, such as ZmanimCalendar.Shkia or ZmanimCalendar.Tzais, of the ZmanimCalendar of the date
+	Start func(zc ZmanimCalendar) (tm time.Time, ok bool)
+	// End the end of the night, such as ZmanimCalendar.Hanetz or ZmanimCalendar.Alos, of the ZmanimCalendar of the next date
+	End func(zc ZmanimCalendar) (tm time.Time, ok bool)
+}
+
+/*
+NewNightDefinition returns the NightDefinition of the custom start and end.
+A panic will be if the start or the end is nil.
+*/
+func NewNightDefinition(name string, start func(zc ZmanimCalendar) (tm time.Time, ok bool), end func(zc ZmanimCalendar) (tm time.Time, ok bool)) NightDefinition {
+	if start == nil {
+		panic("start == nil")
+	}
+	if end == nil {
+		panic("end == nil")
+	}
+	return NightDefinition{Name: name, Start: start, End: end}
+}
+
+/*
+NightDefinitionShkiaToHanetz returns the NightDefinition from ZmanimCalendar.Shkia to ZmanimCalendar.Hanetz, the night
+of the GRA.
+*/
+func NightDefinitionShkiaToHanetz() NightDefinition {
+	return NewNightDefinition("shkia to hanetz", ZmanimCalendar.Shkia, ZmanimCalendar.Hanetz)
+}
+
+/*
+NightDefinitionTzaisToAlos returns the NightDefinition from ZmanimCalendar.Tzais (8.5 deg) to ZmanimCalendar.Alos
+(16.1 deg).
+*/
+func NightDefinitionTzaisToAlos() NightDefinition {
+	return NewNightDefinition("tzais to alos", ZmanimCalendar.Tzais, ZmanimCalendar.Alos)
+}
+
+/*
+NightDefinitionTzais72ToAlos72 returns the NightDefinition from ZmanimCalendar.Tzais72 to ZmanimCalendar.Alos72, the
+night between the ends of the day of the MGA.
+*/
+func NightDefinitionTzais72ToAlos72() NightDefinition {
+	return NewNightDefinition("tzais 72 to alos 72", ZmanimCalendar.Tzais72, ZmanimCalendar.Alos72)
+}
+
+/*
+NightBounds returns the start and the end of the night of the NightDefinition night that follows the day of this
+ZmanimCalendar, the end is on the next civil date. The NightDefinition Start and End are passed the calendars of the
+type and the settings of this one, the ComplexZmanimCalendar of a ComplexZmanimCalendar.
+If the calculation can't be computed such as in the Arctic Circle in the summer where the sun does not set, or the sun
+doesn't reach the degrees of tzais, ok is false will be returned.
+*/
+func (t *zmanimCalendar) NightBounds(night NightDefinition) (start time.Time, end time.Time, ok bool) {
+	zc := t.dayCalendar()
+	if start, ok = night.Start(zc); !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	if end, ok = night.End(zc.NextDay()); !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+/*
+ShaahZmanisNight returns the gdt.GMillisecond length of the night shaah zmanis (temporal hour), the twelfth of the
+night of the NightDefinition night, see NightBounds.
+*/
+func (t *zmanimCalendar) ShaahZmanisNight(night NightDefinition) (i gdt.GMillisecond, ok bool) {
+	start, end, ok := t.NightBounds(night)
+	if !ok {
+		return 0, false
+	}
+	return temporalHour(start, end), true
+}
+
+/*
+ShaahZmanisNightBasedZman is the night equivalent of ShaahZmanisBasedZman, it returns the zman the hours of night shaos
+zmaniyos after the start of the NightDefinition night, see NightBounds.
+A panic will be if the hours are out of 0 to 12.
+*/
+func (t *zmanimCalendar) ShaahZmanisNightBasedZman(night NightDefinition, hours gdt.GHourH64) (tm time.Time, ok bool) {
+	if !(hours >= 0 && hours <= 12) {
+		panic(fmt.Sprintf("hours %v is not between 0 and 12", hours))
+	}
+	start, end, ok := t.NightBounds(night)
+	if !ok {
+		return time.Time{}, false
+	}
+	return t.ShaahZmanisBasedZman(start, end, hours), true
+}
+
+/*
+ChatzosHalayla returns chatzos halayla (midnight), 6 night shaos zmaniyos after the start of the NightDefinition night,
+such as the middle of the night from tzais to alos. Note that for NightDefinitionShkiaToHanetz it differs slightly from
+ComplexZmanimCalendar.SolarMidnight, which is of the sea level sunset and sunrise.
+*/
+func (t *zmanimCalendar) ChatzosHalayla(night NightDefinition) (tm time.Time, ok bool) {
+	return t.ShaahZmanisNightBasedZman(night, 6)
+}
+
+/*
+SofAshmuraRishona returns the end of the first of the three ashmuros (watches) of the night, 4 night shaos zmaniyos
+after the start of the NightDefinition night, used by some customs for krias shema al hamita.
+*/
+func (t *zmanimCalendar) SofAshmuraRishona(night NightDefinition) (tm time.Time, ok bool) {
+	return t.ShaahZmanisNightBasedZman(night, 4)
+}
+
+/*
+SofAshmuraShniya returns the end of the second of the three ashmuros (watches) of the night, 8 night shaos zmaniyos
+after the start of the NightDefinition night.
+*/
+func (t *zmanimCalendar) SofAshmuraShniya(night NightDefinition) (tm time.Time, ok bool) {
+	return t.ShaahZmanisNightBasedZman(night, 8)
+}
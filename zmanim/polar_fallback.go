@@ -0,0 +1,386 @@
+package zmanim
+
+import (
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"time"
+)
+
+/*
+PolarFallback the strategy used to approximate a sunrise, a sunset or a dip below the horizon that doesn't happen on
+the date at the location, see PolarFallbackCalendar.
+*/
+type PolarFallback int
+
+const (
+	// PolarFallbackNone no fallback, the zman was computed for the date at the location
+	PolarFallbackNone PolarFallback = iota
+	// PolarFallbackNearestDay the time of day of the nearest day on which the sun rises, sets or dips to the zenith
+	PolarFallbackNearestDay
+	// PolarFallbackNearestLatitude the time at the nearest latitude (closer to the equator) on which the sun rises,
+	// sets or dips to the zenith, at the same longitude
+	PolarFallbackNearestLatitude
+	// PolarFallbackFixedLatitude the time at the fixed latitude (see PolarFallbackCalendar.SetFixedLatitude) of the
+	// same hemisphere, at the same longitude
+	PolarFallbackFixedLatitude
+	// PolarFallbackSolarNoonMidnight the local solar noon, if the sun stays below the zenith all day, or the local
+	// solar midnight, if the sun stays above it, where sunrise and sunset merge at the edge of the polar night and day
+	PolarFallbackSolarNoonMidnight
+)
+
+func (t PolarFallback) String() string {
+	switch t {
+	case PolarFallbackNone:
+		return "None"
+	case PolarFallbackNearestDay:
+		return "NearestDay"
+	case PolarFallbackNearestLatitude:
+		return "NearestLatitude"
+	case PolarFallbackFixedLatitude:
+		return "FixedLatitude"
+	case PolarFallbackSolarNoonMidnight:
+		return "SolarNoonMidnight"
+	default:
+		return "Unknown"
+	}
+}
+
+const (
+	// PolarFallbackLatitude45 the latitude of 45 deg, the default of PolarFallbackCalendar.FixedLatitude
+	PolarFallbackLatitude45 = 45.0
+	// PolarFallbackLatitude60 the latitude of 60 deg
+	PolarFallbackLatitude60 = 60.0
+	// polarFallbackLatitudeStep the step toward the equator of PolarFallbackNearestLatitude
+	polarFallbackLatitudeStep = 0.5
+)
+
+/*
+PolarZman a zman of PolarFallbackCalendar with the fallback used to compute it.
+*/
+type PolarZman struct {
+	Time time.Time
+	// Fallback the fallback used, PolarFallbackNone if the zman was computed for the date at the location. When the
+	// zman is based on several times (sunrise and sunset for instance) that required different fallbacks, it is the
+	// last one of them in PolarFallbackCalendar.Strategies
+	Fallback PolarFallback
+}
+
+/*
+PolarFallbackCalendar computes the zmanim of ComplexZmanimCalendar for the locations where the sun may not rise, set or
+dip below the horizon on the date, typically above the Arctic Circle or the deep dips of dawn and dusk in the summer at
+high latitudes. Every sunrise, sunset and dip (see AstronomicalCalendar.UTCSunrise and AstronomicalCalendar.UTCSunset)
+that can't be computed is approximated by the Strategies, tried in order until one succeeds, so that the zmanim based on
+them (such as the shaos zmaniyos or the fixed offsets) are approximated as well.
+By default, the Strategies are PolarFallbackNearestDay, PolarFallbackNearestLatitude, PolarFallbackFixedLatitude and
+PolarFallbackSolarNoonMidnight, the MaxDays of PolarFallbackNearestDay is 183 days and the FixedLatitude is
+PolarFallbackLatitude45.
+Note: the approximated zmanim are not astronomical events, please consult a competent halachic authority for the
+customs of the high latitudes.
+*/
+type PolarFallbackCalendar interface {
+	/*
+		Zman returns the zman of ComplexZmanimCalendar passed in (such as ComplexZmanimCalendar.Sunrise or
+		ComplexZmanimCalendar.Alos19Point8Degrees) with the fallback used, ok is false if it can't be computed by any
+		of the Strategies.
+	*/
+	Zman(zman func(zc ComplexZmanimCalendar) (tm time.Time, ok bool)) (z PolarZman, ok bool)
+	Sunrise() (z PolarZman, ok bool)
+	Sunset() (z PolarZman, ok bool)
+	GDateTime() gdt.GDateTime
+	GeoLocation() calculator.GeoLocation
+	Strategies() []PolarFallback
+	// SetStrategies sets the fallbacks to try in order, none to disable the fallbacks.
+	// A panic will be if a strategy is unknown, PolarFallbackNone or repeated.
+	SetStrategies(strategies ...PolarFallback)
+	MaxDays() int
+	// SetMaxDays sets the maximal number of days before or after the date searched by PolarFallbackNearestDay.
+	// A panic will be if the maxDays is not positive.
+	SetMaxDays(maxDays int)
+	FixedLatitude() float64
+	// SetFixedLatitude sets the latitude of PolarFallbackFixedLatitude, such as PolarFallbackLatitude45 or
+	// PolarFallbackLatitude60. A panic will be if the latitude is not between 0 and 90 deg exclusive.
+	SetFixedLatitude(latitude float64)
+}
+
+type polarFallbackCalendar struct {
+	gDateTime              gdt.GDateTime
+	geoLocation            calculator.GeoLocation
+	astronomicalCalculator calculator.AstronomicalCalculator
+	strategies             []PolarFallback
+	maxDays                int
+	fixedLatitude          float64
+}
+
+func newPolarFallbackCalendar() *polarFallbackCalendar {
+	return &polarFallbackCalendar{}
+}
+
+func (t *polarFallbackCalendar) initPolarFallbackCalendar(gDateTime gdt.GDateTime, geoLocation calculator.GeoLocation, astronomicalCalculator calculator.AstronomicalCalculator) {
+	t.gDateTime = gDateTime
+	t.geoLocation = geoLocation
+	t.astronomicalCalculator = astronomicalCalculator
+	t.strategies = []PolarFallback{PolarFallbackNearestDay, PolarFallbackNearestLatitude, PolarFallbackFixedLatitude, PolarFallbackSolarNoonMidnight}
+	t.maxDays = 183
+	t.fixedLatitude = PolarFallbackLatitude45
+}
+
+func NewPolarFallbackCalendar(gDateTime gdt.GDateTime, geoLocation calculator.GeoLocation, astronomicalCalculator calculator.AstronomicalCalculator) PolarFallbackCalendar {
+	t := newPolarFallbackCalendar()
+
+	t.initPolarFallbackCalendar(gDateTime, geoLocation, astronomicalCalculator)
+
+	return t
+}
+
+/*
+NewPolarFallbackCalendar1 returns the PolarFallbackCalendar of the calculator.Location.
+*/
+func NewPolarFallbackCalendar1(gDateTime gdt.GDateTime, location calculator.Location, astronomicalCalculator calculator.AstronomicalCalculator) PolarFallbackCalendar {
+	return NewPolarFallbackCalendar(gDateTime, location.GeoLocation(), astronomicalCalculator)
+}
+
+func (t *polarFallbackCalendar) GDateTime() gdt.GDateTime {
+	return t.gDateTime
+}
+
+func (t *polarFallbackCalendar) GeoLocation() calculator.GeoLocation {
+	return t.geoLocation
+}
+
+func (t *polarFallbackCalendar) Strategies() []PolarFallback {
+	return append([]PolarFallback(nil), t.strategies...)
+}
+
+func (t *polarFallbackCalendar) SetStrategies(strategies ...PolarFallback) {
+	for i, strategy := range strategies {
+		if strategy <= PolarFallbackNone || strategy > PolarFallbackSolarNoonMidnight {
+			panic("unknown PolarFallback")
+		}
+		for _, previous := range strategies[:i] {
+			if previous == strategy {
+				panic("repeated PolarFallback")
+			}
+		}
+	}
+	t.strategies = append([]PolarFallback(nil), strategies...)
+}
+
+func (t *polarFallbackCalendar) MaxDays() int {
+	return t.maxDays
+}
+
+func (t *polarFallbackCalendar) SetMaxDays(maxDays int) {
+	if maxDays <= 0 {
+		panic("maxDays <= 0")
+	}
+	t.maxDays = maxDays
+}
+
+func (t *polarFallbackCalendar) FixedLatitude() float64 {
+	return t.fixedLatitude
+}
+
+func (t *polarFallbackCalendar) SetFixedLatitude(latitude float64) {
+	if latitude <= 0 || latitude >= 90 {
+		panic("latitude is not between 0 and 90")
+	}
+	t.fixedLatitude = latitude
+}
+
+func (t *polarFallbackCalendar) Zman(zman func(zc ComplexZmanimCalendar) (tm time.Time, ok bool)) (z PolarZman, ok bool) {
+	polarCalculator := &polarFallbackCalculator{AstronomicalCalculator: t.astronomicalCalculator, calendar: t, used: -1}
+
+	var astronomicalCalculator calculator.AstronomicalCalculator = polarCalculator
+	if adjustableCalculator, ok := t.astronomicalCalculator.(calculator.AdjustableCalculator); ok {
+		astronomicalCalculator = &adjustablePolarFallbackCalculator{polarFallbackCalculator: polarCalculator, adjustableCalculator: adjustableCalculator}
+	}
+
+	tm, ok := zman(NewComplexZmanimCalendar(t.gDateTime, t.geoLocation, astronomicalCalculator))
+	if !ok {
+		return z, false
+	}
+
+	z = PolarZman{Time: tm, Fallback: PolarFallbackNone}
+	if polarCalculator.used >= 0 {
+		z.Fallback = t.strategies[polarCalculator.used]
+	}
+	return z, true
+}
+
+func (t *polarFallbackCalendar) Sunrise() (z PolarZman, ok bool) {
+	return t.Zman(ComplexZmanimCalendar.Sunrise)
+}
+
+func (t *polarFallbackCalendar) Sunset() (z PolarZman, ok bool) {
+	return t.Zman(ComplexZmanimCalendar.Sunset)
+}
+
+/*
+polarFallbackCalculator the calculator.AstronomicalCalculator of PolarFallbackCalendar.Zman that approximates the
+sunrise and the sunset that can't be computed by the wrapped calculator with the Strategies of the calendar, and
+records the last of them used.
+*/
+type polarFallbackCalculator struct {
+	calculator.AstronomicalCalculator
+	calendar *polarFallbackCalendar
+	// used the index in the Strategies of the last fallback used, -1 if none
+	used int
+}
+
+func (t *polarFallbackCalculator) CalculatorName() string {
+	return t.AstronomicalCalculator.CalculatorName() + " (polar fallback)"
+}
+
+func (t *polarFallbackCalculator) UTCSunrise(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation, zenith dimension.Degrees, adjustForElevation bool) float64 {
+	return t.fallback(targetDateTime, geoLocation, t.adjustedZenith(targetDateTime, geoLocation, zenith, adjustForElevation), func(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation) float64 {
+		return t.AstronomicalCalculator.UTCSunrise(targetDateTime, geoLocation, zenith, adjustForElevation)
+	})
+}
+
+func (t *polarFallbackCalculator) UTCSunset(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation, zenith dimension.Degrees, adjustForElevation bool) float64 {
+	return t.fallback(targetDateTime, geoLocation, t.adjustedZenith(targetDateTime, geoLocation, zenith, adjustForElevation), func(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation) float64 {
+		return t.AstronomicalCalculator.UTCSunset(targetDateTime, geoLocation, zenith, adjustForElevation)
+	})
+}
+
+/*
+SunHorizontalPosition returns the position of the sun of the wrapped calculator, see calculator.SunHorizontalPositionOf.
+*/
+func (t *polarFallbackCalculator) SunHorizontalPosition(tm time.Time, geoLocation calculator.GeoLocation) (altitude dimension.Degrees, azimuth dimension.Degrees) {
+	return calculator.SunHorizontalPositionOf(t.AstronomicalCalculator, tm, geoLocation)
+}
+
+/*
+adjustablePolarFallbackCalculator the polarFallbackCalculator of a calculator.AdjustableCalculator, that forwards its
+settings, so that the zmanim that read them (such as the visible sunrise) are the ones of the wrapped calculator.
+*/
+type adjustablePolarFallbackCalculator struct {
+	*polarFallbackCalculator
+	adjustableCalculator calculator.AdjustableCalculator
+}
+
+func (t *adjustablePolarFallbackCalculator) RefractionModel() calculator.RefractionModel {
+	return t.adjustableCalculator.RefractionModel()
+}
+
+func (t *adjustablePolarFallbackCalculator) SetRefractionModel(refractionModel calculator.RefractionModel) {
+	t.adjustableCalculator.SetRefractionModel(refractionModel)
+}
+
+func (t *adjustablePolarFallbackCalculator) SolarRadius() dimension.ArcMinutes {
+	return t.adjustableCalculator.SolarRadius()
+}
+
+func (t *adjustablePolarFallbackCalculator) SetSolarRadius(solarRadius dimension.ArcMinutes) {
+	t.adjustableCalculator.SetSolarRadius(solarRadius)
+}
+
+func (t *adjustablePolarFallbackCalculator) IsDateDependentSolarRadius() bool {
+	return t.adjustableCalculator.IsDateDependentSolarRadius()
+}
+
+func (t *adjustablePolarFallbackCalculator) SetDateDependentSolarRadius(dateDependentSolarRadius bool) {
+	t.adjustableCalculator.SetDateDependentSolarRadius(dateDependentSolarRadius)
+}
+
+func (t *adjustablePolarFallbackCalculator) EarthRadius() dimension.KM {
+	return t.adjustableCalculator.EarthRadius()
+}
+
+func (t *adjustablePolarFallbackCalculator) SetEarthRadius(earthRadius dimension.KM) {
+	t.adjustableCalculator.SetEarthRadius(earthRadius)
+}
+
+/*
+adjustedZenith returns the zenith as adjusted by the calculator for sunrise and sunset, see calculator.AdjustedZenithOf.
+*/
+func (t *polarFallbackCalculator) adjustedZenith(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation, zenith dimension.Degrees, adjustForElevation bool) dimension.Degrees {
+	var elevation dimension.Meters
+	if adjustForElevation {
+		elevation = geoLocation.Elevation()
+	}
+	return calculator.AdjustedZenithOf(t.AstronomicalCalculator, targetDateTime, zenith, elevation)
+}
+
+/*
+fallback returns the utc time of the sun passed in, or of its first fallback that can be computed, math.NaN if none.
+The adjustedZenith is the zenith of the sun passed in, adjusted by the calculator.
+*/
+func (t *polarFallbackCalculator) fallback(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation, adjustedZenith dimension.Degrees, utc func(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation) float64) float64 {
+	if f := utc(targetDateTime, geoLocation); !math.IsNaN(f) {
+		return f
+	}
+
+	for i, strategy := range t.calendar.strategies {
+		var f float64
+		switch strategy {
+		case PolarFallbackNearestDay:
+			f = t.nearestDay(targetDateTime, geoLocation, utc)
+		case PolarFallbackNearestLatitude:
+			f = t.nearestLatitude(targetDateTime, geoLocation, utc)
+		case PolarFallbackFixedLatitude:
+			f = t.fixedLatitude(targetDateTime, geoLocation, utc)
+		case PolarFallbackSolarNoonMidnight:
+			f = solarNoonOrMidnight(targetDateTime, geoLocation, adjustedZenith)
+		}
+		if !math.IsNaN(f) {
+			if i > t.used {
+				t.used = i
+			}
+			return f
+		}
+	}
+
+	return math.NaN()
+}
+
+func (t *polarFallbackCalculator) nearestDay(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation, utc func(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation) float64) float64 {
+	for days := 1; days <= t.calendar.maxDays; days++ {
+		for _, offset := range []int{-days, days} {
+			date := gdt.NewGDateTime1(targetDateTime.ToTime(nil).AddDate(0, 0, offset))
+			if f := utc(date, geoLocation); !math.IsNaN(f) {
+				return f
+			}
+		}
+	}
+	return math.NaN()
+}
+
+func (t *polarFallbackCalculator) nearestLatitude(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation, utc func(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation) float64) float64 {
+	latitude := math.Abs(geoLocation.Latitude())
+	for latitude -= polarFallbackLatitudeStep; latitude >= 0; latitude -= polarFallbackLatitudeStep {
+		if f := utc(targetDateTime, geoLocationAtLatitude(geoLocation, math.Copysign(latitude, geoLocation.Latitude()))); !math.IsNaN(f) {
+			return f
+		}
+	}
+	return math.NaN()
+}
+
+func (t *polarFallbackCalculator) fixedLatitude(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation, utc func(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation) float64) float64 {
+	if math.Abs(geoLocation.Latitude()) <= t.calendar.fixedLatitude {
+		return math.NaN()
+	}
+	return utc(targetDateTime, geoLocationAtLatitude(geoLocation, math.Copysign(t.calendar.fixedLatitude, geoLocation.Latitude())))
+}
+
+/*
+solarNoonOrMidnight returns the utc solar noon if the center of the sun stays below the adjustedZenith all day, as its
+altitude is the highest at noon, otherwise the utc solar midnight, when its altitude is the lowest. The adjustedZenith
+includes the refraction and the solar radius of sunrise and sunset, as the calculator compares the sun to it.
+*/
+func solarNoonOrMidnight(targetDateTime gdt.GDateTime, geoLocation calculator.GeoLocation, adjustedZenith dimension.Degrees) float64 {
+	noon := calculator.UTCSolarNoon(targetDateTime, geoLocation)
+
+	noonTime := targetDateTime.D.ToTime(time.UTC).Add(time.Duration(noon * float64(time.Hour)))
+	noonAltitude := 90 - math.Abs(geoLocation.Latitude()-float64(calculator.SunDeclination(noonTime)))
+	if noonAltitude < float64(calculator.GeometricZenith-adjustedZenith) {
+		return noon
+	}
+	return calculator.UTCSolarMidnight(targetDateTime, geoLocation)
+}
+
+func geoLocationAtLatitude(geoLocation calculator.GeoLocation, latitude float64) calculator.GeoLocation {
+	return calculator.NewGeoLocation2(geoLocation.LocationName(), latitude, geoLocation.Longitude(), geoLocation.Elevation(), geoLocation.TimeZone())
+}
@@ -0,0 +1,285 @@
+package zmanim
+
+import (
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil/gdt"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"math"
+	"time"
+)
+
+/*
+DayDefinition the start and the end of the day of the shaos zmaniyos (temporal hours), such as sunrise and sunset of the
+GRA or alos and tzais of the MGA. The night is from the end of the day to the start of the next day.
+*/
+type DayDefinition struct {
+	Name  string
+	Start func(zc ZmanimCalendar) (tm time.Time, ok bool)
+	End   func(zc ZmanimCalendar) (tm time.Time, ok bool)
+}
+
+/*
+NewDayDefinition returns the DayDefinition of the custom start and end, such as the method expressions
+ZmanimCalendar.Alos and ZmanimCalendar.Tzais.
+A panic will be if the start or the end is nil.
+*/
+func NewDayDefinition(name string, start func(zc ZmanimCalendar) (tm time.Time, ok bool), end func(zc ZmanimCalendar) (tm time.Time, ok bool)) DayDefinition {
+	if start == nil {
+		panic("start == nil")
+	}
+	if end == nil {
+		panic("end == nil")
+	}
+	return DayDefinition{Name: name, Start: start, End: end}
+}
+
+/*
+DayDefinitionGRA returns the DayDefinition of the GRA, from ZmanimCalendar.Hanetz to ZmanimCalendar.Shkia, the day of
+ZmanimCalendar.ShaahZmanisGRA.
+*/
+func DayDefinitionGRA() DayDefinition {
+	return NewDayDefinition("GRA", ZmanimCalendar.Hanetz, ZmanimCalendar.Shkia)
+}
+
+/*
+DayDefinitionMGA returns the DayDefinition of the MGA, from ZmanimCalendar.Alos72 to ZmanimCalendar.Tzais72, the day of
+ZmanimCalendar.ShaahZmanisMGA.
+*/
+func DayDefinitionMGA() DayDefinition {
+	return NewDayDefinition("MGA", ZmanimCalendar.Alos72, ZmanimCalendar.Tzais72)
+}
+
+/*
+DayDefinitionByDegrees returns the DayDefinition from alos to tzais when the sun is the degrees below the geometric
+horizon, such as 16.1 or 19.8, see ZmanimCalendar.Alos3 and ZmanimCalendar.Tzais3.
+*/
+func DayDefinitionByDegrees(degrees dimension.Degrees) DayDefinition {
+	return NewDayDefinition(
+		fmt.Sprintf("%v degrees", degrees),
+		func(zc ZmanimCalendar) (tm time.Time, ok bool) { return zc.Alos3(degrees, 0, 0) },
+		func(zc ZmanimCalendar) (tm time.Time, ok bool) { return zc.Tzais3(degrees, 0, 0) },
+	)
+}
+
+/*
+DayDefinitionByMinutes returns the DayDefinition from alos the minutes before sunrise to tzais the minutes after
+sunset, such as 72, 90 or 120, see ZmanimCalendar.Alos3 and ZmanimCalendar.Tzais3.
+*/
+func DayDefinitionByMinutes(minutes gdt.GMinute) DayDefinition {
+	return NewDayDefinition(
+		fmt.Sprintf("%d minutes", minutes),
+		func(zc ZmanimCalendar) (tm time.Time, ok bool) { return zc.Alos3(0, time.Duration(minutes), 0) },
+		func(zc ZmanimCalendar) (tm time.Time, ok bool) { return zc.Tzais3(0, time.Duration(minutes), 0) },
+	)
+}
+
+/*
+ClockMode the hours of a ClockPosition.
+*/
+type ClockMode int
+
+const (
+	// ClockShaosZmaniyos 12 shaos zmaniyos (temporal hours) of the day, from the start to the end of the DayDefinition,
+	// and 12 of the night, from the end of the day to the start of the next day
+	ClockShaosZmaniyos ClockMode = iota
+	// ClockSunset the 24 equal hours of the Italian (or "Jewish") clock from the end of the DayDefinition of the previous
+	// date, such as sunset for DayDefinitionGRA, so that the Jewish date and the hour begin together
+	ClockSunset
+)
+
+func (m ClockMode) String() string {
+	switch m {
+	case ClockShaosZmaniyos:
+		return "ClockShaosZmaniyos"
+	case ClockSunset:
+		return "ClockSunset"
+	default:
+		return fmt.Sprintf("ClockMode(%d)", int(m))
+	}
+}
+
+/*
+ClockPosition an instant as the hours into the day or the night, see ZmanimCalendar.ClockPosition.
+*/
+type ClockPosition struct {
+	Mode ClockMode
+	// GDate the civil date of the day. The night, and the hours of ClockSunset, are of the GDate they precede, as the
+	// halachic day begins at night.
+	GDate gdt.GDate
+	// Night is if the instant is between the end of the day preceding the GDate and the start of the day of the GDate
+	Night bool
+	// Hours the hours into the day or the night for ClockShaosZmaniyos, from 0 to 12, or into the date for ClockSunset,
+	// from 0 to about 24
+	Hours float64
+	// HourLength the length of the hour, of the shaah zmanis for ClockShaosZmaniyos, and an hour for ClockSunset
+	HourLength time.Duration
+}
+
+/*
+Clock returns the Hours as h:mm, the minutes are the 60ths of the hour, such as 4:12.
+*/
+func (p ClockPosition) Clock() string {
+	minutes := int(math.Floor(p.Hours * 60))
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+/*
+String returns the Clock and the part of the day, such as "4:12 of the day", "3:00 of the night" or "13:30 from
+sunset".
+*/
+func (p ClockPosition) String() string {
+	switch {
+	case p.Mode == ClockSunset:
+		return p.Clock() + " from sunset"
+	case p.Night:
+		return p.Clock() + " of the night"
+	default:
+		return p.Clock() + " of the day"
+	}
+}
+
+/*
+ClockPosition returns the position of the instant tm in the mode of the DayDefinition day. The position may be of the
+GDate of the ZmanimCalendar, the one before or the one after, the civil date of tm at the GeoLocation decides which. The
+instant may be in any day or night around the civil date of tm, also where the day extends past midnight, such as the
+MGA tzais in the summer of the high latitudes.
+ok is false if the zmanim of the day, of the previous or the next day can't be calculated, such as in the polar day.
+A panic will be if the mode is unknown.
+*/
+func (t *zmanimCalendar) ClockPosition(tm time.Time, day DayDefinition, mode ClockMode) (position ClockPosition, ok bool) {
+	gDate := gdt.NewGDate1(tm.In(t.timeZone()))
+
+	// the starts and the ends of the days of the civil dates from the day before to the day after gDate
+	var starts, ends [3]time.Time
+	for i := range starts {
+		zc := t.dayCalendar().calendarOf(addDays(gDate, i-1))
+		if starts[i], ok = day.Start(zc); !ok {
+			return ClockPosition{}, false
+		}
+		if ends[i], ok = day.End(zc); !ok {
+			return ClockPosition{}, false
+		}
+	}
+
+	switch mode {
+	case ClockShaosZmaniyos:
+		for i := range starts {
+			if !tm.Before(starts[i]) && tm.Before(ends[i]) {
+				return ClockPosition{Mode: mode, GDate: addDays(gDate, i-1), Hours: hoursBetween(starts[i], tm, ends[i]), HourLength: ends[i].Sub(starts[i]) / 12}, true
+			}
+		}
+		for i := 1; i < len(starts); i++ {
+			if !tm.Before(ends[i-1]) && tm.Before(starts[i]) {
+				return ClockPosition{Mode: mode, GDate: addDays(gDate, i-1), Night: true, Hours: hoursBetween(ends[i-1], tm, starts[i]), HourLength: starts[i].Sub(ends[i-1]) / 12}, true
+			}
+		}
+		return ClockPosition{}, false
+	case ClockSunset:
+		for i := 1; i < len(ends); i++ {
+			if !tm.Before(ends[i-1]) && tm.Before(ends[i]) {
+				return ClockPosition{Mode: mode, GDate: addDays(gDate, i-1), Night: tm.Before(starts[i]), Hours: tm.Sub(ends[i-1]).Hours(), HourLength: time.Hour}, true
+			}
+		}
+		return ClockPosition{}, false
+	default:
+		panic(fmt.Sprintf("unknown ClockMode %d", mode))
+	}
+}
+
+/*
+ClockInstant returns the instant of the position in the DayDefinition day, the inverse of ClockPosition. The Night of
+the position is ignored for ClockSunset.
+ok is false if the zmanim of the GDate of the position, or of the previous day for the night, can't be calculated.
+A panic will be if the Hours are negative or not finite, or the Mode is unknown.
+*/
+func (t *zmanimCalendar) ClockInstant(position ClockPosition, day DayDefinition) (tm time.Time, ok bool) {
+	if !(position.Hours >= 0) || math.IsInf(position.Hours, 1) {
+		panic(fmt.Sprintf("hours %v is not a finite non-negative number", position.Hours))
+	}
+
+	switch position.Mode {
+	case ClockShaosZmaniyos:
+		zc := t.dayCalendar().calendarOf(position.GDate)
+		start, ok := day.Start(zc)
+		if !ok {
+			return time.Time{}, false
+		}
+		if !position.Night {
+			end, ok := day.End(zc)
+			if !ok {
+				return time.Time{}, false
+			}
+			return instantBetween(start, position.Hours, end), true
+		}
+		previousEnd, ok := day.End(t.dayCalendar().calendarOf(addDays(position.GDate, -1)))
+		if !ok {
+			return time.Time{}, false
+		}
+		return instantBetween(previousEnd, position.Hours, start), true
+	case ClockSunset:
+		previousEnd, ok := day.End(t.dayCalendar().calendarOf(addDays(position.GDate, -1)))
+		if !ok {
+			return time.Time{}, false
+		}
+		return previousEnd.Add(time.Duration(position.Hours * float64(time.Hour))), true
+	default:
+		panic(fmt.Sprintf("unknown ClockMode %d", position.Mode))
+	}
+}
+
+/*
+calendarOf returns the ZmanimCalendar of the gDate with the GeoLocation, the calculator.AstronomicalCalculator and the
+settings of this one.
+*/
+func (t *zmanimCalendar) calendarOf(gDate gdt.GDate) ZmanimCalendar {
+	zc := newZmanimCalendar()
+	zc.initAstronomicalCalendar(gdt.NewGDateTime(gDate, gdt.NewGTime0()), t.geoLocation, t.astronomicalCalculator)
+	zc.useElevation = t.useElevation
+	zc.candleLightingOffset = t.candleLightingOffset
+	zc.jewishDayOffset = t.jewishDayOffset
+	return zc
+}
+
+/*
+dayCalendar returns the calendar that embeds this one, see self, or this one if it is not embedded.
+*/
+func (t *zmanimCalendar) dayCalendar() dayCalendar {
+	if t.self != nil {
+		return t.self
+	}
+	return t
+}
+
+/*
+PreviousDay returns the ZmanimCalendar of the previous civil date, such as for the tzais of the night that ends on this
+date. It is of the type and the settings of this one, the ComplexZmanimCalendar of a ComplexZmanimCalendar.
+*/
+func (t *zmanimCalendar) PreviousDay() ZmanimCalendar {
+	return t.dayCalendar().calendarOf(addDays(t.gDateTime.D, -1))
+}
+
+/*
+NextDay returns the ZmanimCalendar of the next civil date, such as for the alos or the sunrise that ends the night that
+follows this date. It is of the type and the settings of this one, as PreviousDay.
+*/
+func (t *zmanimCalendar) NextDay() ZmanimCalendar {
+	return t.dayCalendar().calendarOf(addDays(t.gDateTime.D, 1))
+}
+
+func addDays(gDate gdt.GDate, days int) gdt.GDate {
+	return gdt.NewGDate2(gDate.ToAbsDate() + gdt.GDay(days))
+}
+
+/*
+hoursBetween returns the twelfths of the interval from the start to the end that tm is after the start.
+*/
+func hoursBetween(start time.Time, tm time.Time, end time.Time) float64 {
+	return 12 * float64(tm.Sub(start)) / float64(end.Sub(start))
+}
+
+/*
+instantBetween returns the instant the hours twelfths of the interval from the start to the end after the start.
+*/
+func instantBetween(start time.Time, hours float64, end time.Time) time.Time {
+	return start.Add(time.Duration(hours / 12 * float64(end.Sub(start))))
+}
@@ -0,0 +1,192 @@
+package tzlookup
+
+import (
+	"github.com/vlipovetskii/go-zmanim/helper"
+	"github.com/vlipovetskii/go-zmanim/helper/assert"
+	"strings"
+	"testing"
+)
+
+func TestNauticalTimeZoneName(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	for _, test := range []struct {
+		longitude float64
+		want      string
+	}{
+		{0, "Etc/GMT"},
+		{7.4, "Etc/GMT"},
+		{-7.4, "Etc/GMT"},
+		{30, "Etc/GMT-2"},
+		{-40, "Etc/GMT+3"},
+		{179.9, "Etc/GMT-12"},
+		{-179.9, "Etc/GMT+12"},
+	} {
+		assert.Equal(t, tag, test.want, NauticalTimeZoneName(test.longitude))
+	}
+}
+
+func TestDefaultResolver(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	resolver := DefaultResolver()
+	for _, test := range []struct {
+		latitude, longitude float64
+		want                string
+	}{
+		// the Kosel
+		{31.7767, 35.2345, "Asia/Jerusalem"},
+		// Lakewood, NJ
+		{40.0960, -74.2176, "America/New_York"},
+		// Los Angeles
+		{34.05, -118.25, "America/Los_Angeles"},
+		// Tokyo
+		{35.68, 139.77, "Asia/Tokyo"},
+		// Kiryat Arba uses the Israeli time, Hebron the Palestinian one
+		{31.533, 35.117, "Asia/Jerusalem"},
+		{31.524, 35.108, "Asia/Hebron"},
+		// the middle of the Atlantic and of the Pacific
+		{30, -40, "Etc/GMT+3"},
+		{0, -140, "Etc/GMT+9"},
+	} {
+		name, ok := resolver.TimeZoneName(test.latitude, test.longitude)
+		assert.True(t, tag, ok)
+		assert.Equal(t, tag, test.want, name)
+	}
+}
+
+const testGeoJSON = `{
+	"type": "FeatureCollection",
+	"features": [
+		{
+			"type": "Feature",
+			"properties": {"tzid": "Test/Square"},
+			"geometry": {"type": "Polygon", "coordinates": [
+				[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]],
+				[[4, 4], [6, 4], [6, 6], [4, 6], [4, 4]]
+			]}
+		},
+		{
+			"type": "Feature",
+			"properties": {"tzid": "Test/Islands"},
+			"geometry": {"type": "MultiPolygon", "coordinates": [
+				[[[20, 0], [21, 0], [21, 1], [20, 1], [20, 0]]],
+				[[[4.5, 4.5], [5.5, 4.5], [5.5, 5.5], [4.5, 5.5], [4.5, 4.5]]]
+			]}
+		}
+	]
+}`
+
+func TestReadGeoJSON(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	resolver, err := ReadGeoJSON(strings.NewReader(testGeoJSON))
+	assert.Equal(t, tag, nil, err)
+
+	for _, test := range []struct {
+		latitude, longitude float64
+		want                string
+		ok                  bool
+	}{
+		{1, 1, "Test/Square", true},
+		{9, 5, "Test/Square", true},
+		// in the hole of the square, and in the island in the hole
+		{4.2, 4.2, "", false},
+		{5, 5, "Test/Islands", true},
+		{0.5, 20.5, "Test/Islands", true},
+		{-1, 5, "", false},
+	} {
+		name, ok := resolver.TimeZoneName(test.latitude, test.longitude)
+		assert.Equal(t, tag, test.ok, ok)
+		assert.Equal(t, tag, test.want, name)
+	}
+
+	// the polygons before the nautical time zones
+	chain := NewChainResolver(resolver, NewNauticalResolver())
+	name, _ := chain.TimeZoneName(1, 1)
+	assert.Equal(t, tag, "Test/Square", name)
+	name, _ = chain.TimeZoneName(4.2, 4.2)
+	assert.Equal(t, tag, "Etc/GMT", name)
+
+	for _, geoJSON := range []string{
+		`{"type": "Feature"}`,
+		`{"type": "FeatureCollection", "features": [{"properties": {}, "geometry": {"type": "Polygon", "coordinates": []}}]}`,
+		`{"type": "FeatureCollection", "features": [{"properties": {"tzid": "A/B"}, "geometry": {"type": "Point", "coordinates": [0, 0]}}]}`,
+		`{"type": "FeatureCollection", "features": [{"properties": {"tzid": "A/B"}, "geometry": {"type": "Polygon", "coordinates": [[[0]]]}}]}`,
+		`not json`,
+	} {
+		_, err := ReadGeoJSON(strings.NewReader(geoJSON))
+		assert.True(t, tag+" "+geoJSON, err != nil)
+	}
+}
+
+func TestDefaultResolverPolygonsFirst(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	// the embedded polygons are read
+	assert.True(t, tag, DefaultPolygonResolver() != nil)
+
+	polygons, err := ReadGeoJSON(strings.NewReader(`{"type": "FeatureCollection", "features": [{
+		"type": "Feature",
+		"properties": {"tzid": "Test/Kosel"},
+		"geometry": {"type": "Polygon", "coordinates": [[[35.2, 31.7], [35.3, 31.7], [35.3, 31.8], [35.2, 31.8], [35.2, 31.7]]]}
+	}]}`))
+	assert.Equal(t, tag, nil, err)
+	resolver := defaultResolver(polygons)
+
+	// the polygons before the nearest city
+	name, ok := resolver.TimeZoneName(31.7767, 35.2345)
+	assert.True(t, tag, ok)
+	assert.Equal(t, tag, "Test/Kosel", name)
+	// the nearest city and the nautical time zone outside the polygons
+	name, _ = resolver.TimeZoneName(40.0960, -74.2176)
+	assert.Equal(t, tag, "America/New_York", name)
+	name, _ = resolver.TimeZoneName(30, -40)
+	assert.Equal(t, tag, "Etc/GMT+3", name)
+}
+
+func TestDefaultPolygonResolverBorders(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	if len(DefaultPolygonResolver().(*polygonResolver).zones) == 0 {
+		t.Skip("the embedded timezones.geojson.gz has no polygons, run go generate ./zmanim/tzlookup")
+	}
+
+	// the neighbouring cities across the borders, resolved by the polygons alone
+	for _, test := range []struct {
+		latitude  float64
+		longitude float64
+		timeZone  string
+	}{
+		// Eilat and Aqaba across the Gulf of Aqaba
+		{29.5577, 34.9519, "Asia/Jerusalem"},
+		{29.5321, 35.0063, "Asia/Amman"},
+		// Detroit and Windsor across the Detroit River
+		{42.3314, -83.0458, "America/Detroit"},
+		{42.3149, -83.0364, "America/Toronto"},
+	} {
+		name, ok := DefaultPolygonResolver().TimeZoneName(test.latitude, test.longitude)
+		assert.True(t, tag, ok)
+		assert.Equal(t, tag, test.timeZone, name)
+	}
+}
+
+func TestNewGeoLocation(t *testing.T) {
+	tag := helper.CurrentFuncName()
+
+	geoLocation := NewGeoLocation1("Kosel", 31.7767, 35.2345)
+	assert.Equal(t, tag, "Asia/Jerusalem", geoLocation.TimeZone().String())
+
+	geoLocation = NewGeoLocation2("Lakewood", 40.0960, -74.2176, 20)
+	assert.Equal(t, tag, "America/New_York", geoLocation.TimeZone().String())
+
+	geoLocation, err := ParseGeoLocation("Tokyo", "35°40′N 139°46′E")
+	assert.Equal(t, tag, nil, err)
+	assert.Equal(t, tag, "Asia/Tokyo", geoLocation.TimeZone().String())
+
+	_, err = ParseGeoLocation("", "nowhere")
+	assert.True(t, tag, err != nil)
+
+	defer assert.Raises(t, tag)()
+	NewNearestCityResolver(nil, DefaultMaxCityDistance)
+}
@@ -0,0 +1,255 @@
+//go:build ignore
+
+/*
+gen_timezones generates timezones.geojson.gz, the embedded polygons of DefaultPolygonResolver, from the
+timezones.geojson.zip of a release of the timezone-boundary-builder: https://github.com/evansiroky/timezone-boundary-builder
+(the zones without the oceans, which the nautical time zones cover). The rings are simplified by the Douglas-Peucker
+algorithm to the tolerance in degrees, and the rings that collapse are dropped.
+
+	go generate ./zmanim/tzlookup
+	go run gen_timezones.go -release 2024a -tolerance 0.005
+	go run gen_timezones.go -zip timezones.geojson.zip
+*/
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type feature struct {
+	Type       string            `json:"type"`
+	Properties map[string]string `json:"properties"`
+	Geometry   geometry          `json:"geometry"`
+}
+
+type geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+func main() {
+	release := flag.String("release", "2024a", "the release of the timezone-boundary-builder")
+	zipPath := flag.String("zip", "", "the local timezones.geojson.zip instead of the download of the release")
+	tolerance := flag.Float64("tolerance", 0.005, "the tolerance of the simplification in degrees")
+	output := flag.String("o", "timezones.geojson.gz", "the output file")
+	flag.Parse()
+
+	data, err := readZip(*release, *zipPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var collection struct {
+		Features []struct {
+			Properties map[string]any `json:"properties"`
+			Geometry   geometry       `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &collection); err != nil {
+		log.Fatal(err)
+	}
+
+	simplified := featureCollection{Type: "FeatureCollection"}
+	for i, f := range collection.Features {
+		name, _ := f.Properties["tzid"].(string)
+		if name == "" {
+			log.Fatalf("feature %d: no tzid property", i)
+		}
+
+		var polygons [][][][2]float64
+		switch f.Geometry.Type {
+		case "Polygon":
+			var polygon [][][2]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygon); err != nil {
+				log.Fatalf("feature %d: %v", i, err)
+			}
+			polygons = [][][][2]float64{polygon}
+		case "MultiPolygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygons); err != nil {
+				log.Fatalf("feature %d: %v", i, err)
+			}
+		default:
+			log.Fatalf("feature %d: the geometry type %q is not Polygon or MultiPolygon", i, f.Geometry.Type)
+		}
+
+		var multiPolygon [][][][2]float64
+		for _, polygon := range polygons {
+			var rings [][][2]float64
+			for j, ring := range polygon {
+				ring = simplifyRing(ring, *tolerance)
+				if len(ring) < 4 {
+					if j == 0 {
+						// the outer ring collapsed, the polygon is smaller than the tolerance
+						break
+					}
+					continue
+				}
+				rings = append(rings, ring)
+			}
+			if len(rings) > 0 {
+				multiPolygon = append(multiPolygon, rings)
+			}
+		}
+		if len(multiPolygon) == 0 {
+			log.Printf("%s: all the polygons are smaller than the tolerance", name)
+			continue
+		}
+
+		coordinates, err := json.Marshal(multiPolygon)
+		if err != nil {
+			log.Fatal(err)
+		}
+		simplified.Features = append(simplified.Features, feature{
+			Type:       "Feature",
+			Properties: map[string]string{"tzid": name},
+			Geometry:   geometry{Type: "MultiPolygon", Coordinates: coordinates},
+		})
+	}
+
+	var buf bytes.Buffer
+	w, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err := json.NewEncoder(w).Encode(simplified); err != nil {
+		log.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%d zones, %d bytes", len(simplified.Features), buf.Len())
+}
+
+/*
+readZip returns the GeoJSON of the timezones.geojson.zip of the release, or of the zipPath if it is not empty.
+*/
+func readZip(release string, zipPath string) ([]byte, error) {
+	var data []byte
+	var err error
+	if zipPath != "" {
+		data, err = os.ReadFile(zipPath)
+	} else {
+		data, err = download(fmt.Sprintf("https://github.com/evansiroky/timezone-boundary-builder/releases/download/%s/timezones.geojson.zip", release))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range archive.File {
+		if !strings.EqualFold(filepath.Ext(file.Name), ".json") && !strings.EqualFold(filepath.Ext(file.Name), ".geojson") {
+			continue
+		}
+		r, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	}
+	return nil, fmt.Errorf("no GeoJSON in the zip")
+}
+
+func download(url string) ([]byte, error) {
+	response, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, response.Status)
+	}
+	return io.ReadAll(response.Body)
+}
+
+/*
+simplifyRing returns the closed ring simplified by the Douglas-Peucker algorithm to the tolerance, the coordinates
+rounded to 1/10 of the tolerance.
+*/
+func simplifyRing(ring [][2]float64, tolerance float64) [][2]float64 {
+	if len(ring) < 4 {
+		return nil
+	}
+	// the ring is split at the vertex farthest from the first one, so that the two halves have distinct end points
+	farthest, maxDistance := 0, -1.0
+	for i, point := range ring {
+		if d := math.Hypot(point[0]-ring[0][0], point[1]-ring[0][1]); d > maxDistance {
+			farthest, maxDistance = i, d
+		}
+	}
+	if farthest == 0 {
+		return nil
+	}
+
+	keep := make([]bool, len(ring))
+	keep[0], keep[farthest], keep[len(ring)-1] = true, true, true
+	douglasPeucker(ring, 0, farthest, tolerance, keep)
+	douglasPeucker(ring, farthest, len(ring)-1, tolerance, keep)
+
+	precision := math.Pow(10, math.Ceil(-math.Log10(tolerance/10)))
+	var simplified [][2]float64
+	for i, point := range ring {
+		if !keep[i] {
+			continue
+		}
+		point = [2]float64{math.Round(point[0]*precision) / precision, math.Round(point[1]*precision) / precision}
+		if n := len(simplified); n > 0 && simplified[n-1] == point {
+			continue
+		}
+		simplified = append(simplified, point)
+	}
+	if simplified[0] != simplified[len(simplified)-1] {
+		simplified = append(simplified, simplified[0])
+	}
+	return simplified
+}
+
+/*
+douglasPeucker marks to keep the vertices between the first and the last one that are farther than the tolerance from
+the segment between them.
+*/
+func douglasPeucker(ring [][2]float64, first int, last int, tolerance float64, keep []bool) {
+	for last-first > 1 {
+		farthest, maxDistance := 0, 0.0
+		for i := first + 1; i < last; i++ {
+			if d := segmentDistance(ring[i], ring[first], ring[last]); d > maxDistance {
+				farthest, maxDistance = i, d
+			}
+		}
+		if maxDistance <= tolerance {
+			return
+		}
+		keep[farthest] = true
+		douglasPeucker(ring, first, farthest, tolerance, keep)
+		first = farthest
+	}
+}
+
+func segmentDistance(point [2]float64, a [2]float64, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	if dx == 0 && dy == 0 {
+		return math.Hypot(point[0]-a[0], point[1]-a[1])
+	}
+	t := math.Max(0, math.Min(1, ((point[0]-a[0])*dx+(point[1]-a[1])*dy)/(dx*dx+dy*dy)))
+	return math.Hypot(point[0]-a[0]-t*dx, point[1]-a[1]-t*dy)
+}
@@ -0,0 +1,265 @@
+package tzlookup
+
+//go:generate go run gen_timezones.go
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+/*
+timezonesGeoJSONGz the embedded gzipped GeoJSON of the time zone polygons generated by gen_timezones.go, an empty
+FeatureCollection in the repository.
+*/
+//go:embed timezones.geojson.gz
+var timezonesGeoJSONGz []byte
+
+var (
+	defaultPolygonResolver     Resolver
+	defaultPolygonResolverOnce sync.Once
+)
+
+/*
+DefaultPolygonResolver returns the Resolver of the time zone polygons embedded in timezones.geojson.gz. The repository
+ships it as an empty FeatureCollection, so that the DefaultPolygonResolver resolves no location until the polygons are
+generated before the build with go generate ./zmanim/tzlookup, which runs gen_timezones.go: it downloads the polygons
+of a release of the [timezone-boundary-builder]: https://github.com/evansiroky/timezone-boundary-builder without the
+oceans, and simplifies them to the tolerance of 0.005 deg, about 500 meters, so that the locations nearer to a time
+zone boundary may be resolved to the neighbouring zone. For the exact boundaries load the full GeoJSON with LoadGeoJSON.
+*/
+func DefaultPolygonResolver() Resolver {
+	defaultPolygonResolverOnce.Do(func() {
+		r, err := gzip.NewReader(bytes.NewReader(timezonesGeoJSONGz))
+		if err != nil {
+			panic(err.Error())
+		}
+		t, err := ReadGeoJSON(r)
+		if err != nil {
+			panic(err.Error())
+		}
+		defaultPolygonResolver = t
+	})
+	return defaultPolygonResolver
+}
+
+/*
+Point a vertex of a Ring in degrees.
+*/
+type Point struct {
+	Longitude float64
+	Latitude  float64
+}
+
+/*
+Ring a closed ring of the vertices of a Polygon, the last vertex is joined to the first one.
+*/
+type Ring []Point
+
+/*
+Polygon the outer Ring, followed by the rings of the holes, as in GeoJSON.
+*/
+type Polygon []Ring
+
+/*
+Zone the polygons of the area of the IANA time zone of the Name.
+*/
+type Zone struct {
+	Name     string
+	Polygons []Polygon
+}
+
+type boundingBox struct {
+	minLatitude, maxLatitude, minLongitude, maxLongitude float64
+}
+
+type polygonResolver struct {
+	zones []Zone
+	// boxes the bounding boxes of the outer rings of the polygons by the zone and the polygon
+	boxes [][]boundingBox
+}
+
+func newPolygonResolver() *polygonResolver {
+	return &polygonResolver{}
+}
+
+func (t *polygonResolver) initPolygonResolver(zones []Zone) {
+	t.zones = append([]Zone(nil), zones...)
+	t.boxes = make([][]boundingBox, len(zones))
+	for i, zone := range zones {
+		t.boxes[i] = make([]boundingBox, len(zone.Polygons))
+		for j, polygon := range zone.Polygons {
+			t.boxes[i][j] = ringBoundingBox(polygon[0])
+		}
+	}
+}
+
+/*
+NewPolygonResolver returns the Resolver of the zone whose polygons contain the location, the first one if they overlap.
+A panic will be if a polygon has no outer ring.
+*/
+func NewPolygonResolver(zones []Zone) Resolver {
+	for _, zone := range zones {
+		for _, polygon := range zone.Polygons {
+			if len(polygon) == 0 {
+				panic(fmt.Sprintf("a polygon of %s has no outer ring", zone.Name))
+			}
+		}
+	}
+
+	t := newPolygonResolver()
+
+	t.initPolygonResolver(zones)
+
+	return t
+}
+
+func (t *polygonResolver) TimeZoneName(latitude float64, longitude float64) (name string, ok bool) {
+	for i, zone := range t.zones {
+		for j, polygon := range zone.Polygons {
+			if t.boxes[i][j].contains(latitude, longitude) && polygon.contains(latitude, longitude) {
+				return zone.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+/*
+ReadGeoJSON reads the Resolver of the GeoJSON FeatureCollection of the Polygon and MultiPolygon features with the time
+zone name in the tzid property, the format of the timezone-boundary-builder.
+*/
+func ReadGeoJSON(r io.Reader) (Resolver, error) {
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Properties map[string]any `json:"properties"`
+			Geometry   struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.NewDecoder(r).Decode(&collection); err != nil {
+		return nil, err
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("the GeoJSON type %q is not FeatureCollection", collection.Type)
+	}
+
+	zones := make([]Zone, 0, len(collection.Features))
+	for i, feature := range collection.Features {
+		name, _ := feature.Properties["tzid"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("feature %d: no tzid property", i)
+		}
+
+		var coordinates [][][][]float64
+		switch feature.Geometry.Type {
+		case "Polygon":
+			var polygon [][][]float64
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &polygon); err != nil {
+				return nil, fmt.Errorf("feature %d: %w", i, err)
+			}
+			coordinates = [][][][]float64{polygon}
+		case "MultiPolygon":
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &coordinates); err != nil {
+				return nil, fmt.Errorf("feature %d: %w", i, err)
+			}
+		default:
+			return nil, fmt.Errorf("feature %d: the geometry type %q is not Polygon or MultiPolygon", i, feature.Geometry.Type)
+		}
+
+		zone := Zone{Name: name}
+		for _, polygonCoordinates := range coordinates {
+			if len(polygonCoordinates) == 0 {
+				return nil, fmt.Errorf("feature %d: a polygon has no outer ring", i)
+			}
+			polygon := make(Polygon, len(polygonCoordinates))
+			for j, ringCoordinates := range polygonCoordinates {
+				polygon[j] = make(Ring, len(ringCoordinates))
+				for k, position := range ringCoordinates {
+					if len(position) < 2 {
+						return nil, fmt.Errorf("feature %d: the position %v is not longitude, latitude", i, position)
+					}
+					polygon[j][k] = Point{Longitude: position[0], Latitude: position[1]}
+				}
+			}
+			zone.Polygons = append(zone.Polygons, polygon)
+		}
+		zones = append(zones, zone)
+	}
+
+	return NewPolygonResolver(zones), nil
+}
+
+/*
+LoadGeoJSON loads the Resolver from the GeoJSON file, see ReadGeoJSON.
+*/
+func LoadGeoJSON(path string) (Resolver, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadGeoJSON(file)
+}
+
+/*
+contains returns if the location is inside the outer ring and outside the holes.
+*/
+func (p Polygon) contains(latitude float64, longitude float64) bool {
+	if !p[0].contains(latitude, longitude) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.contains(latitude, longitude) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+contains casts the ray from the location to the east and counts the edges it crosses.
+*/
+func (r Ring) contains(latitude float64, longitude float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a.Latitude > latitude) != (b.Latitude > latitude) &&
+			longitude < (b.Longitude-a.Longitude)*(latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func ringBoundingBox(ring Ring) boundingBox {
+	box := boundingBox{minLatitude: 90, maxLatitude: -90, minLongitude: 180, maxLongitude: -180}
+	for _, point := range ring {
+		if point.Latitude < box.minLatitude {
+			box.minLatitude = point.Latitude
+		}
+		if point.Latitude > box.maxLatitude {
+			box.maxLatitude = point.Latitude
+		}
+		if point.Longitude < box.minLongitude {
+			box.minLongitude = point.Longitude
+		}
+		if point.Longitude > box.maxLongitude {
+			box.maxLongitude = point.Longitude
+		}
+	}
+	return box
+}
+
+func (b boundingBox) contains(latitude float64, longitude float64) bool {
+	return latitude >= b.minLatitude && latitude <= b.maxLatitude && longitude >= b.minLongitude && longitude <= b.maxLongitude
+}
@@ -0,0 +1,175 @@
+/*
+Package tzlookup resolves the IANA time zone of a latitude and a longitude offline, so that a calculator.GeoLocation can
+be made of the coordinates alone. The DefaultResolver takes the time zone of the embedded time zone polygons (see
+DefaultPolygonResolver, none until they are generated with go generate), then the time zone of the nearest city of the
+embedded gazetteer.DefaultGazetteer, and the nautical time zone at sea. For the exact time zone boundaries load the full
+GeoJSON of the [timezone-boundary-builder]: https://github.com/evansiroky/timezone-boundary-builder with LoadGeoJSON and
+chain it before the DefaultResolver with NewChainResolver.
+*/
+package tzlookup
+
+import (
+	"fmt"
+	"github.com/vlipovetskii/go-zmanim/hebrewcalendar/timeutil"
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"github.com/vlipovetskii/go-zmanim/zmanim/dimension"
+	"github.com/vlipovetskii/go-zmanim/zmanim/gazetteer"
+	"math"
+	"time"
+)
+
+/*
+DefaultMaxCityDistance the distance in meters from the nearest city of the gazetteer beyond which the DefaultResolver
+falls back to the nautical time zone, if the location is outside the polygons of the DefaultPolygonResolver.
+*/
+const DefaultMaxCityDistance = 300000
+
+/*
+Resolver resolves the IANA time zone name, such as Asia/Jerusalem, of a latitude and a longitude.
+*/
+type Resolver interface {
+	// TimeZoneName returns the IANA time zone name of the location, ok is false if the Resolver doesn't know it
+	TimeZoneName(latitude float64, longitude float64) (name string, ok bool)
+}
+
+/*
+NauticalTimeZoneName returns the nautical time zone of the longitude, the Etc/GMT zone of the whole hours of the 15 deg
+wide band centered on the meridian of the zone, such as Etc/GMT-2 for 30 deg east. Note that the sign of the Etc zones
+is inverted, Etc/GMT-2 is 2 hours ahead of GMT.
+*/
+func NauticalTimeZoneName(longitude float64) string {
+	hours := int(math.Round(longitude / 15))
+	switch {
+	case hours == 0:
+		return "Etc/GMT"
+	case hours > 0:
+		return fmt.Sprintf("Etc/GMT-%d", hours)
+	default:
+		return fmt.Sprintf("Etc/GMT+%d", -hours)
+	}
+}
+
+type nauticalResolver struct{}
+
+/*
+NewNauticalResolver returns the Resolver of the NauticalTimeZoneName, that knows every location.
+*/
+func NewNauticalResolver() Resolver {
+	return nauticalResolver{}
+}
+
+func (nauticalResolver) TimeZoneName(_ float64, longitude float64) (name string, ok bool) {
+	return NauticalTimeZoneName(longitude), true
+}
+
+type nearestCityResolver struct {
+	gazetteer   gazetteer.Gazetteer
+	maxDistance float64
+}
+
+/*
+NewNearestCityResolver returns the Resolver of the time zone of the nearest city of the gazetteer within the
+maxDistance in meters. It is right in the most of the populated areas, but may be wrong near the time zone boundaries
+that are nearer than the cities of the gazetteer.
+A panic will be if the gazetteer is nil.
+*/
+func NewNearestCityResolver(gazetteer gazetteer.Gazetteer, maxDistance float64) Resolver {
+	if gazetteer == nil {
+		panic("gazetteer == nil")
+	}
+	return &nearestCityResolver{gazetteer: gazetteer, maxDistance: maxDistance}
+}
+
+func (t *nearestCityResolver) TimeZoneName(latitude float64, longitude float64) (name string, ok bool) {
+	nearest := t.gazetteer.Nearest(latitude, longitude, 1)
+	if len(nearest) == 0 || nearest[0].Distance > t.maxDistance {
+		return "", false
+	}
+	return nearest[0].TimeZone, true
+}
+
+type chainResolver []Resolver
+
+/*
+NewChainResolver returns the Resolver of the first of the resolvers that knows the location.
+*/
+func NewChainResolver(resolvers ...Resolver) Resolver {
+	return chainResolver(append([]Resolver(nil), resolvers...))
+}
+
+func (t chainResolver) TimeZoneName(latitude float64, longitude float64) (name string, ok bool) {
+	for _, resolver := range t {
+		if name, ok = resolver.TimeZoneName(latitude, longitude); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+/*
+DefaultResolver returns the Resolver of the polygons of the DefaultPolygonResolver, falling back to the nearest city of
+the gazetteer.DefaultGazetteer within the DefaultMaxCityDistance outside them, and to the nautical time zone beyond it.
+*/
+func DefaultResolver() Resolver {
+	return defaultResolver(DefaultPolygonResolver())
+}
+
+/*
+defaultResolver returns the DefaultResolver of the polygons passed in.
+*/
+func defaultResolver(polygons Resolver) Resolver {
+	return NewChainResolver(polygons, NewNearestCityResolver(gazetteer.DefaultGazetteer(), DefaultMaxCityDistance), NewNauticalResolver())
+}
+
+/*
+TimeZone returns the time.Location of the resolver at the latitude and the longitude, the nautical time zone if the
+resolver doesn't know it.
+*/
+func TimeZone(resolver Resolver, latitude float64, longitude float64) (*time.Location, error) {
+	name, ok := resolver.TimeZoneName(latitude, longitude)
+	if !ok {
+		name = NauticalTimeZoneName(longitude)
+	}
+	return time.LoadLocation(name)
+}
+
+/*
+NewGeoLocation1 returns the calculator.GeoLocation of the coordinates in the time zone of the DefaultResolver, see
+calculator.NewGeoLocation1.
+*/
+func NewGeoLocation1(name string, latitude float64, longitude float64) calculator.GeoLocation {
+	return calculator.NewGeoLocation1(name, latitude, longitude, defaultTimeZone(latitude, longitude))
+}
+
+/*
+NewGeoLocation2 returns the calculator.GeoLocation of the coordinates in the time zone of the DefaultResolver, see
+calculator.NewGeoLocation2.
+*/
+func NewGeoLocation2(name string, latitude float64, longitude float64, elevation dimension.Meters) calculator.GeoLocation {
+	return calculator.NewGeoLocation2(name, latitude, longitude, elevation, defaultTimeZone(latitude, longitude))
+}
+
+/*
+ParseGeoLocation returns the calculator.GeoLocation of the coordinates in the time zone of the DefaultResolver, see
+calculator.ParseGeoLocation.
+*/
+func ParseGeoLocation(name string, coordinates string) (calculator.GeoLocation, error) {
+	latitude, longitude, err := calculator.ParseCoordinates(coordinates)
+	if err != nil {
+		return nil, err
+	}
+	timeZone, err := TimeZone(DefaultResolver(), latitude, longitude)
+	if err != nil {
+		return nil, err
+	}
+	return calculator.NewGeoLocation1(name, latitude, longitude, timeZone), nil
+}
+
+/*
+defaultTimeZone returns the time zone of the DefaultResolver.
+A panic will be if it can't be loaded, on the systems without the IANA time zone database import time/tzdata.
+*/
+func defaultTimeZone(latitude float64, longitude float64) *time.Location {
+	name, _ := DefaultResolver().TimeZoneName(latitude, longitude)
+	return timeutil.LoadLocationOrPanic(name)
+}
@@ -0,0 +1,90 @@
+package zmanim
+
+import (
+	"github.com/vlipovetskii/go-zmanim/zmanim/calculator"
+	"time"
+)
+
+/*
+visibleSunStep the step of the search of the visible sunrise and sunset, short enough not to miss the sun crossing a
+narrow gap of the calculator.HorizonProfile.
+*/
+const visibleSunStep = 2 * time.Minute
+
+/*
+visibleSunrise returns the first moment of the day the upper limb of the sun clears the calculator.HorizonProfile of
+the calculator.GeoLocation, from the solar midnight to the solar noon. ok is false if the sun doesn't clear it.
+*/
+func (t *astronomicalCalendar) visibleSunrise() (tm time.Time, ok bool) {
+	noon := t.solarNoon()
+	return t.visibleSunCrossing(noon.Add(-12*time.Hour), noon, true)
+}
+
+/*
+visibleSunset returns the last moment of the day the upper limb of the sun clears the calculator.HorizonProfile of the
+calculator.GeoLocation, from the solar noon to the solar midnight. ok is false if the sun doesn't clear it.
+*/
+func (t *astronomicalCalendar) visibleSunset() (tm time.Time, ok bool) {
+	noon := t.solarNoon()
+	return t.visibleSunCrossing(noon, noon.Add(12*time.Hour), false)
+}
+
+func (t *astronomicalCalendar) solarNoon() time.Time {
+	adjustedDateTime := t.adjustedDateTime()
+	noon := calculator.UTCSolarNoon(adjustedDateTime, t.GeoLocation())
+	return adjustedDateTime.D.ToTime(time.UTC).Add(time.Duration(noon * float64(time.Hour)))
+}
+
+/*
+visibleSunCrossing returns the first rising (or the last setting) of the upper limb of the sun over the
+calculator.HorizonProfile from start to end.
+*/
+func (t *astronomicalCalendar) visibleSunCrossing(start time.Time, end time.Time, rising bool) (tm time.Time, ok bool) {
+	var crossing time.Time
+	previous, previousClears := start, t.sunClearsHorizon(start)
+	for current := start.Add(visibleSunStep); !current.After(end); current = current.Add(visibleSunStep) {
+		currentClears := t.sunClearsHorizon(current)
+		if rising && !previousClears && currentClears {
+			return t.bisectVisibleSun(previous, current, rising).In(t.timeZone()), true
+		}
+		if !rising && previousClears && !currentClears {
+			crossing, ok = t.bisectVisibleSun(previous, current, rising), true
+		}
+		previous, previousClears = current, currentClears
+	}
+	if !ok {
+		return time.Time{}, false
+	}
+	return crossing.In(t.timeZone()), true
+}
+
+/*
+bisectVisibleSun returns the moment the sun clears (or stops clearing) the horizon from before to after, to the
+millisecond.
+*/
+func (t *astronomicalCalendar) bisectVisibleSun(before time.Time, after time.Time, rising bool) time.Time {
+	for after.Sub(before) > time.Millisecond {
+		middle := before.Add(after.Sub(before) / 2)
+		if t.sunClearsHorizon(middle) == rising {
+			after = middle
+		} else {
+			before = middle
+		}
+	}
+	return after.Round(time.Millisecond)
+}
+
+/*
+sunClearsHorizon returns if the apparent upper limb of the sun is above the calculator.HorizonProfile at the time tm.
+The position of the sun is the one of the calculator.AstronomicalCalculator, see calculator.SunHorizontalPositionOf,
+and the refraction is the one of its calculator.RefractionModel at the altitude of the horizon.
+*/
+func (t *astronomicalCalendar) sunClearsHorizon(tm time.Time) bool {
+	altitude, azimuth := calculator.SunHorizontalPositionOf(t.astronomicalCalculator, tm, t.GeoLocation())
+	horizon := calculator.HorizonProfileOf(t.GeoLocation()).Altitude(azimuth)
+
+	solarRadius := calculator.SolarRadiusOf(t.astronomicalCalculator, tm)
+	refraction := calculator.RefractionModelOf(t.astronomicalCalculator).Refraction(horizon)
+
+	return altitude+solarRadius.InDegrees()+refraction.InDegrees() >= horizon
+}
@@ -47,13 +47,16 @@ the author of Luach Ikvei Hayom who spent a considerable amount of time reviewin
 documentation in this library.
 Disclaimer: I did my best to get accurate results, but please double-check before relying on these
 zmanim for halacha lema'aseh.
+Note: As for the AstronomicalCalendar, AstronomicalCalendar.ZmanWithKind flags the zmanim in the hour repeated when the
+clocks are set back, such as the night zmanim of ShaahZmanisNightBasedZman.
 */
-
 type ZmanimCalendar interface {
 	AstronomicalCalendar
 	// IsUseElevation and other getters
 	//
 	IsUseElevation() bool
+	CandleLightingOffset() gdt.GMinuteF64
+	JewishDayOffset() int
 	// Hanetz and other ...
 	//
 	Hanetz() (tm time.Time, ok bool)
@@ -76,14 +79,33 @@ type ZmanimCalendar interface {
 	PlagHamincha() (tm time.Time, ok bool)
 	CandleLighting() (tm time.Time, ok bool)
 	ShaahZmanis(sunrise time.Time, sunset time.Time) gdt.GMillisecond
+	ShaahZmanisBasedZman(startOfDay time.Time, endOfDay time.Time, hours gdt.GHourH64) time.Time
 	ShaahZmanisGRA() (i gdt.GMillisecond, ok bool)
 	ShaahZmanisMGA() (i gdt.GMillisecond, ok bool)
 	ShaahZmanisByDegreesAndOffset(degrees dimension.Degrees, offsetMinutes time.Duration) (i gdt.GMillisecond, ok bool)
 	IsAssurBemlacha(currentTime time.Time, tzais time.Time, inIsrael bool) bool
 	AlosHashachar() (tm time.Time, ok bool)
+	// ClockPosition returns the instant tm as the hours into the day or the night of the DayDefinition day
+	ClockPosition(tm time.Time, day DayDefinition, mode ClockMode) (position ClockPosition, ok bool)
+	// ClockInstant returns the instant of the position, the inverse of ClockPosition
+	ClockInstant(position ClockPosition, day DayDefinition) (tm time.Time, ok bool)
+	// NightBounds and other night shaos zmaniyos, of the night that follows the day
+	//
+	NightBounds(night NightDefinition) (start time.Time, end time.Time, ok bool)
+	ShaahZmanisNight(night NightDefinition) (i gdt.GMillisecond, ok bool)
+	ShaahZmanisNightBasedZman(night NightDefinition, hours gdt.GHourH64) (tm time.Time, ok bool)
+	ChatzosHalayla(night NightDefinition) (tm time.Time, ok bool)
+	SofAshmuraRishona(night NightDefinition) (tm time.Time, ok bool)
+	SofAshmuraShniya(night NightDefinition) (tm time.Time, ok bool)
+	// PreviousDay and NextDay return the ZmanimCalendar of the adjacent civil dates, for the zmanim that straddle them
+	//
+	PreviousDay() ZmanimCalendar
+	NextDay() ZmanimCalendar
 	// SetUseElevation and other setters
 	//
 	SetUseElevation(useElevation bool)
+	SetCandleLightingOffset(candleLightingOffset gdt.GMinuteF64)
+	SetJewishDayOffset(jewishDayOffset int)
 }
 
 type zmanimCalendar struct {
@@ -121,6 +143,27 @@ type zmanimCalendar struct {
 		see CandleLighting
 	*/
 	candleLightingOffset gdt.GMinuteF64
+
+	/*
+		jewishDayOffset the days the Jewish date of IsAssurBemlacha is ahead (1) or behind (-1) of the civil date of the
+		calendar, such as the dateline.HalachicDay.Offset of the locations across the halachic date line, 0 by default.
+	*/
+	jewishDayOffset int
+
+	/*
+		self the calendar that embeds this one, such as the complexZmanimCalendar, nil if it is not embedded, so that
+		the methods of this one that take the calendars of the other dates, such as NextDay, NightBounds and the
+		TemporalClock, return and pass the calendars of the type and the settings of the outer one.
+	*/
+	self dayCalendar
+}
+
+/*
+dayCalendar the ZmanimCalendar that returns the calendar of its type and settings of another date.
+*/
+type dayCalendar interface {
+	ZmanimCalendar
+	calendarOf(gDate gdt.GDate) ZmanimCalendar
 }
 
 func newZmanimCalendar() *zmanimCalendar {
@@ -135,6 +178,13 @@ func NewZmanimCalendar(gDateTime gdt.GDateTime, geoLocation calculator.GeoLocati
 	return t
 }
 
+/*
+NewZmanimCalendar1 returns the ZmanimCalendar of the calculator.Location.
+*/
+func NewZmanimCalendar1(gDateTime gdt.GDateTime, location calculator.Location, astronomicalCalculator calculator.AstronomicalCalculator) ZmanimCalendar {
+	return NewZmanimCalendar(gDateTime, location.GeoLocation(), astronomicalCalculator)
+}
+
 func (t *zmanimCalendar) IsUseElevation() bool {
 	return t.useElevation
 }
@@ -787,6 +837,35 @@ func (t *zmanimCalendar) CandleLightingOffset() gdt.GMinuteF64 {
 	return t.candleLightingOffset
 }
 
+/*
+SetCandleLightingOffset sets the offset in minutes before SeaLevelSunset of CandleLighting, such as 40 for the custom of
+Jerusalem.
+A panic will be if the offset is negative.
+*/
+func (t *zmanimCalendar) SetCandleLightingOffset(candleLightingOffset gdt.GMinuteF64) {
+	if candleLightingOffset < 0 {
+		panic("candleLightingOffset < 0")
+	}
+	t.candleLightingOffset = candleLightingOffset
+}
+
+func (t *zmanimCalendar) JewishDayOffset() int {
+	return t.jewishDayOffset
+}
+
+/*
+SetJewishDayOffset sets the days the Jewish date of IsAssurBemlacha is ahead (1) or behind (-1) of the civil date of the
+calendar, such as the dateline.HalachicDay.Offset, so that the Shabbos and Yom Tov are of the halachic day at the
+locations where the opinions on the halachic date line differ from the civil date, such as Japan and Hawaii.
+A panic will be if the offset is not -1, 0 or 1.
+*/
+func (t *zmanimCalendar) SetJewishDayOffset(jewishDayOffset int) {
+	if jewishDayOffset < -1 || jewishDayOffset > 1 {
+		panic("jewishDayOffset must be -1, 0 or 1")
+	}
+	t.jewishDayOffset = jewishDayOffset
+}
+
 /*
 IsAssurBemlacha is a utility method to determine if the current Date (date-time) passed in
 has a melacha (work) prohibition.
@@ -795,6 +874,8 @@ the tzais for the current day has to be passed to this class.
 Sunset is the classes current day's elevationAdjustedSunset that observes the
 IsUseElevation settings.
 The JewishCalendar.IsInIsrael will be set by the inIsrael parameter.
+The Jewish date is the one of the civil date of this calendar moved by the JewishDayOffset days, see SetJewishDayOffset
+for the halachic date line.
 currentTime is the current time
 tzais the time of tzais
 inIsrael whether to use Israel holiday scheme or not
@@ -804,7 +885,7 @@ see
 - JewishCalendar.HasCandleLighting
 */
 func (t *zmanimCalendar) IsAssurBemlacha(currentTime time.Time, tzais time.Time, inIsrael bool) bool {
-	jewishCalendar := hebrewcalendar.NewJewishCalendar(hebrewcalendar.NewJewishDate2(t.gDateTime.D))
+	jewishCalendar := hebrewcalendar.NewJewishCalendar(hebrewcalendar.NewJewishDate2(addDays(t.gDateTime.D, t.jewishDayOffset)))
 	jewishCalendar.SetInIsrael(inIsrael)
 
 	elevationAdjustedSunset, ok := t.elevationAdjustedSunset()